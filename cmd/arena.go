@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"proj/pkg/history"
+	"proj/pkg/match"
+	"proj/pkg/rating"
+	"proj/pkg/roster"
+	"time"
+)
+
+// arena is the state shared by the interactive menus and the commands: the console they run on,
+// the rules every match is played under, the history every match is recorded in, the roster
+// of saved players and the rating system their matches are rated by.
+type arena struct {
+	*console                // console is where input is read from and output printed to.
+	rules    match.Rules    // rules are the rules every match is played under.
+	history  *history.Store // history records every conducted match.
+	roster   *roster.Roster // roster holds the saved players that can be picked for a match.
+	rater    rating.Rater   // rater rates every match between two roster players.
+}
+
+// newArena creates an arena.
+//
+// Parameters:
+//   - c: The console to read input from and print output to.
+//   - rules: The rules every match is played under.
+//   - store: The match history to record matches in.
+//   - players: The roster of saved players.
+//   - rater: The rating system matches between roster players are rated by.
+//
+// Returns:
+//   - *arena: A pointer to the newly created arena.
+func newArena(c *console, rules match.Rules, store *history.Store, players *roster.Roster, rater rating.Rater) *arena {
+	return &arena{console: c, rules: rules, history: store, roster: players, rater: rater}
+}
+
+// recordMatch appends a conducted match to the match history. Failing to record a match is
+// reported but does not interrupt the session.
+//
+// Parameters:
+//   - m: A pointer to the conducted match.
+func (a *arena) recordMatch(m *match.Match) {
+	record, err := a.history.Append(m, time.Now())
+	if err != nil {
+		fmt.Fprintln(a.out, redColor+"Error recording the match in the history: "+err.Error()+resetColor)
+		return
+	}
+	fmt.Fprintf(a.out, cyanColor+"Recorded as match #%d in the history."+resetColor+"\n", record.ID)
+}
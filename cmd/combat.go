@@ -0,0 +1,165 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"proj/pkg/match"
+	"proj/pkg/strategy"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// playTurnByTurn lets the players of a match choose their action every turn, until the match is
+// over. Players controlled by a strategy have their actions chosen for them, and stunned players
+// lose their turn without being asked. If the input runs out, the rest of the match is played
+// automatically by ConductMatch.
+//
+// Parameters:
+//   - m: A pointer to the match, which must not have been conducted yet.
+//   - strategy1: The strategy controlling Player 1, or nil for a human player.
+//   - strategy2: The strategy controlling Player 2, or nil for a human player.
+func (a *arena) playTurnByTurn(m *match.Match, strategy1, strategy2 match.Strategy) {
+	for !m.Over() {
+		turn := m.NextTurn()
+		fmt.Fprintf(a.out, cyanColor+"Round %d: %s vs %s"+resetColor+"\n", turn.Round, describeFighter(turn.Attacker), describeFighter(turn.Defender))
+
+		// The players take turns, so the starting player plays the odd rounds.
+		controller := strategy1
+		if (turn.Round%2 == 1) != (m.StartingPlayer() == m.PlayerA) {
+			controller = strategy2
+		}
+
+		var action match.Action
+		var err error
+		switch {
+		case turn.Attacker.Stunned():
+			// A stunned player loses their turn whatever they choose.
+			action = match.Action{Kind: match.ActionAttack}
+		case controller != nil:
+			action = controller.Choose(turn)
+		default:
+			action, err = a.chooseAction(turn.Attacker)
+		}
+		if errors.Is(err, io.EOF) {
+			fmt.Fprintln(a.out, yellowColor+"Playing the rest of the match automatically."+resetColor)
+			return
+		}
+		if err != nil {
+			fmt.Fprintln(a.out, redColor+err.Error()+resetColor)
+			continue
+		}
+
+		event, err := m.PlayTurn(action)
+		if err != nil {
+			fmt.Fprintln(a.out, redColor+err.Error()+resetColor)
+			continue
+		}
+		fmt.Fprintln(a.out, event)
+	}
+}
+
+// chooseController asks who controls a player in a match played turn by turn: a human entering
+// their actions, or one of the built-in strategies.
+//
+// Parameters:
+//   - name: The name of the player.
+//
+// Returns:
+//   - match.Strategy: The strategy controlling the player, or nil for a human player.
+//   - error: An error wrapping strategy.ErrUnknownStrategy if no strategy has the entered name,
+//     or an error if the input runs out.
+func (a *arena) chooseController(name string) (match.Strategy, error) {
+	prompt := fmt.Sprintf("Who controls %s? (leave blank for a human player, or enter %s): ", name, strings.Join(strategy.Names, ", "))
+	input, err := a.getStringInput(prompt)
+	if err != nil || input == "" {
+		return nil, err
+	}
+	return strategy.ByName(strings.ToLower(input), time.Now().UnixNano())
+}
+
+// chooseAction asks the player whose turn it is to attack, defend, heal or cast a spell.
+//
+// Parameters:
+//   - f: The state of the player whose turn it is.
+//
+// Returns:
+//   - match.Action: The chosen action, which may still turn out not to be possible.
+//   - error: An error, if the input is invalid or runs out.
+func (a *arena) chooseAction(f match.FighterState) (match.Action, error) {
+	fmt.Fprintln(a.out, yellowColor+"Press 1 to attack, 2 to defend, 3 to heal or 4 to cast a spell"+resetColor)
+	input, err := a.getStringInput(fmt.Sprintf("Action for %s: ", f.Name))
+	if err != nil {
+		return match.Action{}, err
+	}
+
+	switch input {
+	case "1":
+		return match.Action{Kind: match.ActionAttack}, nil
+	case "2":
+		return match.Action{Kind: match.ActionDefend}, nil
+	case "3":
+		return match.Action{Kind: match.ActionHeal}, nil
+	case "4":
+		return a.chooseSpell(f)
+	}
+	return match.Action{}, fmt.Errorf("invalid choice %q. Please enter 1, 2, 3 or 4", input)
+}
+
+// chooseSpell lists the spellbook, marking the spells a player cannot cast right now, and asks
+// them to pick a spell by number or name.
+//
+// Parameters:
+//   - f: The state of the player whose turn it is.
+//
+// Returns:
+//   - match.Action: The action casting the chosen spell.
+//   - error: An error, if the input runs out.
+func (a *arena) chooseSpell(f match.FighterState) (match.Action, error) {
+	spells := match.Spells()
+	for i, spell := range spells {
+		status := ""
+		switch err := f.CanCast(spell); {
+		case errors.Is(err, match.ErrSpellOnCooldown):
+			status = fmt.Sprintf(" (ready in %d turns)", f.Cooldowns[spell.Name])
+		case err != nil:
+			status = " (not enough mana)"
+		}
+		fmt.Fprintf(a.out, "  %d. %s, %d mana: %s%s\n", i+1, spell.Name, spell.Cost, spell.Description, status)
+	}
+
+	choice, err := a.getStringInput("Spell (number or name): ")
+	if err != nil {
+		return match.Action{}, err
+	}
+	if n, err := strconv.Atoi(choice); err == nil && n >= 1 && n <= len(spells) {
+		choice = spells[n-1].Name
+	}
+	return match.Action{Kind: match.ActionCast, Spell: choice}, nil
+}
+
+// describeFighter summarises the state of a player during a match, e.g.
+// "Hero (80/100 health, 12/20 mana, shielded, poison x2 for 3 turns)".
+//
+// Parameters:
+//   - f: The state of the player.
+//
+// Returns:
+//   - string: The summary.
+func describeFighter(f match.FighterState) string {
+	details := []string{fmt.Sprintf("%d/%d health", f.Health, f.StartHealth)}
+	if f.MaxMana > 0 {
+		details = append(details, fmt.Sprintf("%d/%d mana", f.Mana, f.MaxMana))
+	}
+	if f.Shielded {
+		details = append(details, "shielded")
+	}
+	if f.Defending {
+		details = append(details, "defending")
+	}
+	for _, effect := range f.Effects {
+		details = append(details, effect.String())
+	}
+	return fmt.Sprintf("%s (%s)", f.Name, strings.Join(details, ", "))
+}
@@ -0,0 +1,426 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"proj/pkg/match"
+	"proj/pkg/odds"
+	"proj/pkg/player"
+	"proj/pkg/replay"
+	"proj/pkg/roster"
+	"proj/pkg/simulation"
+	"proj/pkg/strategy"
+	"strings"
+	"time"
+)
+
+// Process exit codes of the non-interactive commands.
+const (
+	exitOK      = 0 // exitOK means the command succeeded.
+	exitFailure = 1 // exitFailure means the command ran but its check failed, e.g. a replay diverged.
+	exitUsage   = 2 // exitUsage means the command was invoked incorrectly or its input was invalid.
+)
+
+// command is a non-interactive subcommand of the arena binary.
+type command struct {
+	name    string                            // name is the word that selects the command.
+	usage   string                            // usage shows the arguments of the command.
+	summary string                            // summary describes the command in one line.
+	run     func(a *arena, args []string) int // run executes the command and returns its exit code.
+}
+
+// commands lists every subcommand, in the order they are shown in the usage message.
+var commands = []command{
+	{"fight", "fight --p1 Name:Health:Strength:Attack --p2 Name:Health:Strength:Attack [--ai1 strategy] [--ai2 strategy] [--max-rounds N] [--seed N] [--save file] [--json]", "conduct a single match and print its rounds and result", fightCommand},
+	{"simulate", "simulate --p1 ... --p2 ... [--matches N] [--workers N] [--seed N] [--json]", "conduct many matches in parallel and report win rates", simulateCommand},
+	{"odds", "odds --p1 ... --p2 ... [--json]", "calculate the exact probability of each player winning", oddsCommand},
+	{"replay", "replay <file>", "re-simulate a saved replay file and verify every round", replayMatch},
+	{"history", "history [--player name] [--id N] [--json]", "list recorded matches or show the details of one", historyCommand},
+	{"tournament", "tournament [--format round-robin|single|double|swiss] [--double] [--seeding rating|random|entry] [--rounds N] [--seed N] [--csv file] [--json] [player ...]", "conduct a tournament between the players, or the whole roster", tournamentCommand},
+	{"leaderboard", "leaderboard [--json]", "rank the roster players by rating", leaderboardCommand},
+	{"roster", "roster [list [--json] | add Name:H:S:A | edit <name|ID> Name:H:S:A | delete <name|ID> | import <file> | export [--text] [--out file] <name|ID>]", "list, add, edit, delete, import or export saved players", rosterCommand},
+}
+
+// runCommand runs the subcommand with the given name.
+//
+// Parameters:
+//   - a: A pointer to the arena the command runs in.
+//   - name: The name of the subcommand.
+//   - args: The command-line arguments following the subcommand name.
+//
+// Returns:
+//   - int: The process exit code.
+func runCommand(a *arena, name string, args []string) int {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd.run(a, args)
+		}
+	}
+
+	if name != "help" {
+		fmt.Fprintf(a.out, redColor+"Unknown command %q"+resetColor+"\n", name)
+	}
+	printUsage(a.console)
+	if name == "help" {
+		return exitOK
+	}
+	return exitUsage
+}
+
+// printUsage prints the list of subcommands.
+//
+// Parameters:
+//   - c: The console to print output to.
+func printUsage(c *console) {
+	fmt.Fprintln(c.out, "Usage: arena [-rules file] [-history file] [-roster file] [-rating elo|glicko2] [-script file] [-record file] [command [arguments]]")
+	fmt.Fprintln(c.out, "Without a command, the interactive arena menu is presented. Players are given as")
+	fmt.Fprintln(c.out, "Name:Health:Strength:Attack or as the name or ID of a roster player. Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(c.out, "  %-11s %s\n", cmd.name, cmd.summary)
+		fmt.Fprintf(c.out, "              arena %s\n", cmd.usage)
+	}
+}
+
+// newFlagSet creates the flag set of a command, printing flag errors to the console.
+//
+// Parameters:
+//   - c: The console flag errors are printed to.
+//   - name: The name of the command.
+//
+// Returns:
+//   - *flag.FlagSet: A pointer to the flag set.
+func newFlagSet(c *console, name string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(c.out)
+	return flags
+}
+
+// matchupFlags holds the flags shared by every command that pits two players against each other.
+type matchupFlags struct {
+	flags *flag.FlagSet // flags is the flag set of the command.
+	p1    *string       // p1 is the specification or roster reference of Player 1.
+	p2    *string       // p2 is the specification or roster reference of Player 2.
+	json  *bool         // json selects machine-readable JSON output.
+}
+
+// newMatchupFlags creates the flag set of a command taking two players.
+//
+// Parameters:
+//   - c: The console flag errors are printed to.
+//   - name: The name of the command.
+//
+// Returns:
+//   - *matchupFlags: A pointer to the flags, ready to be extended and parsed.
+func newMatchupFlags(c *console, name string) *matchupFlags {
+	flags := newFlagSet(c, name)
+	return &matchupFlags{
+		flags: flags,
+		p1:    flags.String("p1", "", "Player 1 as Name:Health:Strength:Attack, or a roster name or ID"),
+		p2:    flags.String("p2", "", "Player 2 as Name:Health:Strength:Attack, or a roster name or ID"),
+		json:  flags.Bool("json", false, "print the result as JSON"),
+	}
+}
+
+// parse parses the command-line arguments and the two players they specify, looking roster
+// players up in the arena's roster.
+//
+// Parameters:
+//   - a: A pointer to the arena, whose console errors are printed to.
+//   - args: The command-line arguments following the command name.
+//
+// Returns:
+//   - *player.Player: A pointer to Player 1.
+//   - *player.Player: A pointer to Player 2.
+//   - bool: true if parsing succeeded, false if an error was printed.
+func (m *matchupFlags) parse(a *arena, args []string) (*player.Player, *player.Player, bool) {
+	if err := m.flags.Parse(args); err != nil {
+		return nil, nil, false
+	}
+	if *m.p1 == "" || *m.p2 == "" {
+		fmt.Fprintln(a.out, redColor+"Both --p1 and --p2 are required"+resetColor)
+		return nil, nil, false
+	}
+
+	player1, err := a.resolvePlayer(*m.p1)
+	if err != nil {
+		fmt.Fprintln(a.out, redColor+err.Error()+resetColor)
+		return nil, nil, false
+	}
+	player2, err := a.resolvePlayer(*m.p2)
+	if err != nil {
+		fmt.Fprintln(a.out, redColor+err.Error()+resetColor)
+		return nil, nil, false
+	}
+	return player1, player2, true
+}
+
+// printJSON prints a value as indented JSON.
+//
+// Parameters:
+//   - c: The console to print output to.
+//   - v: The value to print.
+//
+// Returns:
+//   - int: exitOK, or exitFailure if the value cannot be encoded.
+func printJSON(c *console, v any) int {
+	encoder := json.NewEncoder(c.out)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		fmt.Fprintln(c.out, redColor+"Error encoding JSON: "+err.Error()+resetColor)
+		return exitFailure
+	}
+	return exitOK
+}
+
+// fightResult is the JSON output of the fight command.
+type fightResult struct {
+	Result       string             `json:"result"`            // Result is the match result, e.g. "Hero wins".
+	Reason       string             `json:"reason"`            // Reason is how the match ended: win, timeout or draw.
+	Winner       string             `json:"winner"`            // Winner is the name of the winner, empty for a draw.
+	Rounds       int                `json:"rounds"`            // Rounds is the number of rounds played.
+	FinalHealth1 int                `json:"finalHealth1"`      // FinalHealth1 is Player 1's health at the end of the match.
+	FinalHealth2 int                `json:"finalHealth2"`      // FinalHealth2 is Player 2's health at the end of the match.
+	Seed         int64              `json:"seed"`              // Seed is the seed of the match dice.
+	HistoryID    int                `json:"historyId"`         // HistoryID is the ID of the match in the history.
+	Ratings      []roster.Entry     `json:"ratings,omitempty"` // Ratings are the new ratings of both players, if they are roster players.
+	Events       []match.RoundEvent `json:"events"`            // Events are the events of every round.
+}
+
+// fightCommand conducts a single match between the players given on the command line, records
+// it in the match history and, if both are roster players, updates their ratings.
+//
+// Parameters:
+//   - a: A pointer to the arena, whose rules the match is played under and whose history records it.
+//   - args: The command-line arguments following "fight".
+//
+// Returns:
+//   - int: exitOK if the match was conducted, exitFailure if the replay, history or ratings cannot be written,
+//     exitUsage if the arguments or players are invalid.
+func fightCommand(a *arena, args []string) int {
+	m := newMatchupFlags(a.console, "fight")
+	seed := m.flags.Int64("seed", time.Now().UnixNano(), "seed of the match dice")
+	save := m.flags.String("save", "", "save a replay of the match to this file")
+	ai1 := m.flags.String("ai1", "", "strategy choosing Player 1's actions: "+strings.Join(strategy.Names, ", ")+" (default: cast a spell whenever one is worth casting, otherwise attack)")
+	ai2 := m.flags.String("ai2", "", "strategy choosing Player 2's actions, like --ai1")
+	maxRounds := m.flags.Int("max-rounds", 0, fmt.Sprintf("rounds after which the tie-break decides the match (default: maxRounds of the rules, or %d if they set none)", match.SafetyRounds))
+	player1, player2, ok := m.parse(a, args)
+	if !ok {
+		return exitUsage
+	}
+
+	var strategies [2]match.Strategy
+	for i, name := range []string{*ai1, *ai2} {
+		if name == "" {
+			continue
+		}
+		s, err := strategy.ByName(name, *seed+int64(i))
+		if err != nil {
+			fmt.Fprintln(a.out, redColor+err.Error()+resetColor)
+			return exitUsage
+		}
+		strategies[i] = s
+	}
+
+	// A fight always ends: players who heal or defend faster than they hurt each other would
+	// otherwise play forever.
+	rules := a.rules
+	if *maxRounds != 0 {
+		rules.MaxRounds = *maxRounds
+	}
+	currentMatch, err := match.NewMatch(player1, player2, match.WithSeed(*seed), match.WithRules(rules.WithSafetyLimit()), match.WithStrategies(strategies[0], strategies[1]))
+	if err != nil {
+		fmt.Fprintln(a.out, redColor+describeValidationError(err)+resetColor)
+		return exitUsage
+	}
+	roundResults, outcome := match.ConductMatch(currentMatch)
+
+	if *save != "" {
+		if err := replay.Save(*save, currentMatch); err != nil {
+			fmt.Fprintln(a.out, redColor+"Error saving replay: "+err.Error()+resetColor)
+			return exitFailure
+		}
+	}
+
+	record, err := a.history.Append(currentMatch, time.Now())
+	if err != nil {
+		fmt.Fprintln(a.out, redColor+"Error recording the match in the history: "+err.Error()+resetColor)
+		return exitFailure
+	}
+	rated, err := a.rateMatch(currentMatch)
+	if err != nil {
+		fmt.Fprintln(a.out, redColor+"Error updating the ratings: "+err.Error()+resetColor)
+		return exitFailure
+	}
+
+	if *m.json {
+		result := fightResult{
+			Result:       outcome.String(),
+			Reason:       outcome.Reason.String(),
+			Rounds:       outcome.Rounds,
+			FinalHealth1: outcome.FinalHealthA,
+			FinalHealth2: outcome.FinalHealthB,
+			Seed:         currentMatch.Seed(),
+			HistoryID:    record.ID,
+			Ratings:      rated,
+			Events:       currentMatch.Events(),
+		}
+		if outcome.Winner != nil {
+			result.Winner, _, _, _ = player.GetPlayerBaseAttributes(outcome.Winner)
+		}
+		return printJSON(a.console, result)
+	}
+
+	for i, roundResult := range roundResults {
+		fmt.Fprintf(a.out, "Round %d: %s\n", i+1, roundResult)
+	}
+	fmt.Fprintln(a.out, greenColor+"Match result: "+outcome.String()+resetColor)
+	fmt.Fprintf(a.out, cyanColor+"Recorded as match #%d in the history."+resetColor+"\n", record.ID)
+	for _, entry := range rated {
+		fmt.Fprintf(a.out, cyanColor+"%s is now rated %s."+resetColor+"\n", entry.Name, entry.Rating)
+	}
+	return exitOK
+}
+
+// simulateCommand conducts many matches between the players given on the command line and
+// reports the aggregated outcomes.
+//
+// Parameters:
+//   - a: A pointer to the arena, whose rules every match is played under.
+//   - args: The command-line arguments following "simulate".
+//
+// Returns:
+//   - int: exitOK if the simulation ran, exitUsage if the arguments or players are invalid.
+func simulateCommand(a *arena, args []string) int {
+	m := newMatchupFlags(a.console, "simulate")
+	matches := m.flags.Int("matches", 10000, "number of matches to conduct")
+	workers := m.flags.Int("workers", 0, "number of parallel workers (default: number of CPUs)")
+	seed := m.flags.Int64("seed", time.Now().UnixNano(), "seed of the simulation")
+	player1, player2, ok := m.parse(a, args)
+	if !ok {
+		return exitUsage
+	}
+
+	report, err := simulation.Run(player1, player2, simulation.Config{Matches: *matches, Workers: *workers, Seed: *seed, Rules: a.rules})
+	if err != nil {
+		fmt.Fprintln(a.out, redColor+describeValidationError(err)+resetColor)
+		return exitUsage
+	}
+
+	if *m.json {
+		return printJSON(a.console, report)
+	}
+
+	name1, _, _, _ := player.GetPlayerBaseAttributes(player1)
+	name2, _, _, _ := player.GetPlayerBaseAttributes(player2)
+	fmt.Fprintf(a.out, "Matches:     %d\n", report.Matches)
+	fmt.Fprintf(a.out, "%s wins: %d (%.2f%%)\n", name1, report.WinsA, report.WinRateA()*100)
+	fmt.Fprintf(a.out, "%s wins: %d (%.2f%%)\n", name2, report.WinsB, report.WinRateB()*100)
+	fmt.Fprintf(a.out, "Draws:       %d (%.2f%%)\n", report.Draws, report.DrawRate()*100)
+	fmt.Fprintf(a.out, "Mean rounds: %.2f\n", report.MeanRounds())
+	return exitOK
+}
+
+// oddsResult is the JSON output of the odds command.
+type oddsResult struct {
+	Win1           float64 `json:"win1"`           // Win1 is the probability that Player 1 wins.
+	Win2           float64 `json:"win2"`           // Win2 is the probability that Player 2 wins.
+	ExpectedRounds float64 `json:"expectedRounds"` // ExpectedRounds is the expected length of the match.
+}
+
+// oddsCommand calculates the exact odds of a match between the players given on the command line.
+//
+// Parameters:
+//   - a: A pointer to the arena, whose rules the match would be played under.
+//   - args: The command-line arguments following "odds".
+//
+// Returns:
+//   - int: exitOK if the odds were calculated, exitUsage if the arguments or players are invalid.
+func oddsCommand(a *arena, args []string) int {
+	m := newMatchupFlags(a.console, "odds")
+	player1, player2, ok := m.parse(a, args)
+	if !ok {
+		return exitUsage
+	}
+
+	o, err := odds.Calculate(player1, player2, a.rules)
+	if errors.Is(err, odds.ErrStateSpaceTooLarge) || errors.Is(err, odds.ErrSpellcaster) {
+		fmt.Fprintln(a.out, redColor+err.Error()+"; use the simulate command instead"+resetColor)
+		return exitUsage
+	}
+	if err != nil {
+		fmt.Fprintln(a.out, redColor+describeValidationError(err)+resetColor)
+		return exitUsage
+	}
+
+	if *m.json {
+		return printJSON(a.console, oddsResult{o.WinA, o.WinB, o.ExpectedRounds})
+	}
+
+	name1, _, _, _ := player.GetPlayerBaseAttributes(player1)
+	name2, _, _, _ := player.GetPlayerBaseAttributes(player2)
+	fmt.Fprintf(a.out, "%s wins: %.4f%%\n", name1, o.WinA*100)
+	fmt.Fprintf(a.out, "%s wins: %.4f%%\n", name2, o.WinB*100)
+	fmt.Fprintf(a.out, "Expected rounds: %.2f\n", o.ExpectedRounds)
+	return exitOK
+}
+
+// replayMatch loads the replay file named in args, re-simulates the match it describes and
+// verifies that every round plays out exactly as recorded, reporting any divergence.
+//
+// The replay is re-simulated under the rules recorded in the file, not the rules of the arena.
+//
+// Parameters:
+//   - a: A pointer to the arena the command runs in.
+//   - args: The command-line arguments following "replay"; exactly one replay file path is expected.
+//
+// Returns:
+//   - int: The process exit code: exitOK if the replay verified, exitFailure if it diverged, exitUsage on usage or file errors.
+func replayMatch(a *arena, args []string) int {
+	if len(args) != 1 {
+		fmt.Fprintln(a.out, redColor+"Usage: replay <file>"+resetColor)
+		return exitUsage
+	}
+
+	r, err := replay.Load(args[0])
+	if err != nil {
+		fmt.Fprintln(a.out, redColor+"Error loading replay: "+err.Error()+resetColor)
+		return exitUsage
+	}
+
+	fmt.Fprintf(a.out, cyanColor+"Replaying %s vs %s (seed %d, %d rounds)\n"+resetColor, r.Players[0].Name, r.Players[1].Name, r.Seed, len(r.Events))
+	verification, err := r.Verify()
+	if err != nil {
+		fmt.Fprintln(a.out, redColor+"Error replaying match: "+err.Error()+resetColor)
+		return exitUsage
+	}
+	for _, divergence := range verification.Divergences {
+		fmt.Fprintf(a.out, redColor+"Round %d diverged:\n"+resetColor, divergence.Round)
+		fmt.Fprintln(a.out, yellowColor+"  recorded: "+describeEvent(divergence.Recorded)+resetColor)
+		fmt.Fprintln(a.out, yellowColor+"  replayed: "+describeEvent(divergence.Replayed)+resetColor)
+	}
+	if verification.RecordedResult != verification.ReplayedResult {
+		fmt.Fprintf(a.out, redColor+"Result diverged: recorded %q, replayed %q\n"+resetColor, verification.RecordedResult, verification.ReplayedResult)
+	}
+
+	if !verification.OK() {
+		return exitFailure
+	}
+	fmt.Fprintln(a.out, greenColor+"Replay verified: "+verification.ReplayedResult+resetColor)
+	return exitOK
+}
+
+// describeEvent renders an optional round event for divergence reports.
+//
+// Parameters:
+//   - event: A pointer to the round event, or nil if the round does not exist.
+//
+// Returns:
+//   - string: A description of the event including its dice rolls.
+func describeEvent(event *match.RoundEvent) string {
+	if event == nil {
+		return "(no such round)"
+	}
+	return fmt.Sprintf("%s (rolls %d vs %d, %s health %d -> %d)", event, event.AttackRoll, event.DefenceRoll, event.Defender, event.DefenderHealthBefore, event.DefenderHealthAfter)
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"bufio"
+	"io"
+)
+
+// console is the terminal the arena is played on: the reader user input is taken from and
+// the writer all output is printed to. Injecting both lets a session be scripted end-to-end.
+type console struct {
+	in  *bufio.Reader // in buffers user input for the whole session, so piped input is never lost.
+	out io.Writer     // out receives every prompt and message.
+}
+
+// newConsole creates a console reading user input from in and printing output to out.
+//
+// Parameters:
+//   - in: The source of user input.
+//   - out: The destination of output.
+//
+// Returns:
+//   - *console: A pointer to the newly created console.
+func newConsole(in io.Reader, out io.Writer) *console {
+	return &console{in: bufio.NewReader(in), out: out}
+}
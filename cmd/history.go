@@ -0,0 +1,135 @@
+package main
+
+import (
+	"fmt"
+	"proj/pkg/history"
+	"time"
+)
+
+// viewHistory presents the match history: it lists past matches, optionally only those of one
+// player, and shows the details of any listed match the user picks.
+func (a *arena) viewHistory() {
+	records, err := a.history.List()
+	if err != nil {
+		fmt.Fprintln(a.out, redColor+"Error reading the match history: "+err.Error()+resetColor)
+		return
+	}
+
+	name, err := a.getStringInput("Filter by player name (leave blank for all matches): ")
+	if err != nil {
+		return
+	}
+	if name != "" {
+		records = history.FilterByPlayer(records, name)
+	}
+	if len(records) == 0 {
+		fmt.Fprintln(a.out, yellowColor+"No matches recorded."+resetColor)
+		return
+	}
+
+	for _, record := range records {
+		printRecordSummary(a.console, record)
+	}
+
+	for {
+		id, err := a.getUserInput("Enter a match ID to show its details or press 0 to return: ")
+		if err != nil || id == 0 {
+			return
+		}
+
+		record, err := a.history.Get(id)
+		if err != nil {
+			fmt.Fprintln(a.out, redColor+err.Error()+resetColor)
+			continue
+		}
+		printRecordDetails(a.console, record)
+	}
+}
+
+// printRecordSummary prints a one-line summary of a recorded match.
+//
+// Parameters:
+//   - c: The console to print output to.
+//   - record: The recorded match.
+func printRecordSummary(c *console, record history.Record) {
+	fmt.Fprintf(c.out, "#%-4d %s  %s vs %s: %s in %d rounds\n",
+		record.ID, record.Timestamp.Local().Format(time.DateTime), record.Players[0].Name, record.Players[1].Name, record.Result, record.Rounds)
+}
+
+// printRecordDetails prints the players, outcome and every round of a recorded match.
+//
+// Parameters:
+//   - c: The console to print output to.
+//   - record: The recorded match.
+func printRecordDetails(c *console, record history.Record) {
+	fmt.Fprintf(c.out, cyanColor+"Match #%d, played %s (seed %d)"+resetColor+"\n", record.ID, record.Timestamp.Local().Format(time.DateTime), record.Seed)
+	for i, p := range record.Players {
+		extra := ""
+		if p.Agility != 0 {
+			extra += fmt.Sprintf(", agility %d", p.Agility)
+		}
+		if p.Class != "" {
+			extra += ", class " + p.Class
+		}
+		if p.Mana != 0 {
+			extra += fmt.Sprintf(", mana %d", p.Mana)
+		}
+		fmt.Fprintf(c.out, "  %s: health %d, strength %d, attack %d%s, finished with %d health\n", p.Name, p.Health, p.Strength, p.Attack, extra, record.FinalHealth[i])
+	}
+	for _, event := range record.Events {
+		fmt.Fprintf(c.out, "  Round %d: %s\n", event.Round, event)
+	}
+	fmt.Fprintln(c.out, greenColor+"Match result: "+record.Result+" ("+record.Reason+")"+resetColor)
+}
+
+// historyCommand lists recorded matches, optionally only those of one player, or shows the
+// details of a single match.
+//
+// Parameters:
+//   - a: A pointer to the arena whose match history is shown.
+//   - args: The command-line arguments following "history".
+//
+// Returns:
+//   - int: exitOK if the history was shown, exitFailure if it cannot be read or the match is not
+//     found, exitUsage if the arguments are invalid.
+func historyCommand(a *arena, args []string) int {
+	flags := newFlagSet(a.console, "history")
+	name := flags.String("player", "", "only list matches this player took part in")
+	id := flags.Int("id", 0, "show the details of the match with this ID")
+	asJSON := flags.Bool("json", false, "print the matches as JSON")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	if *id != 0 {
+		record, err := a.history.Get(*id)
+		if err != nil {
+			fmt.Fprintln(a.out, redColor+err.Error()+resetColor)
+			return exitFailure
+		}
+		if *asJSON {
+			return printJSON(a.console, record)
+		}
+		printRecordDetails(a.console, record)
+		return exitOK
+	}
+
+	records, err := a.history.List()
+	if err != nil {
+		fmt.Fprintln(a.out, redColor+"Error reading the match history: "+err.Error()+resetColor)
+		return exitFailure
+	}
+	if *name != "" {
+		records = history.FilterByPlayer(records, *name)
+	}
+	if *asJSON {
+		if records == nil {
+			records = []history.Record{}
+		}
+		return printJSON(a.console, records)
+	}
+	for _, record := range records {
+		printRecordSummary(a.console, record)
+	}
+	return exitOK
+}
@@ -1,11 +1,17 @@
 package main
 
 import (
-	"bufio"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"os"
+	"proj/pkg/history"
 	"proj/pkg/match"
 	"proj/pkg/player"
+	"proj/pkg/rating"
+	"proj/pkg/replay"
+	"proj/pkg/roster"
 	"strconv"
 	"strings"
 )
@@ -30,48 +36,154 @@ const (
 // The color-coded console output enhances the visual experience, and the application logic is
 // structured to handle various user inputs and scenarios. The ManageMatchesInArena function is
 // responsible for handling the process of entering, conducting, and managing matches within the arena.
+//
+// Running the application with a subcommand, such as "arena fight --p1 Hero:100:10:5 --p2 Villain:50:5:2",
+// runs that command non-interactively instead of presenting the menu; see runCommand. The
+// "-rules <file>" flag plays every match under the rules loaded from a JSON file instead of the
+// default rules; see match.LoadRules.
+//
+// The "-record <file>" flag saves everything typed during a session to a file, and the
+// "-script <file>" flag plays such a file back as the session's input, end-to-end.
+// Every match is recorded in the match history file, which "-history <file>" relocates, and
+// saved players are kept in the roster file, which "-roster <file>" relocates. Matches between
+// roster players update their ratings under the rating system chosen with "-rating <system>".
+// The "-classes <file>" flag adds the character classes defined in a JSON file to the built-in
+// ones; see player.LoadClasses.
 func main() {
+	rulesPath := flag.String("rules", "", "path to a JSON file with the match rules")
+	scriptPath := flag.String("script", "", "path to a file of recorded input to play back instead of reading standard input")
+	recordPath := flag.String("record", "", "path to a file to record the session's input to, for later use with -script")
+	historyPath := flag.String("history", "", "path to the match history file (default: in the user configuration directory)")
+	rosterPath := flag.String("roster", "", "path to the player roster file (default: in the user configuration directory)")
+	ratingSystem := flag.String("rating", "glicko2", "rating system for roster players: "+strings.Join(rating.Systems, " or "))
+	classesPath := flag.String("classes", "", "path to a JSON file with more character classes")
+	flag.Parse()
+
+	c := newConsole(os.Stdin, os.Stdout)
+
+	// Classes are loaded before the roster, whose players may belong to them.
+	if *classesPath != "" {
+		if err := player.LoadClasses(*classesPath); err != nil {
+			fmt.Fprintln(c.out, redColor+"Error loading classes: "+err.Error()+resetColor)
+			os.Exit(exitUsage)
+		}
+	}
+
+	if *historyPath == "" {
+		var err error
+		*historyPath, err = history.DefaultPath()
+		if err != nil {
+			fmt.Fprintln(c.out, redColor+"Error locating the match history: "+err.Error()+resetColor)
+			os.Exit(exitUsage)
+		}
+	}
+	store := history.Open(*historyPath)
+
+	if *rosterPath == "" {
+		var err error
+		*rosterPath, err = roster.DefaultPath()
+		if err != nil {
+			fmt.Fprintln(c.out, redColor+"Error locating the roster: "+err.Error()+resetColor)
+			os.Exit(exitUsage)
+		}
+	}
+	rater, err := rating.ByName(*ratingSystem)
+	if err != nil {
+		fmt.Fprintln(c.out, redColor+err.Error()+resetColor)
+		os.Exit(exitUsage)
+	}
+	players, err := roster.Load(*rosterPath)
+	if err != nil {
+		fmt.Fprintln(c.out, redColor+"Error loading the roster: "+err.Error()+resetColor)
+		os.Exit(exitFailure)
+	}
+
+	rules := match.DefaultRules()
+	if *rulesPath != "" {
+		var err error
+		rules, err = match.LoadRules(*rulesPath)
+		if err != nil {
+			fmt.Fprintln(c.out, redColor+"Error loading rules: "+err.Error()+resetColor)
+			os.Exit(exitUsage)
+		}
+	}
+
+	if flag.NArg() > 0 {
+		os.Exit(runCommand(newArena(c, rules, store, players, rater), flag.Arg(0), flag.Args()[1:]))
+	}
+
+	switch {
+	case *scriptPath != "":
+		script, err := os.Open(*scriptPath)
+		if err != nil {
+			fmt.Fprintln(c.out, redColor+"Error opening script: "+err.Error()+resetColor)
+			os.Exit(exitUsage)
+		}
+		defer script.Close()
+		c = newConsole(script, os.Stdout)
+	case *recordPath != "":
+		recording, err := os.Create(*recordPath)
+		if err != nil {
+			fmt.Fprintln(c.out, redColor+"Error creating recording: "+err.Error()+resetColor)
+			os.Exit(exitUsage)
+		}
+		defer recording.Close()
+		c = newConsole(io.TeeReader(os.Stdin, recording), os.Stdout)
+	}
+
+	newArena(c, rules, store, players, rater).run()
+}
+
+// run presents the main menu on the console until the user exits or the input runs out.
+func (a *arena) run() {
 	for {
-		fmt.Println(cyanColor + "Welcome to Magical Arena 1.0!" + resetColor)
-		fmt.Println(magentaColor + "Press 1 to enter the arena or press 0 to exit" + resetColor)
+		fmt.Fprintln(a.out, cyanColor+"Welcome to Magical Arena 1.0!"+resetColor)
+		fmt.Fprintln(a.out, magentaColor+"Press 1 to enter the arena, 2 to view the match history, 3 to manage the roster, 4 to view the leaderboard or press 0 to exit"+resetColor)
 
-		choice, err := getUserInput("Enter your choice: ")
+		choice, err := a.getUserInput("Enter your choice: ")
+		if errors.Is(err, io.EOF) {
+			return
+		}
 		if err != nil {
-			fmt.Println(redColor + "Please enter a valid choice or press 0 to exit" + resetColor)
+			fmt.Fprintln(a.out, redColor+"Please enter a valid choice or press 0 to exit"+resetColor)
 			continue
 		}
 
 		switch choice {
 		case 0:
-			fmt.Println(redColor + "Exiting the application. Goodbye!" + resetColor)
+			fmt.Fprintln(a.out, redColor+"Exiting the application. Goodbye!"+resetColor)
 			return
 		case 1:
-			fmt.Println(magentaColor + "Entering the arena..." + resetColor)
-			fmt.Println(cyanColor + "Welcome to the arena!" + resetColor)
-			fmt.Println(yellowColor + "Press 1 to teleport into matches or press 0 to exit" + resetColor)
+			fmt.Fprintln(a.out, magentaColor+"Entering the arena..."+resetColor)
+			fmt.Fprintln(a.out, cyanColor+"Welcome to the arena!"+resetColor)
+			fmt.Fprintln(a.out, yellowColor+"Press 1 to teleport into matches or press 0 to exit"+resetColor)
 
 			// Take user input to enter a match or exit the application
-			choice, err = getUserInput("Enter your choice: ")
+			choice, err = a.getUserInput("Enter your choice: ")
 
 			if err != nil {
-				fmt.Println(redColor + "Error reading user input: " + err.Error() + resetColor)
+				fmt.Fprintln(a.out, redColor+"Error reading user input: "+err.Error()+resetColor)
 				continue
 			}
 
-			// Entering inside matches
-			if choice == 1 {
-				// This function will handle the logic of starting matches and concluding them
-				ManageMatchesInArena()
-			}
-
-			// Handled arena exiting logic
-			if choice == 0 {
-				fmt.Println(magentaColor + "Exiting the arena." + resetColor)
-			} else {
-				fmt.Println(redColor + "Invalid choice. Returning to the main menu." + resetColor)
+			switch choice {
+			case 1:
+				// Entering inside matches, this function will handle the logic of starting matches and concluding them
+				a.ManageMatchesInArena()
+			case 0:
+				// Handled arena exiting logic
+				fmt.Fprintln(a.out, magentaColor+"Exiting the arena."+resetColor)
+			default:
+				fmt.Fprintln(a.out, redColor+"Invalid choice. Returning to the main menu."+resetColor)
 			}
+		case 2:
+			a.viewHistory()
+		case 3:
+			a.manageRoster()
+		case 4:
+			printLeaderboard(a.console, a.rater, a.roster.Leaderboard(a.rater))
 		default:
-			fmt.Println(redColor + "Invalid choice. Please enter 0 or 1." + resetColor)
+			fmt.Fprintln(a.out, redColor+"Invalid choice. Please enter 0, 1, 2, 3 or 4."+resetColor)
 		}
 	}
 }
@@ -86,8 +198,8 @@ func main() {
 // Returns:
 //   - int: The parsed integer choice.
 //   - error: An error, if any.
-func getUserInput(prompt string) (int, error) {
-	input, err := getStringInput(prompt)
+func (c *console) getUserInput(prompt string) (int, error) {
+	input, err := c.getStringInput(prompt)
 	if err != nil {
 		return 0, err
 	}
@@ -103,127 +215,144 @@ func getUserInput(prompt string) (int, error) {
 
 // ManageMatchesInArena initiates the process for entering and conducting matches in the arena.
 //
-// This function presents the user with options to either enter a new match or exit the arena.
-// It prompts the user for input and creates Player instances for both participants.
-// The function then validates the attributes of both players and proceeds to create and conduct a new match.
-// The match result and round records are stored in a map, and the match number is incremented for each new match.
+// This function presents the user with options to either enter a new match, played automatically
+// or turn by turn, or exit the arena. In a match played turn by turn, each player chooses to
+// attack, defend, heal or cast a spell on their turn.
+// It prompts the user to pick both participants from the roster or to enter their attributes.
+// The function then creates a new match, which validates the attributes of both players, and conducts it.
+// Every conducted match is recorded in the match history, where it can be viewed later, and
+// a match between two roster players updates their ratings.
 //
 // The function continues running until the user chooses to exit the matches section by entering 0.
 //
+// Every match is played under the rules of the arena.
+//
 // Example:
 //
-//	ManageMatchesInArena()
+//	a.ManageMatchesInArena()
 //
-// Note: Ensure that the necessary color constants, getUserInput, getPlayerAttributes, isValidPlayerAttributes,
+// Note: Ensure that the necessary color constants, getUserInput, getPlayerAttributes, describeValidationError,
 // and match packages are correctly imported and defined for the proper functioning of this function.
-func ManageMatchesInArena() {
-	matchRecords := make(map[int]string)
-	matchNo := 1
-
+func (a *arena) ManageMatchesInArena() {
 	for {
-		fmt.Println(yellowColor + "Press 1 to start a match or press 0 to exit the arena" + resetColor)
+		fmt.Fprintln(a.out, yellowColor+"Press 1 to start a match, 2 to play a match turn by turn or press 0 to exit the arena"+resetColor)
 
-		choice, err := getUserInput("Enter your choice: ")
+		choice, err := a.getUserInput("Enter your choice: ")
 		if err != nil {
-			fmt.Println(redColor + "Please enter a valid choice or press 0 to exit" + resetColor)
+			fmt.Fprintln(a.out, redColor+"Please enter a valid choice or press 0 to exit"+resetColor)
 			return
 		}
 
 		switch choice {
 		case 0:
-			fmt.Println(magentaColor + "Exiting the matches section." + resetColor)
+			fmt.Fprintln(a.out, magentaColor+"Exiting the matches section."+resetColor)
 			return
-		case 1:
-			fmt.Println(cyanColor + "Entering a new match..." + resetColor)
+		case 1, 2:
+			fmt.Fprintln(a.out, cyanColor+"Entering a new match..."+resetColor)
 
-			player1, err := getPlayerAttributes("Player 1")
+			player1, err := a.choosePlayer("Player 1")
 			if err != nil {
-				fmt.Println(redColor + "Error creating Player 1: " + err.Error() + resetColor)
+				fmt.Fprintln(a.out, redColor+"Error creating Player 1: "+err.Error()+resetColor)
 				continue
 			}
 
-			player2, err := getPlayerAttributes("Player 2")
+			player2, err := a.choosePlayer("Player 2")
 			if err != nil {
-				fmt.Println(redColor + "Error creating Player 2: " + err.Error() + resetColor)
+				fmt.Fprintln(a.out, redColor+"Error creating Player 2: "+err.Error()+resetColor)
 				continue
 			}
 
-			// Validate players attributes
-			if !isValidPlayerAttributes(player1, player2) {
-				continue
+			// Players of a match played turn by turn may be controlled by a strategy
+			var strategy1, strategy2 match.Strategy
+			if choice == 2 {
+				name1, _, _, _ := player.GetPlayerBaseAttributes(player1)
+				if strategy1, err = a.chooseController(name1); err != nil {
+					fmt.Fprintln(a.out, redColor+err.Error()+resetColor)
+					continue
+				}
+				name2, _, _, _ := player.GetPlayerBaseAttributes(player2)
+				if strategy2, err = a.chooseController(name2); err != nil {
+					fmt.Fprintln(a.out, redColor+err.Error()+resetColor)
+					continue
+				}
 			}
 
-			// Create a new match
-			currentMatch := match.NewMatch(player1, player2)
+			// Create a new match, which validates the players attributes
+			currentMatch, err := match.NewMatch(player1, player2, match.WithRules(a.rules), match.WithStrategies(strategy1, strategy2))
+			if err != nil {
+				fmt.Fprintln(a.out, redColor+describeValidationError(err)+resetColor)
+				continue
+			}
 
-			// Conducting the match
-			_, matchResult := match.ConductMatch(currentMatch)
+			// Conducting the match, after the players have taken their turns if they play turn by turn
+			if choice == 2 {
+				a.playTurnByTurn(currentMatch, strategy1, strategy2)
+			}
+			_, outcome := match.ConductMatch(currentMatch)
+			matchResult := outcome.String()
 
-			// Storing the match result and match round records in map
-			matchRecords[matchNo] = matchResult
+			fmt.Fprintln(a.out, greenColor+"Match result: "+matchResult+resetColor)
 
-			// Incrementing the match number
-			matchNo++
+			// Storing the match in the match history
+			a.recordMatch(currentMatch)
+			a.reportRatings(currentMatch)
 
-			fmt.Println(greenColor + "Match result: " + matchResult + resetColor)
+			saveReplay(a.console, currentMatch)
 		default:
-			fmt.Println(redColor + "Invalid choice. Please enter 0 or 1." + resetColor)
+			fmt.Fprintln(a.out, redColor+"Invalid choice. Please enter 0, 1 or 2."+resetColor)
 		}
 	}
 }
 
-// isValidPlayerAttributes checks if the attributes of two players are within valid ranges to proceed with a match.
-// It compares the attack strength of one player against the health of the other player, considering specific conditions.
+// saveReplay offers to save a conducted match to a replay file, so that it can be re-simulated
+// later with the replay command. Entering an empty file name skips saving.
 //
 // Parameters:
-//   - player1: The first player.
-//   - player2: The second player.
-//
-// Returns:
-//   - bool: True if the attributes are within valid ranges, false otherwise.
-func isValidPlayerAttributes(player1, player2 *player.Player) bool {
-	playerName1, playerHealth1, playerStrength1, playerAttack1 := player.GetPlayerBaseAttributes(player1)
-	playerName2, playerHealth2, playerStrength2, playerAttack2 := player.GetPlayerBaseAttributes(player2)
-
-	// Check for unique names of players
-	if playerName1 == playerName2 {
-		fmt.Println(redColor + "Player names must be unique." + resetColor)
-		return false
-	}
-
-	// Check for health must be greater than 0
-	if playerHealth1 <= 0 || playerHealth2 <= 0 {
-		fmt.Println(redColor + "Player health must be greater than 0." + resetColor)
-		return false
+//   - currentMatch: A pointer to the conducted match.
+func saveReplay(c *console, currentMatch *match.Match) {
+	path, err := c.getStringInput("Enter a file name to save the replay (leave blank to skip): ")
+	if err != nil || path == "" {
+		return
 	}
 
-	// Check for strength must be greater than 0
-	if playerStrength1 <= 0 || playerStrength2 <= 0 {
-		fmt.Println(redColor + "Player strength must be greater than 0." + resetColor)
-		return false
-	}
-
-	// Check for attack must be greater than 0
-	if playerAttack1 <= 0 || playerAttack2 <= 0 {
-		fmt.Println(redColor + "Player attack must be greater than 0." + resetColor)
-		return false
-	}
-
-	// Check for attack conditions must be following certain conditions
-	if playerAttack1*6 <= playerStrength2 {
-		fmt.Println(redColor + "Player 1 attack is too low to damage Player 2." + resetColor)
-		return false
+	if err := replay.Save(path, currentMatch); err != nil {
+		fmt.Fprintln(c.out, redColor+"Error saving replay: "+err.Error()+resetColor)
+		return
 	}
+	fmt.Fprintln(c.out, greenColor+"Replay saved to "+path+resetColor)
+}
 
-	if playerAttack2*6 <= playerStrength1 {
-		fmt.Println(redColor + "Player 2 attack is too low to damage Player 1." + resetColor)
-		return false
+// describeValidationError renders an error returned by match.NewMatch as a message for the user.
+//
+// Parameters:
+//   - err: The error returned by match.NewMatch.
+//
+// Returns:
+//   - string: A message explaining why the players cannot enter a match.
+func describeValidationError(err error) string {
+	switch {
+	case errors.Is(err, match.ErrDuplicateName):
+		return "Player names must be unique."
+	case errors.Is(err, player.ErrEmptyName):
+		return "Player names must not be empty."
+	case errors.Is(err, player.ErrNonPositiveHealth):
+		return "Player health must be greater than 0."
+	case errors.Is(err, player.ErrNonPositiveStrength):
+		return "Player strength must be greater than 0."
+	case errors.Is(err, player.ErrNonPositiveAttack):
+		return "Player attack must be greater than 0."
+	case errors.Is(err, player.ErrInvalidAgility):
+		return fmt.Sprintf("Player agility must be between 0 and %d.", player.MaxAgility)
+	case errors.Is(err, player.ErrNegativeMana):
+		return "Player mana must not be negative."
+	case errors.Is(err, match.ErrAttackCannotPenetrate):
+		return "Player attack is too low to damage the opponent (" + err.Error() + ")."
 	}
-
-	return true
+	return "Invalid match: " + err.Error()
 }
 
-// getPlayerAttributes prompts the user to enter attributes for a player and returns a new Player instance.
+// getPlayerAttributes prompts the user to enter attributes for a player, including an optional
+// agility and mana pool, and to pick their class from the known classes, and returns a new Player instance.
 //
 // Parameters:
 //   - playerName: The name of the player.
@@ -231,30 +360,59 @@ func isValidPlayerAttributes(player1, player2 *player.Player) bool {
 // Returns:
 //   - *player.Player: A pointer to the newly created Player instance.
 //   - error: An error, if any.
-func getPlayerAttributes(playerName string) (*player.Player, error) {
-	fmt.Printf(cyanColor+"Enter attributes for %s:\n"+resetColor, playerName)
+func getPlayerAttributes(c *console, playerName string) (*player.Player, error) {
+	fmt.Fprintf(c.out, cyanColor+"Enter attributes for %s:\n"+resetColor, playerName)
 
-	name, err := getStringInput("Name: ")
+	name, err := c.getStringInput("Name: ")
 	if err != nil {
 		return nil, fmt.Errorf("failed to get player name: %w", err)
 	}
 
-	health, err := getIntegerInput("Health: ")
+	health, err := c.getIntegerInput("Health: ")
 	if err != nil {
 		return nil, fmt.Errorf("failed to get player health: %w", err)
 	}
 
-	strength, err := getIntegerInput("Strength: ")
+	strength, err := c.getIntegerInput("Strength: ")
 	if err != nil {
 		return nil, fmt.Errorf("failed to get player strength: %w", err)
 	}
 
-	attack, err := getIntegerInput("Attack: ")
+	attack, err := c.getIntegerInput("Attack: ")
 	if err != nil {
 		return nil, fmt.Errorf("failed to get player attack: %w", err)
 	}
 
-	return player.NewPlayer(name, health, strength, attack), nil
+	agility, err := c.getOptionalIntegerInput("Agility (leave blank for none): ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get player agility: %w", err)
+	}
+
+	mana, err := c.getOptionalIntegerInput("Mana (leave blank for none): ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get player mana: %w", err)
+	}
+	opts := []player.Option{player.WithAgility(agility), player.WithMana(mana)}
+
+	classes := player.Classes()
+	for i, class := range classes {
+		fmt.Fprintf(c.out, "  %d. %s: %s\n", i+1, class.Name, class.Description)
+	}
+	choice, err := c.getStringInput("Class (number or name, leave blank for none): ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get player class: %w", err)
+	}
+	if choice == "" {
+		return player.NewPlayer(name, health, strength, attack, opts...), nil
+	}
+	if n, err := strconv.Atoi(choice); err == nil && n >= 1 && n <= len(classes) {
+		return player.NewPlayer(name, health, strength, attack, append(opts, player.WithClass(classes[n-1]))...), nil
+	}
+	class, err := player.FindClass(choice)
+	if err != nil {
+		return nil, err
+	}
+	return player.NewPlayer(name, health, strength, attack, append(opts, player.WithClass(class))...), nil
 }
 
 // getIntegerInput prompts the user with the provided message,
@@ -271,8 +429,8 @@ func getPlayerAttributes(playerName string) (*player.Player, error) {
 // Returns:
 //   - int: The parsed integer.
 //   - error: An error, if any.
-func getIntegerInput(prompt string) (int, error) {
-	input, err := getStringInput(prompt)
+func (c *console) getIntegerInput(prompt string) (int, error) {
+	input, err := c.getStringInput(prompt)
 	if err != nil {
 		return 0, err
 	}
@@ -281,9 +439,26 @@ func getIntegerInput(prompt string) (int, error) {
 	return strconv.Atoi(input)
 }
 
+// getOptionalIntegerInput prompts the user with the provided message for an integer they may
+// leave out by entering nothing.
+//
+// Parameters:
+//   - prompt: The message to prompt the user for input.
+//
+// Returns:
+//   - int: The parsed integer, or 0 if the input was blank.
+//   - error: An error, if any.
+func (c *console) getOptionalIntegerInput(prompt string) (int, error) {
+	input, err := c.getStringInput(prompt)
+	if err != nil || input == "" {
+		return 0, err
+	}
+	return strconv.Atoi(input)
+}
+
 // ExposeGetIntegerInput is a wrapper function for getIntegerInput to be used in tests
-func ExposeGetIntegerInput(prompt string) (int, error) {
-	return getIntegerInput(prompt)
+func ExposeGetIntegerInput(c *console, prompt string) (int, error) {
+	return c.getIntegerInput(prompt)
 }
 
 // getStringInput prompts the user with the provided message,
@@ -296,11 +471,11 @@ func ExposeGetIntegerInput(prompt string) (int, error) {
 // Returns:
 //   - string: The user-input string.
 //   - error: An error, if any.
-func getStringInput(prompt string) (string, error) {
-	fmt.Print(prompt)
-	reader := bufio.NewReader(os.Stdin)
-	input, err := reader.ReadString('\n')
-	if err != nil {
+func (c *console) getStringInput(prompt string) (string, error) {
+	fmt.Fprint(c.out, prompt)
+	input, err := c.in.ReadString('\n')
+	// A final line without a trailing newline is still input.
+	if err != nil && !(errors.Is(err, io.EOF) && input != "") {
 		return "", err
 	}
 
@@ -309,6 +484,6 @@ func getStringInput(prompt string) (string, error) {
 }
 
 // ExposeGetStringInput is a wrapper function for getStringInput to be used in tests
-func ExposeGetStringInput(prompt string) (string, error) {
-	return getStringInput(prompt)
+func ExposeGetStringInput(c *console, prompt string) (string, error) {
+	return c.getStringInput(prompt)
 }
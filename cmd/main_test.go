@@ -0,0 +1,329 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"proj/pkg/history"
+	"proj/pkg/match"
+	"proj/pkg/rating"
+	"proj/pkg/roster"
+	"strings"
+	"testing"
+)
+
+// TestScriptedSession tests that a whole arena session can be driven from injected input.
+//
+// Test scenarios:
+//  1. Script a session that plays one match and exits. Check that the match is conducted
+//     and the application says goodbye.
+//  2. Script a session that enters duplicate player names. Check that the validation error
+//     is rendered and the session ends cleanly when the input runs out.
+//  3. Script a session that creates two roster players, then picks them for a match by name
+//     and by ID. Check that the match is conducted between them.
+//  4. Script a session that creates roster players picking their class by number and by name,
+//     one with agility and mana, and one naming an unknown class. Check the classes, agility and
+//     mana are kept and the unknown class is rejected.
+//  5. Script a match played turn by turn, casting a spell, failing to cast one without mana,
+//     defending and healing, until the input runs out. Check every turn is played and the rest of
+//     the match is played automatically.
+//  6. Script a match played turn by turn between two players controlled by strategies. Check no
+//     action is asked for and the greedy spellcaster burns through the strength of their opponent.
+//  7. Script a match played turn by turn in which a player is dazed and poisoned. Check the stunned
+//     player loses their turn without being asked, and their poison is shown.
+func TestScriptedSession(t *testing.T) {
+	//TEST 1: one match, then exit
+	var out bytes.Buffer
+	script := "1\n1\n1\nHero\n100\n10\n5\n\n\n\nVillain\n50\n5\n2\n\n\n\n\n0\n0\n"
+	newTestArena(t, script, &out).run()
+	if !strings.Contains(out.String(), "Match result: ") || !strings.Contains(out.String(), "Goodbye!") {
+		t.Errorf(redColor+"Expected a match result and a goodbye, got %s"+resetColor, out.String())
+	} else {
+		fmt.Println(greenColor + "TestScriptedSession : Test1 : Passed" + resetColor)
+	}
+
+	//TEST 2: duplicate names, then the script runs out
+	out.Reset()
+	script = "1\n1\n1\nHero\n100\n10\n5\n\n\n\nHero\n50\n5\n2\n\n\n\n"
+	newTestArena(t, script, &out).run()
+	if !strings.Contains(out.String(), "Player names must be unique.") {
+		t.Errorf(redColor+"Expected the duplicate name to be rejected, got %s"+resetColor, out.String())
+	} else {
+		fmt.Println(greenColor + "TestScriptedSession : Test2 : Passed" + resetColor)
+	}
+
+	//TEST 3: roster players picked for a match
+	out.Reset()
+	script = "3\n1\nHero\n100\n10\n5\n\n\n\n1\nVillain\n50\n5\n2\n\n\n\n0\n1\n1\n1\nhero\n2\n\n0\n0\n0\n"
+	newTestArena(t, script, &out).run()
+	if !strings.Contains(out.String(), "Added Villain to the roster as #2.") || !strings.Contains(out.String(), "Match result: Hero wins") {
+		t.Errorf(redColor+"Expected the roster players to fight, got %s"+resetColor, out.String())
+	} else {
+		fmt.Println(greenColor + "TestScriptedSession : Test3 : Passed" + resetColor)
+	}
+
+	//TEST 4: classes picked for roster players
+	out.Reset()
+	script = "3\n1\nConan\n100\n10\n5\n\n\n1\n1\nMerlin\n60\n5\n8\n20\n30\n mage \n1\nBard\n50\n5\n5\n\n\nbard\n0\n0\n"
+	a := newTestArena(t, script, &out)
+	a.run()
+	entries := a.roster.Entries()
+	if len(entries) != 2 || entries[0].Class != "Warrior" || entries[1].Class != "Mage" || entries[1].Agility != 20 || entries[1].Mana != 30 || !strings.Contains(out.String(), `unknown character class "bard"`) {
+		t.Errorf(redColor+"Expected a Warrior and a Mage, got %v in %s"+resetColor, entries, out.String())
+	} else {
+		fmt.Println(greenColor + "TestScriptedSession : Test4 : Passed" + resetColor)
+	}
+
+	//TEST 5: a match played turn by turn
+	out.Reset()
+	script = "1\n1\n2\nHero\n100\n10\n5\n\n\n\nVillain\n50\n5\n2\n\n20\n\n\n\n4\n1\n4\nfireball\n2\n3\n"
+	newTestArena(t, script, &out).run()
+	for _, expected := range []string{"Round 1: Villain (50/50 health, 20/20 mana) vs Hero (100/100 health)", "Villain cast shield",
+		"not enough mana to cast the spell: fireball costs 10, Hero has 0", "Hero took a defensive stance",
+		"Villain tended their wounds", "Playing the rest of the match automatically.", "Match result: "} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf(redColor+"Expected %q, got %s"+resetColor, expected, out.String())
+			return
+		}
+	}
+	fmt.Println(greenColor + "TestScriptedSession : Test5 : Passed" + resetColor)
+
+	//TEST 6: a match between strategies
+	out.Reset()
+	script = "1\n1\n2\nHero\n100\n10\n5\n\n\n\nVillain\n50\n5\n2\n\n20\n\nexpectimax\nGreedy\n\n0\n0\n"
+	newTestArena(t, script, &out).run()
+	if strings.Contains(out.String(), "Action for ") || !strings.Contains(out.String(), "Villain cast fireball on Hero") || !strings.Contains(out.String(), "Match result: ") {
+		t.Errorf(redColor+"Expected the strategies to play the whole match, got %s"+resetColor, out.String())
+	} else {
+		fmt.Println(greenColor + "TestScriptedSession : Test6 : Passed" + resetColor)
+	}
+
+	//TEST 7: status effects
+	out.Reset()
+	script = "1\n1\n2\nHero\n100\n10\n5\n\n\n\nVillain\n50\n5\n2\n\n20\n\n\n\n4\ndaze\n4\nvenom\n"
+	newTestArena(t, script, &out).run()
+	if strings.Count(out.String(), "Action for Hero") != 1 || !strings.Contains(out.String(), "Hero was stunned and lost their turn") ||
+		!strings.Contains(out.String(), "Round 4: Hero (100/100 health, poison for 3 turns)") {
+		t.Errorf(redColor+"Expected Hero to be stunned and poisoned, got %s"+resetColor, out.String())
+	} else {
+		fmt.Println(greenColor + "TestScriptedSession : Test7 : Passed" + resetColor)
+	}
+}
+
+// newTestArena creates an arena on the given input and output with an empty history and roster
+// kept in a temporary directory.
+func newTestArena(t *testing.T, input string, out *bytes.Buffer) *arena {
+	t.Helper()
+	dir := t.TempDir()
+	players, err := roster.Load(filepath.Join(dir, "roster.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return newArena(newConsole(strings.NewReader(input), out), match.DefaultRules(), history.Open(filepath.Join(dir, "history.jsonl")), players, rating.NewElo())
+}
+
+// TestGetInput tests reading several prompts from one buffered input.
+func TestGetInput(t *testing.T) {
+	var out bytes.Buffer
+	c := newConsole(strings.NewReader(" Hero \n42"), &out)
+	name, err := ExposeGetStringInput(c, "Name: ")
+	if err != nil || name != "Hero" {
+		t.Errorf(redColor+"Expected name to be Hero, got '%s', %v"+resetColor, name, err)
+	}
+	health, err := ExposeGetIntegerInput(c, "Health: ")
+	if err != nil || health != 42 || out.String() != "Name: Health: " {
+		t.Errorf(redColor+"Expected health to be 42, got %d, %v"+resetColor, health, err)
+	} else {
+		fmt.Println(greenColor + "TestGetInput : Test1 : Passed" + resetColor)
+	}
+}
+
+// TestCommands tests the non-interactive subcommands and their exit codes.
+//
+// Test scenarios:
+//  1. fight with --json prints the seeded match as JSON and exits with exitOK.
+//  2. odds with a malformed player exits with exitUsage.
+//  3. An unknown command prints the usage and exits with exitUsage.
+//  4. history lists the fight recorded in test 1 and shows its details.
+//  5. history with an unknown ID exits with exitFailure.
+//  6. roster add saves players that fight can then use by name, and deleting an unknown
+//     player exits with exitFailure.
+//  7. A roster player exported as text and as JSON imports into another roster, where the
+//     second copy is skipped as a duplicate, and an invalid character file exits with exitUsage.
+//  8. The fight of test 6 rated the roster players, and the leaderboard ranks the winner first.
+//  9. A round-robin tournament of the whole roster plus one more player plays every pairing,
+//     and a tournament of a single player exits with exitUsage.
+//  10. A double elimination tournament seeded by rating renders its bracket and crowns a champion.
+//  11. A Swiss tournament exports its standings, with tie-break scores, to a CSV file.
+//  12. fight with strategies for both players conducts the match, and an unknown strategy exits
+//     with exitUsage.
+//  13. fight stops two mages who heal faster than they hurt each other after 1000 rounds, or
+//     after --max-rounds, and a negative --max-rounds exits with exitUsage.
+func TestCommands(t *testing.T) {
+	//TEST 1: seeded fight as JSON
+	var out bytes.Buffer
+	a := newTestArena(t, "", &out)
+	code := runCommand(a, "fight", []string{"--p1", "Hero:100:10:5", "--p2", "Villain:50:5:2", "--seed", "1", "--json"})
+	var result fightResult
+	if err := json.Unmarshal(out.Bytes(), &result); code != exitOK || err != nil || result.Seed != 1 || result.Winner != "Hero" || len(result.Events) != result.Rounds {
+		t.Errorf(redColor+"Expected Hero to win a seeded fight, got code %d and %s"+resetColor, code, out.String())
+	} else {
+		fmt.Println(greenColor + "TestCommands : Test1 : Passed" + resetColor)
+	}
+
+	//TEST 2: malformed player
+	out.Reset()
+	code = runCommand(a, "odds", []string{"--p1", "Hero:100:ten:5", "--p2", "Villain:50:5:2"})
+	if code != exitUsage || !strings.Contains(out.String(), "non-numeric") {
+		t.Errorf(redColor+"Expected a usage error, got code %d and %s"+resetColor, code, out.String())
+	} else {
+		fmt.Println(greenColor + "TestCommands : Test2 : Passed" + resetColor)
+	}
+
+	//TEST 3: unknown command
+	out.Reset()
+	code = runCommand(a, "bogus", nil)
+	if code != exitUsage || !strings.Contains(out.String(), "Usage: arena") {
+		t.Errorf(redColor+"Expected the usage message, got code %d and %s"+resetColor, code, out.String())
+	} else {
+		fmt.Println(greenColor + "TestCommands : Test3 : Passed" + resetColor)
+	}
+
+	//TEST 4: the fight of test 1 is in the history
+	out.Reset()
+	code = runCommand(a, "history", []string{"--player", "Villain"})
+	listing := out.String()
+	out.Reset()
+	detailsCode := runCommand(a, "history", []string{"--id", "1"})
+	if code != exitOK || detailsCode != exitOK || !strings.Contains(listing, "#1") || !strings.Contains(out.String(), "Round 1:") {
+		t.Errorf(redColor+"Expected the recorded fight to be listed and shown, got %s and %s"+resetColor, listing, out.String())
+	} else {
+		fmt.Println(greenColor + "TestCommands : Test4 : Passed" + resetColor)
+	}
+
+	//TEST 5: unknown match ID
+	out.Reset()
+	code = runCommand(a, "history", []string{"--id", "42"})
+	if code != exitFailure {
+		t.Errorf(redColor+"Expected exitFailure for an unknown match, got code %d and %s"+resetColor, code, out.String())
+	} else {
+		fmt.Println(greenColor + "TestCommands : Test5 : Passed" + resetColor)
+	}
+
+	//TEST 6: roster players
+	out.Reset()
+	codes := []int{
+		runCommand(a, "roster", []string{"add", "Hero:100:10:5"}),
+		runCommand(a, "roster", []string{"add", "Villain:50:5:2"}),
+		runCommand(a, "fight", []string{"--p1", "hero", "--p2", "2", "--seed", "1"}),
+		runCommand(a, "roster", []string{"delete", "Nobody"}),
+	}
+	if codes[0] != exitOK || codes[1] != exitOK || codes[2] != exitOK || codes[3] != exitFailure || !strings.Contains(out.String(), "Match result: Hero wins") {
+		t.Errorf(redColor+"Expected the roster players to fight, got codes %v and %s"+resetColor, codes, out.String())
+	} else {
+		fmt.Println(greenColor + "TestCommands : Test6 : Passed" + resetColor)
+	}
+
+	//TEST 7: export and import
+	dir := t.TempDir()
+	textPath, jsonPath, invalidPath := filepath.Join(dir, "hero.txt"), filepath.Join(dir, "hero.json"), filepath.Join(dir, "ghost.txt")
+	if err := os.WriteFile(invalidPath, []byte("Ghost:0:1:1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	other := newTestArena(t, "", &out)
+	codes = []int{
+		runCommand(a, "roster", []string{"export", "--text", "--out", textPath, "Hero"}),
+		runCommand(a, "roster", []string{"export", "--out", jsonPath, "1"}),
+		runCommand(other, "roster", []string{"import", textPath}),
+		runCommand(other, "roster", []string{"import", jsonPath}),
+		runCommand(other, "roster", []string{"import", invalidPath}),
+	}
+	text, _ := os.ReadFile(textPath)
+	entry, err := other.roster.Find("Hero")
+	if codes[0] != exitOK || codes[1] != exitOK || codes[2] != exitOK || codes[3] != exitFailure || codes[4] != exitUsage ||
+		string(text) != "Hero:100:10:5\n" || err != nil || entry.Health != 100 || len(other.roster.Entries()) != 1 {
+		t.Errorf(redColor+"Expected Hero to be exported and imported once, got codes %v, %q and %v"+resetColor, codes, text, other.roster.Entries())
+	} else {
+		fmt.Println(greenColor + "TestCommands : Test7 : Passed" + resetColor)
+	}
+
+	//TEST 8: ratings and leaderboard
+	out.Reset()
+	code = runCommand(a, "leaderboard", []string{"--json"})
+	var board []roster.Entry
+	if err := json.Unmarshal(out.Bytes(), &board); code != exitOK || err != nil || len(board) != 2 ||
+		board[0].Name != "Hero" || board[0].Rating.Rating != 1516 || board[1].Rating.Matches != 1 {
+		t.Errorf(redColor+"Expected Hero to lead at 1516 after one rated win, got code %d and %s"+resetColor, code, out.String())
+	} else {
+		fmt.Println(greenColor + "TestCommands : Test8 : Passed" + resetColor)
+	}
+
+	//TEST 9: round-robin tournament
+	out.Reset()
+	code = runCommand(a, "tournament", []string{"--seed", "3", "--json", "Hero", "Villain", "Rogue:60:5:8"})
+	var league tournamentResult
+	if err := json.Unmarshal(out.Bytes(), &league); code != exitOK || err != nil || len(league.Games) != 3 || len(league.Standings) != 3 ||
+		runCommand(a, "tournament", []string{"Hero"}) != exitUsage {
+		t.Errorf(redColor+"Expected a 3 game round-robin, got code %d and %s"+resetColor, code, out.String())
+	} else {
+		fmt.Println(greenColor + "TestCommands : Test9 : Passed" + resetColor)
+	}
+
+	//TEST 10: double elimination
+	out.Reset()
+	code = runCommand(a, "tournament", []string{"--format", "double", "--seed", "3", "Hero", "Villain", "Rogue:60:5:8"})
+	if code != exitOK || !strings.Contains(out.String(), "Winners bracket") || !strings.Contains(out.String(), "Grand final") || !strings.Contains(out.String(), "Champion: ") {
+		t.Errorf(redColor+"Expected a rendered double elimination bracket, got code %d and %s"+resetColor, code, out.String())
+	} else {
+		fmt.Println(greenColor + "TestCommands : Test10 : Passed" + resetColor)
+	}
+
+	//TEST 11: Swiss tournament
+	out.Reset()
+	csvPath := filepath.Join(dir, "standings.csv")
+	code = runCommand(a, "tournament", []string{"--format", "swiss", "--seed", "3", "--csv", csvPath, "Hero", "Villain", "Rogue:60:5:8", "Knight:80:8:4", "Mage:40:3:9"})
+	standings, _ := os.ReadFile(csvPath)
+	if code != exitOK || !strings.Contains(out.String(), "Buchholz") || strings.Count(string(standings), "\n") != 6 ||
+		!strings.HasPrefix(string(standings), "rank,name,played,wins,losses,draws,byes,points,buchholz,sonneborn_berger") {
+		t.Errorf(redColor+"Expected Swiss standings exported to CSV, got code %d, %s and %s"+resetColor, code, out.String(), standings)
+	} else {
+		fmt.Println(greenColor + "TestCommands : Test11 : Passed" + resetColor)
+	}
+
+	//TEST 12: strategies
+	out.Reset()
+	code = runCommand(a, "fight", []string{"--p1", "Merlin:60:5:8:mana=20", "--p2", "Morgana:60:5:8:mana=20", "--ai1", "expectimax", "--ai2", "cautious", "--seed", "5"})
+	if code != exitOK || !strings.Contains(out.String(), "Match result: ") ||
+		runCommand(a, "fight", []string{"--p1", "Hero", "--p2", "Villain", "--ai1", "minimax"}) != exitUsage {
+		t.Errorf(redColor+"Expected a match between strategies, got code %d and %s"+resetColor, code, out.String())
+	} else {
+		fmt.Println(greenColor + "TestCommands : Test12 : Passed" + resetColor)
+	}
+
+	//TEST 13: round limit
+	mages := []string{"--p1", "Merlin:100:10:2:mana=30", "--p2", "Morgana:100:10:3:mana=20", "--seed", "1", "--json"}
+	var limited, capped fightResult
+	out.Reset()
+	code = runCommand(a, "fight", mages)
+	errLimited := json.Unmarshal(out.Bytes(), &limited)
+	out.Reset()
+	cappedCode := runCommand(a, "fight", append(mages, "--max-rounds", "50"))
+	errCapped := json.Unmarshal(out.Bytes(), &capped)
+	if code != exitOK || errLimited != nil || limited.Rounds != match.SafetyRounds || limited.Reason == "win" ||
+		cappedCode != exitOK || errCapped != nil || capped.Rounds != 50 ||
+		runCommand(a, "fight", append(mages, "--max-rounds", "-1")) != exitUsage {
+		t.Errorf(redColor+"Expected the fights to stop after %d and 50 rounds, got %d and %d"+resetColor, match.SafetyRounds, limited.Rounds, capped.Rounds)
+	} else {
+		fmt.Println(greenColor + "TestCommands : Test13 : Passed" + resetColor)
+	}
+}
+
+// TestMain runs the main testing suite.
+func TestMain(m *testing.M) {
+	fmt.Println("Testing cmd package...")
+	Result := m.Run()
+	fmt.Println("Testing complete.")
+	os.Exit(Result)
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"proj/pkg/match"
+	"proj/pkg/rating"
+	"proj/pkg/roster"
+)
+
+// rateMatch updates the ratings of both players of a conducted match, if both were picked from
+// the roster, and saves the roster.
+//
+// Parameters:
+//   - m: A pointer to the conducted match.
+//
+// Returns:
+//   - []roster.Entry: The entries of Player A and Player B with their new ratings, or nil if the
+//     match is not rated.
+//   - error: An error, if the roster cannot be saved.
+func (a *arena) rateMatch(m *match.Match) ([]roster.Entry, error) {
+	entryA, okA := a.roster.Lookup(m.PlayerA)
+	entryB, okB := a.roster.Lookup(m.PlayerB)
+	if !okA || !okB {
+		return nil, nil
+	}
+
+	entryA.Rating, entryB.Rating = a.rater.Rate(
+		rating.Current(a.rater, entryA.Rating),
+		rating.Current(a.rater, entryB.Rating),
+		rating.Score(m.Outcome(), m.PlayerA))
+	for _, entry := range []roster.Entry{entryA, entryB} {
+		if err := a.roster.SetRating(entry.ID, entry.Rating); err != nil {
+			return nil, err
+		}
+	}
+	if err := a.roster.Save(); err != nil {
+		return nil, err
+	}
+	return []roster.Entry{entryA, entryB}, nil
+}
+
+// reportRatings rates a conducted match and prints the new ratings of both players. Failing to
+// rate a match is reported but does not interrupt the session.
+//
+// Parameters:
+//   - m: A pointer to the conducted match.
+//
+// Returns:
+//   - []roster.Entry: The entries of both players with their new ratings, or nil if the match is
+//     not rated or rating it failed.
+func (a *arena) reportRatings(m *match.Match) []roster.Entry {
+	rated, err := a.rateMatch(m)
+	if err != nil {
+		fmt.Fprintln(a.out, redColor+"Error updating the ratings: "+err.Error()+resetColor)
+		return nil
+	}
+	for _, entry := range rated {
+		fmt.Fprintf(a.out, cyanColor+"%s is now rated %s."+resetColor+"\n", entry.Name, entry.Rating)
+	}
+	return rated
+}
+
+// printLeaderboard prints the roster ranked by rating.
+//
+// Parameters:
+//   - c: The console to print output to.
+//   - rater: The rating system the roster is ranked by.
+//   - entries: The ranked roster entries.
+func printLeaderboard(c *console, rater rating.Rater, entries []roster.Entry) {
+	if len(entries) == 0 {
+		fmt.Fprintln(c.out, yellowColor+"The roster is empty."+resetColor)
+		return
+	}
+	fmt.Fprintf(c.out, cyanColor+"%-5s %-20s %7s %10s %8s"+resetColor+"\n", "Rank", "Name", "Rating", "Deviation", "Matches")
+	for i, entry := range entries {
+		deviation := "-"
+		if entry.Rating.Deviation != 0 {
+			deviation = fmt.Sprintf("%.0f", entry.Rating.Deviation)
+		}
+		fmt.Fprintf(c.out, "%-5d %-20s %7.0f %10s %8d\n", i+1, entry.Name, entry.Rating.Rating, deviation, entry.Rating.Matches)
+	}
+	fmt.Fprintf(c.out, "Ratings use the %s system.\n", rater.Name())
+}
+
+// leaderboardCommand prints the roster ranked by rating.
+//
+// Parameters:
+//   - a: A pointer to the arena whose roster is ranked.
+//   - args: The command-line arguments following "leaderboard".
+//
+// Returns:
+//   - int: exitOK if the leaderboard was printed, exitUsage if the arguments are invalid.
+func leaderboardCommand(a *arena, args []string) int {
+	flags := newFlagSet(a.console, "leaderboard")
+	asJSON := flags.Bool("json", false, "print the leaderboard as JSON")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	entries := a.roster.Leaderboard(a.rater)
+	if *asJSON {
+		return printJSON(a.console, entries)
+	}
+	printLeaderboard(a.console, a.rater, entries)
+	return exitOK
+}
@@ -0,0 +1,331 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"proj/pkg/player"
+	"proj/pkg/roster"
+	"strings"
+)
+
+// manageRoster presents the roster menu, where players can be created, edited and deleted.
+// Every change is saved to the roster file straight away.
+func (a *arena) manageRoster() {
+	for {
+		printRoster(a.console, a.roster.Entries())
+		fmt.Fprintln(a.out, yellowColor+"Press 1 to create a player, 2 to edit a player, 3 to delete a player or press 0 to return"+resetColor)
+
+		choice, err := a.getUserInput("Enter your choice: ")
+		if err != nil {
+			fmt.Fprintln(a.out, redColor+"Please enter a valid choice or press 0 to return"+resetColor)
+			return
+		}
+
+		switch choice {
+		case 0:
+			return
+		case 1:
+			p, err := getPlayerAttributes(a.console, "the new player")
+			if err != nil {
+				fmt.Fprintln(a.out, redColor+"Error creating player: "+err.Error()+resetColor)
+				continue
+			}
+			entry, err := a.roster.Add(p)
+			a.saveRoster(fmt.Sprintf("Added %s to the roster as #%d.", entry.Name, entry.ID), err)
+		case 2:
+			entry, err := a.findRosterEntry("Enter the name or ID of the player to edit: ")
+			if err != nil {
+				continue
+			}
+			p, err := getPlayerAttributes(a.console, entry.Name)
+			if err != nil {
+				fmt.Fprintln(a.out, redColor+"Error editing player: "+err.Error()+resetColor)
+				continue
+			}
+			entry, err = a.roster.Update(entry.ID, p)
+			a.saveRoster(fmt.Sprintf("Updated #%d %s.", entry.ID, entry.Name), err)
+		case 3:
+			entry, err := a.findRosterEntry("Enter the name or ID of the player to delete: ")
+			if err != nil {
+				continue
+			}
+			a.saveRoster(fmt.Sprintf("Deleted %s from the roster.", entry.Name), a.roster.Remove(entry.ID))
+		default:
+			fmt.Fprintln(a.out, redColor+"Invalid choice. Please enter 0, 1, 2 or 3."+resetColor)
+		}
+	}
+}
+
+// findRosterEntry prompts for the name or ID of a roster entry and looks it up, printing an
+// error if there is no such entry.
+//
+// Parameters:
+//   - prompt: The message to prompt the user.
+//
+// Returns:
+//   - roster.Entry: The entry.
+//   - error: An error, if the input cannot be read or no entry matches it.
+func (a *arena) findRosterEntry(prompt string) (roster.Entry, error) {
+	ref, err := a.getStringInput(prompt)
+	if err != nil {
+		return roster.Entry{}, err
+	}
+	entry, err := a.roster.Find(ref)
+	if err != nil {
+		fmt.Fprintln(a.out, redColor+err.Error()+resetColor)
+	}
+	return entry, err
+}
+
+// saveRoster saves the roster after a change and reports the result.
+//
+// Parameters:
+//   - message: The message to print if the change was made and saved.
+//   - err: The error returned by the change, if any; nothing is saved if it is not nil.
+func (a *arena) saveRoster(message string, err error) {
+	if err == nil {
+		err = a.roster.Save()
+	}
+	if err != nil {
+		fmt.Fprintln(a.out, redColor+"Error updating the roster: "+err.Error()+resetColor)
+		return
+	}
+	fmt.Fprintln(a.out, greenColor+message+resetColor)
+}
+
+// choosePlayer sets up a player for a match: the user picks a roster entry by name or ID, or
+// enters new attributes. With an empty roster, the attributes are asked for straight away.
+//
+// Parameters:
+//   - playerName: The name of the player being set up, e.g. "Player 1".
+//
+// Returns:
+//   - *player.Player: A pointer to the player.
+//   - error: An error, if the input cannot be read or no roster entry matches it.
+func (a *arena) choosePlayer(playerName string) (*player.Player, error) {
+	entries := a.roster.Entries()
+	if len(entries) == 0 {
+		return getPlayerAttributes(a.console, playerName)
+	}
+
+	printRoster(a.console, entries)
+	ref, err := a.getStringInput("Enter the name or ID of a roster player for " + playerName + " (leave blank to enter new attributes): ")
+	if err != nil {
+		return nil, err
+	}
+	if ref == "" {
+		return getPlayerAttributes(a.console, playerName)
+	}
+	entry, err := a.roster.Find(ref)
+	if err != nil {
+		return nil, err
+	}
+	return entry.Player(), nil
+}
+
+// resolvePlayer resolves a player given on the command line, either as
+// "Name:Health:Strength:Attack" or as the name or ID of a roster entry.
+//
+// Parameters:
+//   - spec: The player specification or roster reference.
+//
+// Returns:
+//   - *player.Player: A pointer to the player.
+//   - error: An error, if the specification is malformed or no roster entry matches it.
+func (a *arena) resolvePlayer(spec string) (*player.Player, error) {
+	if strings.Contains(spec, ":") {
+		return player.ParseText(spec)
+	}
+	entry, err := a.roster.Find(spec)
+	if err != nil {
+		return nil, err
+	}
+	return entry.Player(), nil
+}
+
+// printRoster prints the roster as a table.
+//
+// Parameters:
+//   - c: The console to print output to.
+//   - entries: The roster entries.
+func printRoster(c *console, entries []roster.Entry) {
+	if len(entries) == 0 {
+		fmt.Fprintln(c.out, yellowColor+"The roster is empty."+resetColor)
+		return
+	}
+	fmt.Fprintf(c.out, cyanColor+"%-4s %-20s %7s %9s %7s %8s %5s  %s"+resetColor+"\n", "ID", "Name", "Health", "Strength", "Attack", "Agility", "Mana", "Class")
+	for _, entry := range entries {
+		fmt.Fprintf(c.out, "%-4d %-20s %7d %9d %7d %8d %5d  %s\n", entry.ID, entry.Name, entry.Health, entry.Strength, entry.Attack, entry.Agility, entry.Mana, entry.Class)
+	}
+}
+
+// rosterCommand lists, adds, edits, deletes, imports or exports roster players.
+//
+// Parameters:
+//   - a: A pointer to the arena whose roster is managed.
+//   - args: The command-line arguments following "roster": an action, its flags and its arguments.
+//
+// Returns:
+//   - int: exitOK if the action succeeded, exitFailure if a file cannot be read or written or a
+//     player is not found, exitUsage if the arguments or players are invalid.
+func rosterCommand(a *arena, args []string) int {
+	action := "list"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		action, args = args[0], args[1:]
+	}
+
+	flags := newFlagSet(a.console, "roster "+action)
+	asJSON := flags.Bool("json", false, "print the roster as JSON")
+	asText := flags.Bool("text", false, "export the player as Name:Health:Strength:Attack instead of JSON")
+	out := flags.String("out", "", "export the player to this file instead of printing it")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	args = flags.Args()
+
+	var message string
+	var err error
+	switch {
+	case action == "list" && len(args) == 0:
+		if *asJSON {
+			return printJSON(a.console, a.roster.Entries())
+		}
+		printRoster(a.console, a.roster.Entries())
+		return exitOK
+	case action == "export" && len(args) == 1:
+		return exportPlayer(a, args[0], *asText, *out)
+	case action == "import" && len(args) == 1:
+		return importPlayers(a, args[0])
+	case action == "add" && len(args) == 1:
+		var p *player.Player
+		if p, err = player.ParseText(args[0]); err != nil {
+			fmt.Fprintln(a.out, redColor+err.Error()+resetColor)
+			return exitUsage
+		}
+		var entry roster.Entry
+		entry, err = a.roster.Add(p)
+		message = fmt.Sprintf("Added %s to the roster as #%d.", entry.Name, entry.ID)
+	case action == "edit" && len(args) == 2:
+		var p *player.Player
+		if p, err = player.ParseText(args[1]); err != nil {
+			fmt.Fprintln(a.out, redColor+err.Error()+resetColor)
+			return exitUsage
+		}
+		var entry roster.Entry
+		if entry, err = a.roster.Find(args[0]); err == nil {
+			entry, err = a.roster.Update(entry.ID, p)
+		}
+		message = fmt.Sprintf("Updated #%d %s.", entry.ID, entry.Name)
+	case action == "delete" && len(args) == 1:
+		var entry roster.Entry
+		if entry, err = a.roster.Find(args[0]); err == nil {
+			err = a.roster.Remove(entry.ID)
+		}
+		message = fmt.Sprintf("Deleted %s from the roster.", entry.Name)
+	default:
+		fmt.Fprintln(a.out, redColor+"Usage: arena roster [list | add Name:Health:Strength:Attack | edit <name|ID> Name:Health:Strength:Attack | delete <name|ID> | import <file> | export [--text] [--out file] <name|ID>]"+resetColor)
+		return exitUsage
+	}
+
+	if err != nil {
+		fmt.Fprintln(a.out, redColor+err.Error()+resetColor)
+		if errors.Is(err, roster.ErrNotFound) {
+			return exitFailure
+		}
+		return exitUsage
+	}
+	if err := a.roster.Save(); err != nil {
+		fmt.Fprintln(a.out, redColor+"Error saving the roster: "+err.Error()+resetColor)
+		return exitFailure
+	}
+	fmt.Fprintln(a.out, greenColor+message+resetColor)
+	return exitOK
+}
+
+// importPlayers adds every player defined in a character file to the roster. Players whose name
+// is already taken are reported and skipped; the others are still added.
+//
+// Parameters:
+//   - a: A pointer to the arena whose roster the players are added to.
+//   - path: The path of the character file; see player.Decode for the accepted formats.
+//
+// Returns:
+//   - int: exitOK if every player was added, exitFailure if the file cannot be read, a player was
+//     skipped or the roster cannot be saved, exitUsage if the file holds an invalid player.
+func importPlayers(a *arena, path string) int {
+	players, err := player.Load(path)
+	if err != nil {
+		fmt.Fprintln(a.out, redColor+"Error importing players: "+err.Error()+resetColor)
+		if errors.Is(err, os.ErrNotExist) || errors.Is(err, os.ErrPermission) {
+			return exitFailure
+		}
+		return exitUsage
+	}
+
+	code := exitOK
+	added := 0
+	for _, p := range players {
+		entry, err := a.roster.Add(p)
+		if err != nil {
+			fmt.Fprintln(a.out, redColor+"Skipped: "+err.Error()+resetColor)
+			code = exitFailure
+			continue
+		}
+		fmt.Fprintf(a.out, "Imported %s as #%d.\n", entry.Name, entry.ID)
+		added++
+	}
+
+	if added > 0 {
+		if err := a.roster.Save(); err != nil {
+			fmt.Fprintln(a.out, redColor+"Error saving the roster: "+err.Error()+resetColor)
+			return exitFailure
+		}
+	}
+	fmt.Fprintf(a.out, greenColor+"Imported %d of %d players."+resetColor+"\n", added, len(players))
+	return code
+}
+
+// exportPlayer writes a roster player as a character file, in JSON or in the
+// "Name:Health:Strength:Attack" text format, so it can be shared and imported elsewhere.
+//
+// Parameters:
+//   - a: A pointer to the arena whose roster holds the player.
+//   - ref: The name or ID of the roster player.
+//   - asText: true to use the text format, false to use JSON.
+//   - path: The file to write, or "" to print the player instead.
+//
+// Returns:
+//   - int: exitOK if the player was exported, exitFailure if the player is not found or the
+//     file cannot be written.
+func exportPlayer(a *arena, ref string, asText bool, path string) int {
+	entry, err := a.roster.Find(ref)
+	if err != nil {
+		fmt.Fprintln(a.out, redColor+err.Error()+resetColor)
+		return exitFailure
+	}
+
+	var data []byte
+	if asText {
+		data, err = entry.Player().MarshalText()
+	} else {
+		data, err = json.MarshalIndent(entry.Player(), "", "  ")
+	}
+	if err != nil {
+		fmt.Fprintln(a.out, redColor+"Error encoding player: "+err.Error()+resetColor)
+		return exitFailure
+	}
+	data = append(data, '\n')
+
+	if path == "" {
+		a.out.Write(data)
+		return exitOK
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		fmt.Fprintln(a.out, redColor+"Error exporting player: "+err.Error()+resetColor)
+		return exitFailure
+	}
+	fmt.Fprintln(a.out, greenColor+"Exported "+entry.Name+" to "+path+resetColor)
+	return exitOK
+}
@@ -0,0 +1,260 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"proj/pkg/match"
+	"proj/pkg/player"
+	"proj/pkg/rating"
+	"proj/pkg/tournament"
+	"time"
+)
+
+// errRecording marks a tournament match that was conducted but could not be recorded.
+var errRecording = errors.New("error recording the match")
+
+// tournamentGame is the JSON output of a tournament game.
+type tournamentGame struct {
+	Round   int    `json:"round"`   // Round is the tournament round the game was played in.
+	PlayerA string `json:"playerA"` // PlayerA is the name of Player A.
+	PlayerB string `json:"playerB"` // PlayerB is the name of Player B.
+	Result  string `json:"result"`  // Result is the match result, e.g. "Hero wins".
+	Rounds  int    `json:"rounds"`  // Rounds is the number of rounds the match lasted.
+}
+
+// tournamentResult is the JSON output of the tournament command.
+type tournamentResult struct {
+	Format    string                `json:"format"`             // Format is the format of the tournament.
+	Champion  string                `json:"champion,omitempty"` // Champion is the winner of an elimination bracket.
+	Games     []tournamentGame      `json:"games"`              // Games are the games played, in order.
+	Standings []tournament.Standing `json:"standings"`          // Standings rank the entrants, best first.
+}
+
+// tournamentCommand conducts a tournament between the players given on the command line, or the
+// whole roster if none are given. Every match is recorded in the match history, and matches
+// between roster players update their ratings.
+//
+// Parameters:
+//   - a: A pointer to the arena, whose rules every match is played under.
+//   - args: The command-line arguments following "tournament".
+//
+// Returns:
+//   - int: exitOK if the tournament was conducted, exitFailure if a match cannot be recorded,
+//     exitUsage if the arguments or entrants are invalid.
+func tournamentCommand(a *arena, args []string) int {
+	flags := newFlagSet(a.console, "tournament")
+	format := flags.String("format", "round-robin", "tournament format: round-robin, single or double (elimination), or swiss")
+	double := flags.Bool("double", false, "play every round-robin pairing twice, with the players swapped")
+	seeding := flags.String("seeding", "rating", "elimination bracket seeding: rating, random or entry (the order given)")
+	rounds := flags.Int("rounds", 0, "number of Swiss rounds (default: enough to find a single unbeaten entrant)")
+	csvPath := flags.String("csv", "", "export the final standings to this CSV file")
+	seed := flags.Int64("seed", time.Now().UnixNano(), "seed of the first match's dice")
+	asJSON := flags.Bool("json", false, "print the games and standings as JSON")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	entrants, ok := a.entrants(flags.Args())
+	if !ok {
+		return exitUsage
+	}
+
+	cfg := tournament.Config{Rules: a.rules, Seed: *seed, OnMatch: a.recordTournamentMatch}
+	var result tournament.Result
+	var bracket *tournament.Bracket
+	var err error
+	switch *format {
+	case "round-robin":
+		result, err = tournament.RoundRobin(entrants, *double, cfg)
+	case "swiss":
+		result, err = tournament.Swiss(entrants, *rounds, cfg)
+	case "single", "double":
+		switch *seeding {
+		case "rating":
+			entrants = tournament.SeedByRating(entrants, a.ratings(entrants))
+		case "random":
+			entrants = tournament.SeedRandomly(entrants, *seed)
+		case "entry":
+		default:
+			fmt.Fprintf(a.out, redColor+"Unknown seeding %q"+resetColor+"\n", *seeding)
+			return exitUsage
+		}
+		if *format == "single" {
+			bracket, err = tournament.SingleElimination(entrants, cfg)
+		} else {
+			bracket, err = tournament.DoubleElimination(entrants, cfg)
+		}
+		if err == nil {
+			result = bracket.Result
+		}
+	default:
+		fmt.Fprintf(a.out, redColor+"Unknown tournament format %q"+resetColor+"\n", *format)
+		return exitUsage
+	}
+	switch {
+	case errors.Is(err, errRecording):
+		fmt.Fprintln(a.out, redColor+err.Error()+resetColor)
+		return exitFailure
+	case errors.Is(err, tournament.ErrTooFewEntrants), errors.Is(err, tournament.ErrInvalidRounds):
+		fmt.Fprintln(a.out, redColor+err.Error()+resetColor)
+		return exitUsage
+	case err != nil:
+		fmt.Fprintln(a.out, redColor+describeValidationError(err)+resetColor)
+		return exitUsage
+	}
+
+	if *csvPath != "" {
+		if err := exportStandings(*csvPath, result.Standings); err != nil {
+			fmt.Fprintln(a.out, redColor+"Error exporting the standings: "+err.Error()+resetColor)
+			return exitFailure
+		}
+	}
+
+	if *asJSON {
+		output := tournamentResult{Format: *format, Games: make([]tournamentGame, len(result.Games)), Standings: result.Standings}
+		for i, game := range result.Games {
+			output.Games[i] = newTournamentGame(game)
+		}
+		if bracket != nil {
+			output.Champion = bracket.Standings[0].Name()
+		}
+		return printJSON(a.console, output)
+	}
+
+	if bracket != nil {
+		if err := bracket.Render(a.out); err != nil {
+			return exitFailure
+		}
+		fmt.Fprintln(a.out)
+	} else {
+		for _, game := range result.Games {
+			g := newTournamentGame(game)
+			fmt.Fprintf(a.out, "Round %d: %s vs %s: %s in %d rounds\n", g.Round, g.PlayerA, g.PlayerB, g.Result, g.Rounds)
+		}
+	}
+	printStandings(a.console, result.Standings, *format == "swiss")
+	return exitOK
+}
+
+// entrants resolves the players given on the command line, or takes the whole roster if none are given.
+//
+// Parameters:
+//   - specs: The player specifications or roster references.
+//
+// Returns:
+//   - []*player.Player: The entrants.
+//   - bool: true if every player was resolved, false if an error was printed.
+func (a *arena) entrants(specs []string) ([]*player.Player, bool) {
+	var entrants []*player.Player
+	if len(specs) == 0 {
+		for _, entry := range a.roster.Entries() {
+			entrants = append(entrants, entry.Player())
+		}
+		return entrants, true
+	}
+
+	for _, spec := range specs {
+		p, err := a.resolvePlayer(spec)
+		if err != nil {
+			fmt.Fprintln(a.out, redColor+err.Error()+resetColor)
+			return nil, false
+		}
+		entrants = append(entrants, p)
+	}
+	return entrants, true
+}
+
+// ratings returns the rating of each entrant for seeding a bracket: the current rating of a
+// roster player, and the initial rating of anyone else.
+//
+// Parameters:
+//   - entrants: The entrants.
+//
+// Returns:
+//   - []float64: The rating of each entrant, in the same order.
+func (a *arena) ratings(entrants []*player.Player) []float64 {
+	ratings := make([]float64, len(entrants))
+	for i, p := range entrants {
+		current := a.rater.Initial()
+		if entry, ok := a.roster.Lookup(p); ok {
+			current = rating.Current(a.rater, entry.Rating)
+		}
+		ratings[i] = current.Rating
+	}
+	return ratings
+}
+
+// recordTournamentMatch records a tournament match in the match history and, if both players are
+// roster players, updates their ratings.
+//
+// Parameters:
+//   - m: A pointer to the conducted match.
+//
+// Returns:
+//   - error: An error wrapping errRecording, if the history or roster cannot be written.
+func (a *arena) recordTournamentMatch(m *match.Match) error {
+	if _, err := a.history.Append(m, time.Now()); err != nil {
+		return fmt.Errorf("%w in the history: %v", errRecording, err)
+	}
+	if _, err := a.rateMatch(m); err != nil {
+		return fmt.Errorf("%w in the ratings: %v", errRecording, err)
+	}
+	return nil
+}
+
+// newTournamentGame summarises a tournament game.
+//
+// Parameters:
+//   - game: The game.
+//
+// Returns:
+//   - tournamentGame: The summary of the game.
+func newTournamentGame(game tournament.Game) tournamentGame {
+	nameA, _, _, _ := player.GetPlayerBaseAttributes(game.Match.PlayerA)
+	nameB, _, _, _ := player.GetPlayerBaseAttributes(game.Match.PlayerB)
+	outcome := game.Match.Outcome()
+	return tournamentGame{Round: game.Round, PlayerA: nameA, PlayerB: nameB, Result: outcome.String(), Rounds: outcome.Rounds}
+}
+
+// exportStandings writes tournament standings to a CSV file.
+//
+// Parameters:
+//   - path: The path of the CSV file.
+//   - standings: The ranked standings.
+//
+// Returns:
+//   - error: An error, if the file cannot be written.
+func exportStandings(path string, standings []tournament.Standing) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := tournament.WriteStandingsCSV(file, standings); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// printStandings prints tournament standings as a table.
+//
+// Parameters:
+//   - c: The console to print output to.
+//   - standings: The standings, best first.
+//   - tieBreaks: true to show the Swiss tie-break scores.
+func printStandings(c *console, standings []tournament.Standing, tieBreaks bool) {
+	fmt.Fprintf(c.out, cyanColor+"%-5s %-20s %6s %4s %4s %4s %6s", "Rank", "Name", "Played", "W", "L", "D", "Points")
+	if tieBreaks {
+		fmt.Fprintf(c.out, " %8s %8s", "Buchholz", "S-B")
+	}
+	fmt.Fprintf(c.out, " %8s"+resetColor+"\n", "Damage")
+
+	for i, s := range standings {
+		fmt.Fprintf(c.out, "%-5d %-20s %6d %4d %4d %4d %6.1f", i+1, s.Name(), s.Played, s.Wins, s.Losses, s.Draws, s.Points)
+		if tieBreaks {
+			fmt.Fprintf(c.out, " %8.1f %8.2f", s.Buchholz, s.SonnebornBerger)
+		}
+		fmt.Fprintf(c.out, " %+8d\n", s.DamageDifferential())
+	}
+}
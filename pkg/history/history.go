@@ -0,0 +1,258 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"proj/pkg/match"
+	"proj/pkg/player"
+	"proj/pkg/replay"
+	"time"
+)
+
+// ErrNotFound is returned when no match with the requested ID has been recorded.
+var ErrNotFound = errors.New("match not found in history")
+
+// Record is the persisted account of a single conducted match.
+type Record struct {
+	ID          int                    `json:"id"`          // ID identifies the match; IDs count up from 1.
+	Timestamp   time.Time              `json:"timestamp"`   // Timestamp is when the match was recorded.
+	Players     [2]replay.PlayerRecord `json:"players"`     // Players holds Player A and Player B, in that order.
+	Result      string                 `json:"result"`      // Result is the match result, e.g. "Hero wins".
+	Reason      string                 `json:"reason"`      // Reason is how the match ended: win, timeout or draw.
+	Winner      string                 `json:"winner"`      // Winner is the name of the winner, empty for a draw.
+	Rounds      int                    `json:"rounds"`      // Rounds is the number of rounds played.
+	FinalHealth [2]int                 `json:"finalHealth"` // FinalHealth holds the final health of Player A and Player B.
+	Seed        int64                  `json:"seed"`        // Seed is the seed of the match dice.
+	Events      []match.RoundEvent     `json:"events"`      // Events are the events of every round.
+}
+
+// HasPlayer reports whether a player with the given name took part in the match.
+//
+// Parameters:
+//   - name: The name of the player.
+//
+// Returns:
+//   - bool: true if either player has that name, false otherwise.
+func (r Record) HasPlayer(name string) bool {
+	return r.Players[0].Name == name || r.Players[1].Name == name
+}
+
+// Store is an append-only history of matches, kept as one JSON record per line in a local file.
+// A store expects to be the only writer of its file while it is in use.
+type Store struct {
+	path   string // path is the location of the history file.
+	lastID int    // lastID is the ID of the last recorded match, once known.
+	known  bool   // known reports whether lastID has been read from the file.
+}
+
+// Open returns the store kept in the file at the given path. The file and its directory are
+// created when the first match is appended.
+//
+// Parameters:
+//   - path: The path of the history file.
+//
+// Returns:
+//   - *Store: A pointer to the store.
+func Open(path string) *Store {
+	return &Store{path: path}
+}
+
+// DefaultPath returns the standard location of the history file inside the user's configuration directory.
+//
+// Returns:
+//   - string: The path of the history file.
+//   - error: An error, if the configuration directory cannot be determined.
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "magical-arena", "history.jsonl"), nil
+}
+
+// Append records a conducted match at the end of the history, writing it as a single line. The
+// history is read once, on the first append, to find the last ID; later appends only write.
+//
+// Parameters:
+//   - m: A pointer to the conducted match.
+//   - at: The time the match is recorded at.
+//
+// Returns:
+//   - Record: The record appended to the history, with its assigned ID.
+//   - error: An error, if the history cannot be read or written.
+func (s *Store) Append(m *match.Match, at time.Time) (Record, error) {
+	if !s.known {
+		lastID, err := s.readLastID()
+		if err != nil {
+			return Record{}, err
+		}
+		s.lastID, s.known = lastID, true
+	}
+
+	record := newRecord(m, at)
+	record.ID = s.lastID + 1
+
+	line, err := json.Marshal(record)
+	if err != nil {
+		return Record{}, err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return Record{}, err
+	}
+	file, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return Record{}, err
+	}
+	defer file.Close()
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return Record{}, err
+	}
+	s.lastID = record.ID
+	return record, nil
+}
+
+// readLastID reads the ID of the last recorded match, decoding only the IDs of the records.
+//
+// Returns:
+//   - int: The ID of the last recorded match, or 0 for an empty or missing history.
+//   - error: An error, if the history cannot be read.
+func (s *Store) readLastID() (int, error) {
+	file, err := os.Open(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	lastID := 0
+	scanner := newScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var record struct {
+			ID int `json:"id"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return 0, fmt.Errorf("invalid history record on line %d: %w", line, err)
+		}
+		lastID = record.ID
+	}
+	return lastID, scanner.Err()
+}
+
+// newScanner returns a scanner over the lines of a history file.
+//
+// Parameters:
+//   - r: The reader of the history file.
+//
+// Returns:
+//   - *bufio.Scanner: A pointer to the scanner.
+func newScanner(r io.Reader) *bufio.Scanner {
+	scanner := bufio.NewScanner(r)
+	// Records carry every round event, so lines can be far longer than the default limit.
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	return scanner
+}
+
+// newRecord captures a conducted match as a history record without an ID.
+//
+// Parameters:
+//   - m: A pointer to the conducted match.
+//   - at: The time the match is recorded at.
+//
+// Returns:
+//   - Record: The record of the match.
+func newRecord(m *match.Match, at time.Time) Record {
+	outcome := m.Outcome()
+	record := Record{
+		Timestamp:   at,
+		Players:     [2]replay.PlayerRecord{replay.NewPlayerRecord(m.PlayerA), replay.NewPlayerRecord(m.PlayerB)},
+		Result:      outcome.String(),
+		Reason:      outcome.Reason.String(),
+		Rounds:      outcome.Rounds,
+		FinalHealth: [2]int{outcome.FinalHealthA, outcome.FinalHealthB},
+		Seed:        m.Seed(),
+		Events:      m.Events(),
+	}
+	if outcome.Winner != nil {
+		record.Winner, _, _, _ = player.GetPlayerBaseAttributes(outcome.Winner)
+	}
+	return record
+}
+
+// List returns every recorded match, oldest first. A missing history file is an empty history.
+//
+// Returns:
+//   - []Record: The recorded matches.
+//   - error: An error, if the history cannot be read.
+func (s *Store) List() ([]Record, error) {
+	file, err := os.Open(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var records []Record
+	scanner := newScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var record Record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("invalid history record on line %d: %w", line, err)
+		}
+		records = append(records, record)
+	}
+	return records, scanner.Err()
+}
+
+// Get returns the recorded match with the given ID.
+//
+// Parameters:
+//   - id: The ID of the match.
+//
+// Returns:
+//   - Record: The recorded match.
+//   - error: ErrNotFound if no match has that ID, or an error if the history cannot be read.
+func (s *Store) Get(id int) (Record, error) {
+	records, err := s.List()
+	if err != nil {
+		return Record{}, err
+	}
+	for _, record := range records {
+		if record.ID == id {
+			return record, nil
+		}
+	}
+	return Record{}, fmt.Errorf("%w: %d", ErrNotFound, id)
+}
+
+// FilterByPlayer returns the records of the matches a player with the given name took part in.
+//
+// Parameters:
+//   - records: The records to filter.
+//   - name: The name of the player.
+//
+// Returns:
+//   - []Record: The matching records, in their original order.
+func FilterByPlayer(records []Record, name string) []Record {
+	var filtered []Record
+	for _, record := range records {
+		if record.HasPlayer(name) {
+			filtered = append(filtered, record)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,103 @@
+package history
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"proj/pkg/match"
+	"proj/pkg/player"
+	"testing"
+	"time"
+)
+
+// ANSI escape codes for text color
+const (
+	redColor   = "\033[31m"
+	greenColor = "\033[32m"
+	resetColor = "\033[0m"
+)
+
+// conduct creates and conducts a seeded match for a test.
+func conduct(t *testing.T, playerA, playerB *player.Player) *match.Match {
+	t.Helper()
+	m, err := match.NewMatch(playerA, playerB, match.WithSeed(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	match.ConductMatch(m)
+	return m
+}
+
+// TestStore tests appending to and reading from the match history.
+//
+// Test scenarios:
+//  1. A store whose file does not exist yet is empty.
+//  2. Append two matches and check they are listed with increasing IDs and their outcome.
+//  3. Get a match by ID, and check an unknown ID returns ErrNotFound.
+//  4. Filter the history by player name.
+//  5. A store reopened on the same file carries on counting IDs from the last recorded match.
+func TestStore(t *testing.T) {
+	//TEST 1: empty history
+	store := Open(filepath.Join(t.TempDir(), "nested", "history.jsonl"))
+	records, err := store.List()
+	if err != nil || len(records) != 0 {
+		t.Errorf(redColor+"Expected an empty history, got %v, %v"+resetColor, records, err)
+	} else {
+		fmt.Println(greenColor + "TestStore : Test1 : Passed" + resetColor)
+	}
+
+	//TEST 2: append two matches
+	hero := player.NewPlayer("Hero", 100, 10, 5)
+	first := conduct(t, hero, player.NewPlayer("Villain", 50, 5, 2))
+	second := conduct(t, player.NewPlayer("Rogue", 60, 5, 8), player.NewPlayer("Knight", 80, 8, 4))
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	if _, err := store.Append(first, at); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Append(second, at); err != nil {
+		t.Fatal(err)
+	}
+	records, err = store.List()
+	if err != nil || len(records) != 2 || records[0].ID != 1 || records[1].ID != 2 ||
+		records[0].Result != first.Outcome().String() || records[0].Rounds != len(first.Events()) || !records[0].Timestamp.Equal(at) {
+		t.Errorf(redColor+"Expected two records in order, got %+v, %v"+resetColor, records, err)
+	} else {
+		fmt.Println(greenColor + "TestStore : Test2 : Passed" + resetColor)
+	}
+
+	//TEST 3: get by ID
+	record, err := store.Get(2)
+	_, missing := store.Get(3)
+	if err != nil || record.Players[0].Name != "Rogue" || !errors.Is(missing, ErrNotFound) {
+		t.Errorf(redColor+"Expected match 2 between Rogue and Knight, got %+v, %v, %v"+resetColor, record, err, missing)
+	} else {
+		fmt.Println(greenColor + "TestStore : Test3 : Passed" + resetColor)
+	}
+
+	//TEST 4: filter by player
+	filtered := FilterByPlayer(records, "Villain")
+	if len(filtered) != 1 || filtered[0].ID != 1 {
+		t.Errorf(redColor+"Expected only match 1 for Villain, got %+v"+resetColor, filtered)
+	} else {
+		fmt.Println(greenColor + "TestStore : Test4 : Passed" + resetColor)
+	}
+
+	//TEST 5: reopen the history
+	reopened := Open(store.path)
+	third, err := reopened.Append(first, at)
+	records, errList := reopened.List()
+	if err != nil || errList != nil || third.ID != 3 || len(records) != 3 || records[2].ID != 3 {
+		t.Errorf(redColor+"Expected match 3 to follow the recorded matches, got %+v, %v, %v"+resetColor, third, err, errList)
+	} else {
+		fmt.Println(greenColor + "TestStore : Test5 : Passed" + resetColor)
+	}
+}
+
+// TestMain runs the main testing suite.
+func TestMain(m *testing.M) {
+	fmt.Println("Testing history package...")
+	Result := m.Run()
+	fmt.Println("Testing complete.")
+	os.Exit(Result)
+}
@@ -0,0 +1,112 @@
+package match
+
+import "math/rand"
+
+// Dice is the source of every dice roll made during a match.
+//
+// Implementations must return a value in the range [1, sides] for each call to Roll.
+// Supplying a Dice to a Match makes its sequence of rounds fully reproducible.
+type Dice interface {
+	Roll(sides int) int
+}
+
+// RandomDice is a Dice backed by a seeded pseudo-random number generator.
+// Two RandomDice created with the same seed produce the same sequence of rolls.
+type RandomDice struct {
+	rng *rand.Rand // rng is the generator private to this set of dice.
+}
+
+// NewRandomDice creates a RandomDice seeded with the given value.
+//
+// Parameters:
+//   - seed: The seed for the underlying random number generator.
+//
+// Returns:
+//   - *RandomDice: A pointer to the newly created RandomDice instance.
+func NewRandomDice(seed int64) *RandomDice {
+	return &RandomDice{rng: rand.New(rand.NewSource(seed))}
+}
+
+// Roll returns a uniformly distributed value in the range [1, sides].
+//
+// Parameters:
+//   - sides: The number of sides of the die being rolled.
+//
+// Returns:
+//   - int: The rolled value.
+func (d *RandomDice) Roll(sides int) int {
+	return d.rng.Intn(sides) + 1
+}
+
+// FixedDice is a Dice that always rolls the same value, limited to the faces of the die rolled,
+// so a value of 8 rolls 6 on a six-sided die and 8 on the 100-sided dodge die.
+// It is useful for tests and tutorials where every roll should be predictable.
+type FixedDice struct {
+	value int // value is returned by every roll.
+}
+
+// NewFixedDice creates a FixedDice that always rolls the given value, or the nearest face of a
+// die that does not have it.
+//
+// Parameters:
+//   - value: The value returned by every roll.
+//
+// Returns:
+//   - *FixedDice: A pointer to the newly created FixedDice instance.
+func NewFixedDice(value int) *FixedDice {
+	return &FixedDice{value: value}
+}
+
+// Roll returns the fixed value of the dice, clamped to the range [1, sides].
+//
+// Parameters:
+//   - sides: The number of sides of the die being rolled.
+//
+// Returns:
+//   - int: The fixed value, or the nearest face of the die.
+func (d *FixedDice) Roll(sides int) int {
+	return min(max(d.value, 1), sides)
+}
+
+// ScriptedDice is a Dice that returns a scripted sequence of rolls in order, starting over
+// from the beginning once the sequence is exhausted.
+//
+// Rolls within a round are made in the order attack roll, then defence roll, so the script
+// {6, 1, 2, 3} means the first attacker rolls 6 against a defence of 1, and the second
+// attacker rolls 2 against a defence of 3. A defender whose class rolls several defence dice
+// takes that many rolls after the attack roll. With the Dodge rule, an attack that did not fumble
+// against a defender with agility then rolls a 100-sided die, which dodges the attack if it shows
+// at most their agility. A drain rolls like an attack but cannot be dodged, a fireball rolls only
+// the attack die, and the other spells, defending and healing roll nothing.
+type ScriptedDice struct {
+	rolls []int // rolls is the scripted sequence of values.
+	next  int   // next is the index of the next roll to return.
+}
+
+// NewScriptedDice creates a ScriptedDice that returns the given rolls in order. It panics if no
+// rolls are given, since the dice would have nothing to return.
+//
+// Parameters:
+//   - rolls: The sequence of values to return. It must not be empty.
+//
+// Returns:
+//   - *ScriptedDice: A pointer to the newly created ScriptedDice instance.
+func NewScriptedDice(rolls ...int) *ScriptedDice {
+	if len(rolls) == 0 {
+		panic("match: NewScriptedDice needs at least one roll")
+	}
+	return &ScriptedDice{rolls: rolls}
+}
+
+// Roll returns the next value of the scripted sequence.
+//
+// Parameters:
+//   - sides: The number of sides of the die being rolled (ignored).
+//
+// Returns:
+//   - int: The next scripted value.
+func (d *ScriptedDice) Roll(sides int) int {
+	roll := d.rolls[d.next]
+	d.next = (d.next + 1) % len(d.rolls)
+	return roll
+}
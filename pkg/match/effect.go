@@ -0,0 +1,222 @@
+package match
+
+import (
+	"fmt"
+	"strings"
+)
+
+// EffectKind is a kind of timed status effect on a player.
+type EffectKind string
+
+// The kinds of status effects. Effects last a number of the afflicted player's turns, which count
+// down at the end of each of their turns, and tick at the start or end of those turns:
+//   - poison deals poisonDamage per stack at the end of each turn and weakens the player's attack
+//     by a tenth per stack. Poisoning a poisoned player adds a stack, up to maxPoisonStacks, and
+//     restarts the effect.
+//   - burn deals burnDamage at the end of each turn and lowers the player's strength by a quarter.
+//     Burning a burning player restarts the effect, without stacking.
+//   - stun makes the player lose their next turn. Stunning a stunned player has no effect, so a
+//     player cannot be kept stunned.
+//   - regen restores regenHealing at the start of each turn. Regenerating a regenerating player
+//     extends the effect by its duration.
+const (
+	EffectPoison EffectKind = "poison"
+	EffectBurn   EffectKind = "burn"
+	EffectStun   EffectKind = "stun"
+	EffectRegen  EffectKind = "regen"
+)
+
+// The strength and duration of the status effects.
+const (
+	effectTurns     = 3 // effectTurns is the number of turns poison, burn and regen last.
+	poisonDamage    = 2 // poisonDamage is the damage of each stack of poison at the end of a turn.
+	maxPoisonStacks = 3 // maxPoisonStacks is the number of times poison can stack.
+	burnDamage      = 4 // burnDamage is the damage of burn at the end of a turn.
+	regenHealing    = 3 // regenHealing is the health regen restores at the start of a turn.
+)
+
+// Effect is a timed status effect on a player.
+type Effect struct {
+	Kind   EffectKind `json:"kind"`   // Kind is the kind of effect.
+	Turns  int        `json:"turns"`  // Turns is the number of the afflicted player's turns the effect lasts, the current one included.
+	Stacks int        `json:"stacks"` // Stacks is the number of times the effect is stacked, 1 for effects that do not stack.
+}
+
+// String renders the effect, e.g. "poison x2 for 3 turns" or "stun for 1 turn".
+//
+// Returns:
+//   - string: A description of the effect.
+func (e Effect) String() string {
+	description := string(e.Kind)
+	if e.Stacks > 1 {
+		description += fmt.Sprintf(" x%d", e.Stacks)
+	}
+	if e.Turns == 1 {
+		return description + " for 1 turn"
+	}
+	return description + fmt.Sprintf(" for %d turns", e.Turns)
+}
+
+// AppliedEffect records a status effect applied to a player during a round.
+type AppliedEffect struct {
+	Player string `json:"player"` // Player is the name of the afflicted player.
+	Effect Effect `json:"effect"` // Effect is the effect on the player once applied, stacks included.
+}
+
+// EffectTick records the change a status effect made to the health of the player whose turn it was.
+type EffectTick struct {
+	Kind   EffectKind `json:"kind"`   // Kind is the kind of effect.
+	Health int        `json:"health"` // Health is the health the effect took from the player, or restored for regen.
+}
+
+// afflict applies a status effect to a fighter following the stacking rules of its kind, recording
+// it in the event.
+//
+// Parameters:
+//   - target: A pointer to the state of the afflicted player.
+//   - effect: The effect to apply, with one stack.
+//   - event: A pointer to the event of the round.
+func afflict(target *fighter, effect Effect, event *RoundEvent) {
+	i := findEffect(target.effects, effect.Kind)
+	if i < 0 {
+		target.effects = append(target.effects, effect)
+		event.Applied = append(event.Applied, AppliedEffect{Player: target.name, Effect: effect})
+		return
+	}
+
+	current := &target.effects[i]
+	switch effect.Kind {
+	case EffectPoison:
+		current.Stacks = min(maxPoisonStacks, current.Stacks+effect.Stacks)
+		current.Turns = max(current.Turns, effect.Turns)
+	case EffectBurn:
+		current.Turns = max(current.Turns, effect.Turns)
+	case EffectStun:
+		return
+	case EffectRegen:
+		current.Turns += effect.Turns
+	}
+	event.Applied = append(event.Applied, AppliedEffect{Player: target.name, Effect: *current})
+}
+
+// startEffects ticks the effects of the player whose turn it is at the start of their turn.
+//
+// Parameters:
+//   - f: A pointer to the state of the player whose turn it is.
+//   - event: A pointer to the event of the round.
+//
+// Returns:
+//   - bool: true if the player is stunned and loses their turn, false otherwise.
+func startEffects(f *fighter, event *RoundEvent) bool {
+	if findEffect(f.effects, EffectRegen) >= 0 {
+		event.Ticks = append(event.Ticks, EffectTick{Kind: EffectRegen, Health: heal(f, regenHealing)})
+	}
+	return findEffect(f.effects, EffectStun) >= 0
+}
+
+// endEffects ticks the effects of the player whose turn it is at the end of their turn, then counts
+// their effects down and removes those that expired.
+//
+// Parameters:
+//   - f: A pointer to the state of the player whose turn it is.
+//   - event: A pointer to the event of the round.
+func endEffects(f *fighter, event *RoundEvent) {
+	for _, effect := range f.effects {
+		damage := 0
+		switch effect.Kind {
+		case EffectPoison:
+			damage = poisonDamage * effect.Stacks
+		case EffectBurn:
+			damage = burnDamage
+		default:
+			continue
+		}
+		damage = min(damage, f.health)
+		f.health -= damage
+		event.Ticks = append(event.Ticks, EffectTick{Kind: effect.Kind, Health: damage})
+	}
+
+	remaining := f.effects[:0]
+	for _, effect := range f.effects {
+		if effect.Turns--; effect.Turns > 0 {
+			remaining = append(remaining, effect)
+		} else {
+			event.Expired = append(event.Expired, effect.Kind)
+		}
+	}
+	f.effects = remaining
+}
+
+// effectiveAttack is the attack of a fighter once weakened by poison.
+//
+// Parameters:
+//   - f: A pointer to the state of the player.
+//
+// Returns:
+//   - int: The attack of the player.
+func effectiveAttack(f *fighter) int {
+	if i := findEffect(f.effects, EffectPoison); i >= 0 {
+		return f.attack * (10 - f.effects[i].Stacks) / 10
+	}
+	return f.attack
+}
+
+// effectiveStrength is the strength of a fighter once lowered by burn.
+//
+// Parameters:
+//   - f: A pointer to the state of the player.
+//
+// Returns:
+//   - int: The strength of the player.
+func effectiveStrength(f *fighter) int {
+	if findEffect(f.effects, EffectBurn) >= 0 {
+		return f.strength * 3 / 4
+	}
+	return f.strength
+}
+
+// findEffect finds an effect of a kind among the effects on a player.
+//
+// Parameters:
+//   - effects: The effects on the player.
+//   - kind: The kind of effect.
+//
+// Returns:
+//   - int: The index of the effect, or -1 if the player has no effect of that kind.
+func findEffect(effects []Effect, kind EffectKind) int {
+	for i, effect := range effects {
+		if effect.Kind == kind {
+			return i
+		}
+	}
+	return -1
+}
+
+// describeEffects renders the effects applied, ticked and expired during a round as sentences to
+// follow the description of the round, e.g. ". Villain now has poison for 3 turns".
+//
+// Parameters:
+//   - e: The event of the round.
+//
+// Returns:
+//   - string: The sentences, each starting with ". ", or "" if no effect changed.
+func describeEffects(e RoundEvent) string {
+	var sentences []string
+	for _, applied := range e.Applied {
+		sentences = append(sentences, fmt.Sprintf("%s now has %s", applied.Player, applied.Effect))
+	}
+	for _, tick := range e.Ticks {
+		if tick.Kind == EffectRegen {
+			sentences = append(sentences, fmt.Sprintf("%s regenerated %d health", e.Attacker, tick.Health))
+		} else {
+			sentences = append(sentences, fmt.Sprintf("%s took %d %s damage", e.Attacker, tick.Health, tick.Kind))
+		}
+	}
+	for _, kind := range e.Expired {
+		sentences = append(sentences, fmt.Sprintf("The %s on %s wore off", kind, e.Attacker))
+	}
+	if len(sentences) == 0 {
+		return ""
+	}
+	return ". " + strings.Join(sentences, ". ")
+}
@@ -0,0 +1,103 @@
+package match
+
+import (
+	"fmt"
+	"proj/pkg/player"
+	"reflect"
+	"testing"
+)
+
+// TestEffects tests the status effects and the spells that apply them.
+//
+// Test scenarios:
+//  1. venom poisons the defender, who takes 2 damage at the end of their turn and attacks with a
+//     tenth less attack.
+//  2. Poison stacks up to 3 times, each stack adding damage and weakening the attack further.
+//  3. ignite sets the defender burning, lowering their strength by a quarter so attacks hurt them
+//     more, and burning them again restarts the effect without stacking.
+//  4. daze stuns the defender, who loses their next turn whatever their action, with a single
+//     certain way for it to play out; dazing a stunned player has no effect.
+//  5. renew restores 3 health at once and at the start of the caster's next 2 turns, then wears
+//     off; renewing while it lasts extends it.
+func TestEffects(t *testing.T) {
+	rules := DefaultRules()
+	newFighters := func() (*fighter, *fighter) {
+		return newFighter(player.NewPlayer("PlayerA", 100, 10, 10, player.WithMana(30))), newFighter(player.NewPlayer("PlayerB", 100, 10, 10))
+	}
+
+	//TEST 1: venom
+	caster, defender := newFighters()
+	cast := conductRound(NewScriptedDice(1), rules, caster, defender, Action{Kind: ActionCast, Spell: SpellVenom})
+	poisoned := conductRound(NewScriptedDice(2, 1), rules, defender, caster, Action{Kind: ActionAttack})
+	if !reflect.DeepEqual(cast.Applied, []AppliedEffect{{Player: "PlayerB", Effect: Effect{Kind: EffectPoison, Turns: 3, Stacks: 1}}}) ||
+		cast.String() != "PlayerA cast venom on PlayerB. PlayerB now has poison for 3 turns" ||
+		poisoned.Damage != 8 || defender.health != 98 || defender.effects[0].Turns != 2 ||
+		poisoned.String() != "PlayerB attacked PlayerA for 8 damage. PlayerB took 2 poison damage" {
+		t.Errorf(redColor+"Expected poison to weaken the attack and deal 2 damage, got %+v and %+v"+resetColor, cast, poisoned)
+	} else {
+		fmt.Println(greenColor + "TestEffects : Test1 : Passed" + resetColor)
+	}
+
+	//TEST 2: poison stacks
+	caster, defender = newFighters()
+	var event RoundEvent
+	for i := 0; i < 4; i++ {
+		afflict(defender, Effect{Kind: EffectPoison, Turns: 3, Stacks: 1}, &event)
+	}
+	poisoned = conductRound(NewScriptedDice(2, 1), rules, defender, caster, Action{Kind: ActionAttack})
+	if len(defender.effects) != 1 || event.Applied[3].Effect.Stacks != 3 || poisoned.Damage != 4 || defender.health != 94 {
+		t.Errorf(redColor+"Expected 3 stacks of poison, got %v, %+v and %d health"+resetColor, defender.effects, poisoned, defender.health)
+	} else {
+		fmt.Println(greenColor + "TestEffects : Test2 : Passed" + resetColor)
+	}
+
+	//TEST 3: ignite
+	caster, defender = newFighters()
+	conductRound(NewScriptedDice(1), rules, caster, defender, Action{Kind: ActionCast, Spell: SpellIgnite})
+	burning := conductRound(NewScriptedDice(1, 1), rules, defender, caster, Action{Kind: ActionAttack})
+	struck := conductRound(NewScriptedDice(2, 2), rules, caster, defender, Action{Kind: ActionAttack})
+	event = RoundEvent{}
+	afflict(defender, Effect{Kind: EffectBurn, Turns: 3, Stacks: 1}, &event)
+	if burning.Ticks[0] != (EffectTick{Kind: EffectBurn, Health: 4}) || struck.Damage != 6 || defender.health != 90 ||
+		!reflect.DeepEqual(defender.effects, []Effect{{Kind: EffectBurn, Turns: 3, Stacks: 1}}) {
+		t.Errorf(redColor+"Expected burn to deal 4 damage and lower strength, got %+v, %+v and %v"+resetColor, burning, struck, defender.effects)
+	} else {
+		fmt.Println(greenColor + "TestEffects : Test3 : Passed" + resetColor)
+	}
+
+	//TEST 4: daze
+	caster, defender = newFighters()
+	conductRound(NewScriptedDice(1), rules, caster, defender, Action{Kind: ActionCast, Spell: SpellDaze})
+	turn := Turn{Round: 2, Attacker: defender.state(), Defender: caster.state(), Rules: rules}
+	branches, _ := turn.Branches(Action{Kind: ActionAttack})
+	event = RoundEvent{}
+	afflict(defender, Effect{Kind: EffectStun, Turns: 1, Stacks: 1}, &event)
+	stunned := conductRound(NewScriptedDice(6, 1), rules, defender, caster, Action{Kind: ActionAttack})
+	if !stunned.Stunned || caster.health != 100 || len(defender.effects) != 0 || len(event.Applied) != 0 ||
+		len(turn.Actions()) != 1 || len(branches) != 1 || branches[0].Probability != 1 ||
+		stunned.String() != "PlayerB was stunned and lost their turn. The stun on PlayerB wore off" {
+		t.Errorf(redColor+"Expected PlayerB to lose their turn, got %+v and %v"+resetColor, stunned, branches)
+	} else {
+		fmt.Println(greenColor + "TestEffects : Test4 : Passed" + resetColor)
+	}
+
+	//TEST 5: renew
+	caster, defender = newFighters()
+	caster.health = 50
+	renewed := conductRound(NewScriptedDice(1), rules, caster, defender, Action{Kind: ActionCast, Spell: SpellRenew})
+	var ticks []string
+	for i := 0; i < 3; i++ {
+		ticks = append(ticks, conductRound(NewScriptedDice(1, 1), rules, caster, defender, Action{Kind: ActionDefend}).String())
+	}
+	event = RoundEvent{}
+	afflict(caster, Effect{Kind: EffectRegen, Turns: 3, Stacks: 1}, &event)
+	afflict(caster, Effect{Kind: EffectRegen, Turns: 3, Stacks: 1}, &event)
+	if renewed.String() != "PlayerA cast renew and recovered 3 health. PlayerA now has regen for 3 turns" || caster.health != 59 ||
+		ticks[0] != "PlayerA took a defensive stance. PlayerA regenerated 3 health" ||
+		ticks[1] != "PlayerA took a defensive stance. PlayerA regenerated 3 health. The regen on PlayerA wore off" ||
+		ticks[2] != "PlayerA took a defensive stance" || caster.effects[0].Turns != 6 {
+		t.Errorf(redColor+"Expected renew to restore 9 health over 3 turns, got %q, %q and %d health"+resetColor, renewed, ticks, caster.health)
+	} else {
+		fmt.Println(greenColor + "TestEffects : Test5 : Passed" + resetColor)
+	}
+}
@@ -0,0 +1,11 @@
+package match
+
+import "errors"
+
+// Errors returned by NewMatch, usable with errors.Is. Invalid player attributes are reported
+// with the errors of player.Validate.
+var (
+	ErrDuplicateName         = errors.New("player names must be unique")
+	ErrAttackCannotPenetrate = errors.New("attack is too low to damage the opponent")
+	ErrInvalidRules          = errors.New("invalid rules")
+)
@@ -0,0 +1,115 @@
+package match
+
+import "fmt"
+
+// RoundEvent records everything that happened during a single round of a match.
+type RoundEvent struct {
+	Round                int             `json:"round"`                // Round is the 1-based number of the round within the match.
+	Attacker             string          `json:"attacker"`             // Attacker is the name of the player who attacked this round.
+	Defender             string          `json:"defender"`             // Defender is the name of the player who defended this round.
+	AttackRoll           int             `json:"attackRoll"`           // AttackRoll is the value rolled on the attacker's die.
+	DefenceRoll          int             `json:"defenceRoll"`          // DefenceRoll is the value rolled on the defender's die.
+	Damage               int             `json:"damage"`               // Damage is the damage dealt to the defender.
+	DefenderHealthBefore int             `json:"defenderHealthBefore"` // DefenderHealthBefore is the defender's health at the start of the round.
+	DefenderHealthAfter  int             `json:"defenderHealthAfter"`  // DefenderHealthAfter is the defender's health at the end of the round.
+	Critical             bool            `json:"critical,omitempty"`   // Critical is true if the attack was a critical hit.
+	Fumble               bool            `json:"fumble,omitempty"`     // Fumble is true if the attacker fumbled and missed.
+	Dodged               bool            `json:"dodged,omitempty"`     // Dodged is true if the defender dodged the attack.
+	Action               ActionKind      `json:"action,omitempty"`     // Action is the action the attacker took instead of attacking, if any.
+	Spell                string          `json:"spell,omitempty"`      // Spell is the name of the spell the attacker cast, if any.
+	Healing              int             `json:"healing,omitempty"`    // Healing is the health the attacker's spell restored to them.
+	Absorbed             int             `json:"absorbed,omitempty"`   // Absorbed is the damage the defender's shield kept from them.
+	Stunned              bool            `json:"stunned,omitempty"`    // Stunned is true if the attacker was stunned and lost their turn.
+	Applied              []AppliedEffect `json:"applied,omitempty"`    // Applied are the status effects the attacker's action applied to either player.
+	Ticks                []EffectTick    `json:"ticks,omitempty"`      // Ticks are the changes the attacker's status effects made to their health.
+	Expired              []EffectKind    `json:"expired,omitempty"`    // Expired are the status effects on the attacker that wore off after their turn.
+}
+
+// String renders the event as a human-readable sentence, e.g. "Hero attacked Villain for 20 damage",
+// "Hero attacked Villain for 40 damage with a critical hit", "Hero fumbled the attack on Villain",
+// "Villain dodged the attack of Hero", "Hero cast fireball on Villain for 30 damage" or
+// "Hero cast heal and recovered 25 health" or "Hero took a defensive stance". Damage kept off by a
+// shield is mentioned at the end, followed by the status effects applied, ticked and expired, e.g.
+// "Hero cast venom on Villain. Villain now has poison for 3 turns".
+//
+// Returns:
+//   - string: A description of the round.
+func (e RoundEvent) String() string {
+	return e.describeAction() + describeEffects(e)
+}
+
+// describeAction renders what the attacker did during the round, without its status effects.
+//
+// Returns:
+//   - string: A description of the attacker's turn.
+func (e RoundEvent) describeAction() string {
+	var description string
+	switch {
+	case e.Stunned:
+		return fmt.Sprintf("%s was stunned and lost their turn", e.Attacker)
+	case e.Action == ActionDefend:
+		return fmt.Sprintf("%s took a defensive stance", e.Attacker)
+	case e.Action == ActionHeal:
+		description = fmt.Sprintf("%s tended their wounds", e.Attacker)
+	case e.Spell == SpellHeal || e.Spell == SpellShield || e.Spell == SpellRenew:
+		description = fmt.Sprintf("%s cast %s", e.Attacker, e.Spell)
+	case e.Spell == SpellVenom || e.Spell == SpellIgnite || e.Spell == SpellDaze:
+		return fmt.Sprintf("%s cast %s on %s", e.Attacker, e.Spell, e.Defender)
+	case e.Spell != "":
+		description = fmt.Sprintf("%s cast %s on %s for %d damage", e.Attacker, e.Spell, e.Defender, e.Damage)
+	case e.Fumble:
+		return fmt.Sprintf("%s fumbled the attack on %s", e.Attacker, e.Defender)
+	case e.Dodged:
+		return fmt.Sprintf("%s dodged the attack of %s", e.Defender, e.Attacker)
+	case e.Critical:
+		description = fmt.Sprintf("%s attacked %s for %d damage with a critical hit", e.Attacker, e.Defender, e.Damage)
+	default:
+		description = fmt.Sprintf("%s attacked %s for %d damage", e.Attacker, e.Defender, e.Damage)
+	}
+	if e.Action == ActionHeal || e.Spell == SpellHeal || e.Spell == SpellRenew || e.Healing > 0 {
+		description += fmt.Sprintf(" and recovered %d health", e.Healing)
+	}
+	if e.Absorbed > 0 {
+		description += fmt.Sprintf(", %d absorbed by the shield of %s", e.Absorbed, e.Defender)
+	}
+	return description
+}
+
+// ChosenAction returns the action the attacker took in the round, so that the round can be
+// played again with PlayTurn.
+//
+// Returns:
+//   - Action: The action of the attacker.
+func (e RoundEvent) ChosenAction() Action {
+	switch {
+	case e.Spell != "":
+		return Action{Kind: ActionCast, Spell: e.Spell}
+	case e.Action != "":
+		return Action{Kind: e.Action}
+	}
+	return Action{Kind: ActionAttack}
+}
+
+// Events returns the events recorded for each round of the match so far, in order.
+// The returned slice is a copy and may be modified freely.
+//
+// Returns:
+//   - []RoundEvent: The recorded round events.
+func (m *Match) Events() []RoundEvent {
+	events := make([]RoundEvent, len(m.events))
+	copy(events, m.events)
+	return events
+}
+
+// RoundResults returns a human-readable description of each round of the match so far,
+// derived from the recorded round events.
+//
+// Returns:
+//   - []string: A slice containing descriptions of each round result.
+func (m *Match) RoundResults() []string {
+	results := make([]string, len(m.events))
+	for i, event := range m.events {
+		results[i] = event.String()
+	}
+	return results
+}
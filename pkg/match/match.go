@@ -3,59 +3,195 @@ package match
 // Import the player package to use the Player struct.
 import (
 	"fmt"
-	"math/rand"
 	"proj/pkg/player"
+	"time"
 )
 
 // Match represents a match between two players in the Magical Arena.
 type Match struct {
-	PlayerA      *player.Player // PlayerA is a pointer to the first player in the match.
-	PlayerB      *player.Player // PlayerB is a pointer to the second player in the match.
-	roundResults []string       // RoundResults stores the results of each round in the match.
-	result       string         // Result indicates the overall result of the match (e.g., "PlayerA wins", "Draw", etc.).
+	PlayerA *player.Player // PlayerA is a pointer to the first player in the match.
+	PlayerB *player.Player // PlayerB is a pointer to the second player in the match.
+	events  []RoundEvent   // Events stores what happened in each round of the match.
+	outcome MatchOutcome   // Outcome describes the overall result of the match.
+	seed    int64          // Seed is the value the match dice were seeded with.
+	dice    Dice           // Dice is the source of every roll made during the match.
+	seeded  bool           // Seeded reports whether the dice were derived from the seed.
+	rules   Rules          // Rules are the mechanics the match is played under.
+
+	a, b               *fighter // a and b are the states of Player A and Player B once the match has started.
+	attacker, defender *fighter // attacker is the state of the player whose turn it is, defender that of their opponent.
+	strategyA          Strategy // strategyA chooses the actions of Player A, or is nil for the default.
+	strategyB          Strategy // strategyB chooses the actions of Player B, or is nil for the default.
+}
+
+// Option configures optional settings of a Match when it is created with NewMatch.
+type Option func(*Match)
+
+// WithSeed seeds the match dice with the given value, so that the same seed always yields
+// the same sequence of rounds.
+//
+// Parameters:
+//   - seed: The seed for the match dice.
+//
+// Returns:
+//   - Option: An option to pass to NewMatch.
+func WithSeed(seed int64) Option {
+	return func(m *Match) {
+		m.seed = seed
+	}
+}
+
+// WithDice makes the match roll the provided dice instead of seeded random dice.
+//
+// Parameters:
+//   - dice: The dice used for every roll in the match.
+//
+// Returns:
+//   - Option: An option to pass to NewMatch.
+func WithDice(dice Dice) Option {
+	return func(m *Match) {
+		m.dice = dice
+	}
 }
 
 // NewMatch creates and initializes a new Match instance with the provided players.
+// Unless configured otherwise, the match rolls random dice seeded from the current time;
+// the seed is recorded on the match and can be retrieved with Seed.
+//
+// The players and rules are validated before the match is created: both players must pass
+// player.Validate, have different names, and be able to damage each other under the rules.
 //
 // Parameters:
 //   - playerA: A pointer to the first player in the match.
 //   - playerB: A pointer to the second player in the match.
+//   - opts: Optional settings such as WithSeed, WithDice, WithRules or WithStrategies.
 //
 // Returns:
 //   - *Match: A pointer to the newly created Match instance.
-func NewMatch(playerA, playerB *player.Player) *Match {
-	return &Match{playerA, playerB, []string{}, ""}
+//   - error: An error usable with errors.Is, such as player.ErrNonPositiveHealth, ErrDuplicateName,
+//     ErrAttackCannotPenetrate or ErrInvalidRules, if the match cannot be played.
+//
+// Example:
+//
+//	m, err := NewMatch(playerA, playerB, WithSeed(42))
+func NewMatch(playerA, playerB *player.Player, opts ...Option) (*Match, error) {
+	m := &Match{
+		PlayerA: playerA,
+		PlayerB: playerB,
+		events:  []RoundEvent{},
+		seed:    time.Now().UnixNano(),
+		rules:   DefaultRules(),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	if m.strategyA != nil || m.strategyB != nil {
+		m.rules = m.rules.WithSafetyLimit()
+	}
+	if err := validate(m); err != nil {
+		return nil, err
+	}
+	if m.dice == nil {
+		m.dice = NewRandomDice(m.seed)
+		m.seeded = true
+	}
+	return m, nil
+}
+
+// validate checks that a match between its players can be played under its rules.
+//
+// Parameters:
+//   - m: A pointer to the Match to validate.
+//
+// Returns:
+//   - error: An error describing the first problem found, or nil if the match is valid.
+func validate(m *Match) error {
+	if err := m.rules.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidRules, err)
+	}
+	if err := player.Validate(m.PlayerA); err != nil {
+		return err
+	}
+	if err := player.Validate(m.PlayerB); err != nil {
+		return err
+	}
+
+	nameA, _, _, _ := player.GetPlayerBaseAttributes(m.PlayerA)
+	nameB, _, _, _ := player.GetPlayerBaseAttributes(m.PlayerB)
+	if nameA == nameB {
+		return fmt.Errorf("%w: both players are named %s", ErrDuplicateName, nameA)
+	}
+	if !NewStrike(m.rules, m.PlayerA, m.PlayerB).CanPenetrate() {
+		return fmt.Errorf("%w: %s cannot damage %s", ErrAttackCannotPenetrate, nameA, nameB)
+	}
+	if !NewStrike(m.rules, m.PlayerB, m.PlayerA).CanPenetrate() {
+		return fmt.Errorf("%w: %s cannot damage %s", ErrAttackCannotPenetrate, nameB, nameA)
+	}
+	return nil
+}
+
+// Seed returns the seed the match dice were created with.
+//
+// Returns:
+//   - int64: The seed of the match.
+func (m *Match) Seed() int64 {
+	return m.seed
+}
+
+// Reproducible reports whether the match can be re-simulated from its seed alone, which is
+// the case unless custom dice were supplied with WithDice.
+//
+// Returns:
+//   - bool: true if the match rolls dice derived from its seed, false otherwise.
+func (m *Match) Reproducible() bool {
+	return m.seeded
 }
 
 // ConductMatch simulates a match between two players in the magical arena.
 // The player with lower health attacks first, and rounds are conducted until the match is over (player.health <= 0).
-// The result of each round and the overall match result are recorded.
+// If the rules set a round limit and it is reached first, the tie-break rule decides the match.
+// The events of each round and the overall match result are recorded; see Events for the structured round log.
+//
+// Each player's action is picked by their strategy, if given one with WithStrategies. Otherwise a
+// player with mana casts a spell whenever one is worth casting, and attacks when none is. A match
+// already played in part with PlayTurn carries on from where it stands.
 //
 // Parameters:
 //   - match: A pointer to the Match instance representing the ongoing match (type *Match).
 //
 // Returns:
 //   - []string: A slice containing descriptions of each round result.
-//   - string: A string indicating the result of the entire match.
-func ConductMatch(match *Match) ([]string, string) {
-	currentPlayer := determineStartingPlayer(match)
-
-	nameA, healthA, strengthA, attackA := player.GetPlayerBaseAttributes(match.PlayerA)
-	nameB, healthB, strengthB, attackB := player.GetPlayerBaseAttributes(match.PlayerB)
-
-	for !isMatchOver(healthA, healthB) {
-		roundResult, currentHealthA, currentHealthB := conductRound(currentPlayer, nameA, healthA, strengthA, attackA, nameB, healthB, strengthB, attackB)
-		match.roundResults = append(match.roundResults, roundResult)
-		healthA = currentHealthA
-		healthB = currentHealthB
-		switchCurrentPlayer(&currentPlayer, match.PlayerA, match.PlayerB)
+//   - MatchOutcome: The outcome of the entire match; its String method renders it as e.g. "PlayerA wins".
+func ConductMatch(match *Match) ([]string, MatchOutcome) {
+	for !match.Over() {
+		match.playTurn(match.chooseAction())
+	}
+
+	a, b := match.a, match.b
+	reason, winner := MatchResult(a.name, a.health, b.name, b.health)
+	if reason == Draw && !isMatchOver(a.health, b.health) {
+		reason, winner = breakTie(match.rules.TieBreak, a.name, a.health, a.startHealth, b.name, b.health, b.startHealth)
 	}
 
-	match.result = MatchResult(nameA, healthA, nameB, healthB)
-	return match.roundResults, match.result
+	match.outcome = MatchOutcome{
+		FinalHealthA:   a.health,
+		FinalHealthB:   b.health,
+		Rounds:         len(match.events),
+		StartingPlayer: determineStartingPlayer(match),
+		Reason:         reason,
+	}
+	switch {
+	case reason == Draw:
+	case winner == a.name:
+		match.outcome.Winner, match.outcome.Loser = match.PlayerA, match.PlayerB
+	default:
+		match.outcome.Winner, match.outcome.Loser = match.PlayerB, match.PlayerA
+	}
+	return match.RoundResults(), match.outcome
 }
 
-// determineStartingPlayer determines the starting player for a match based on their health attributes.
+// determineStartingPlayer determines the starting player for a match according to the first-mover rule,
+// which by default compares the players' health attributes.
 //
 // Parameters:
 //   - match: A pointer to the Match instance representing the ongoing match.
@@ -66,12 +202,32 @@ func determineStartingPlayer(match *Match) *player.Player {
 	_, healthA, _, _ := player.GetPlayerBaseAttributes(match.PlayerA)
 	_, healthB, _, _ := player.GetPlayerBaseAttributes(match.PlayerB)
 
+	switch match.rules.FirstMover {
+	case FirstMoverPlayerA:
+		return match.PlayerA
+	case FirstMoverPlayerB:
+		return match.PlayerB
+	case FirstMoverHigherHealth:
+		if healthA >= healthB {
+			return match.PlayerA
+		}
+		return match.PlayerB
+	}
+
 	if healthA <= healthB {
 		return match.PlayerA
 	}
 	return match.PlayerB
 }
 
+// StartingPlayer returns the player who attacks first in the match.
+//
+// Returns:
+//   - *player.Player: A pointer to the player who starts the match.
+func (m *Match) StartingPlayer() *player.Player {
+	return determineStartingPlayer(m)
+}
+
 // GetDeterminStartingPlayer is a helper function that exposes the private determineStartingPlayer function for testing purposes.
 //
 // Parameters:
@@ -95,6 +251,18 @@ func isMatchOver(healthA, healthB int) bool {
 	return healthA <= 0 || healthB <= 0
 }
 
+// isRoundLimitReached checks whether a match has played the maximum number of rounds allowed by its rules.
+//
+// Parameters:
+//   - rules: The rules of the match.
+//   - rounds: The number of rounds played so far.
+//
+// Returns:
+//   - bool: true if the round limit is reached, false otherwise or if the rules set no limit.
+func isRoundLimitReached(rules Rules, rounds int) bool {
+	return rules.MaxRounds > 0 && rounds >= rules.MaxRounds
+}
+
 // GetIsMatchOver is a helper function that exposes the private isMatchOver function for testing purposes.
 //
 // Parameters:
@@ -107,72 +275,176 @@ func GetIsMatchOver(healthA, healthB int) bool {
 	return isMatchOver(healthA, healthB)
 }
 
-// conductRound simulates a single round of a match between two players.
+// fighter is the state of a player during a match. The player's own attributes are never
+// changed by a match; the fighter tracks their current health and mana instead.
+type fighter struct {
+	name        string         // name is the name of the player.
+	health      int            // health is the current health of the player.
+	startHealth int            // startHealth is the health of the player at the start of the match, which healing never exceeds.
+	strength    int            // strength is the strength attribute of the player.
+	attack      int            // attack is the attack attribute of the player.
+	agility     int            // agility is the agility attribute of the player.
+	class       player.Class   // class is the character class of the player.
+	mana        int            // mana is the mana the player has left to cast spells.
+	maxMana     int            // maxMana is the mana pool of the player, which regenerating mana never exceeds.
+	cooldowns   map[string]int // cooldowns holds the number of turns until each spell the player cast can be cast again.
+	healReady   int            // healReady is the number of turns until the player can heal again.
+	shielded    bool           // shielded is true while a shield spell protects the player.
+	defending   bool           // defending is true while the player's strength roll counts double, until their next turn.
+	effects     []Effect       // effects are the status effects on the player, in the order they were first applied.
+}
+
+// newFighter prepares a player to fight a match, at full health and mana.
 //
 // Parameters:
-//   - currentPlayer: A pointer to the current player (type *player.Player).
-//   - nameA: The name of Player A.
-//   - healthA: The current health of Player A.
-//   - strengthA: The strength attribute of Player A.
-//   - attackA: The attack attribute of Player A.
-//   - nameB: The name of Player B.
-//   - healthB: The current health of Player B.
-//   - strengthB: The strength attribute of Player B.
-//   - attackB: The attack attribute of Player B.
+//   - p: A pointer to the player.
 //
 // Returns:
-//   - string: A description of the round result.
-//   - int: The updated health of Player A.
-//   - int: The updated health of Player B.
+//   - *fighter: A pointer to the player's state in the match.
+func newFighter(p *player.Player) *fighter {
+	name, health, strength, attack := player.GetPlayerBaseAttributes(p)
+	mana := player.GetPlayerMana(p)
+	return &fighter{
+		name:        name,
+		health:      health,
+		startHealth: health,
+		strength:    strength,
+		attack:      attack,
+		agility:     player.GetPlayerAgility(p),
+		class:       player.GetPlayerClass(p),
+		mana:        mana,
+		maxMana:     mana,
+		cooldowns:   map[string]int{},
+	}
+}
+
+// conductRound simulates a single round of a match: the attacker's turn, in which they take an
+// action. Under rules with critical hits, fumbles or dodging, the event records whether any of them
+// happened. The attacker's status effects tick at the start and end of their turn, and a stunned
+// attacker loses their turn whatever their action. After their turn, the attacker regenerates mana,
+// their spells and heal cool down and their status effects count down.
 //
-// Note: The function calculates the damage inflicted by the current player on the opponent based on random dice rolls,
+// Parameters:
+//   - dice: The dice used for the attack and defence rolls.
+//   - rules: The rules of the match, which determine the dice and the damage formula.
+//   - attacker: A pointer to the state of the attacking player.
+//   - defender: A pointer to the state of the defending player, whose health is reduced by the damage.
+//   - action: The action of the attacker, which must be one they can take.
 //
-//	considering the attack and defense attributes of both players.
-func conductRound(currentPlayer *player.Player, nameA string, healthA int, strengthA int, attackA int, nameB string, healthB int, strengthB int, attackB int) (string, int, int) {
-	playerName, _, _, _ := player.GetPlayerBaseAttributes(currentPlayer)
-
-	roundResult := ""
-	currentHealthB := healthB
-	currentHealthA := healthA
+// Returns:
+//   - RoundEvent: The event describing the round. Its Round number is left for the caller to assign.
+//
+// Note: The function calculates the damage inflicted on the defender based on dice rolls, considering
+//
+//	the attack and strength attributes of both players and the dice and weights of their classes.
+func conductRound(dice Dice, rules Rules, attacker, defender *fighter, action Action) RoundEvent {
+	event := RoundEvent{Attacker: attacker.name, Defender: defender.name, DefenderHealthBefore: defender.health}
+	event.Stunned = startEffects(attacker, &event)
+	switch {
+	case event.Stunned:
+	case action.Kind == ActionDefend:
+		event.Action = ActionDefend
+		attacker.defending = true
+	case action.Kind == ActionHeal:
+		event.Action = ActionHeal
+		event.Healing = heal(attacker, max(1, attacker.startHealth/10))
+		// Like a spell cooldown, it counts down at the end of this turn too.
+		attacker.healReady = rules.HealCooldown + 1
+	case action.Kind == ActionCast:
+		spell, _ := FindSpell(action.Spell)
+		event.Action = ActionCast
+		cast(dice, rules, spell, attacker, defender, &event)
+	default:
+		attackDefender(dice, rules, attacker, defender, &event)
+	}
+	event.DefenderHealthAfter = defender.health
+	endEffects(attacker, &event)
 
-	if playerName == nameA {
-		attackFromCurrentPlayer := attackA * (rand.Intn(6) + 1)
-		defenceFromOtherPlayer := strengthB * (rand.Intn(6) + 1)
-		damageToOtherPlayer := max(0, attackFromCurrentPlayer-defenceFromOtherPlayer)
-		currentHealthB = max(0, healthB-damageToOtherPlayer)
-		roundResult = fmt.Sprintf("%s attacked %s for %d damage", nameA, nameB, damageToOtherPlayer)
+	// The defender's stance lasts until their own turn comes round.
+	defender.defending = false
+	attacker.mana = min(attacker.maxMana, attacker.mana+rules.ManaRegen)
+	for name, turns := range attacker.cooldowns {
+		attacker.cooldowns[name] = max(0, turns-1)
 	}
+	attacker.healReady = max(0, attacker.healReady-1)
+	return event
+}
 
-	if playerName == nameB {
-		attackFromCurrentPlayer := attackB * (rand.Intn(6) + 1)
-		defenceFromOtherPlayer := strengthA * (rand.Intn(6) + 1)
-		damageToOtherPlayer := max(0, attackFromCurrentPlayer-defenceFromOtherPlayer)
-		currentHealthA = max(0, healthA-damageToOtherPlayer)
-		roundResult = fmt.Sprintf("%s attacked %s for %d damage", nameB, nameA, damageToOtherPlayer)
+// attackDefender makes the attacker attack the defender with their dice, recording the rolls and the
+// damage in the event.
+//
+// Parameters:
+//   - dice: The dice used for the attack and defence rolls.
+//   - rules: The rules of the match.
+//   - attacker: A pointer to the state of the attacking player.
+//   - defender: A pointer to the state of the defending player.
+//   - event: A pointer to the event of the round.
+func attackDefender(dice Dice, rules Rules, attacker, defender *fighter, event *RoundEvent) {
+	s := strikeOn(rules, attacker, defender)
+	event.AttackRoll, event.DefenceRoll = s.roll(dice)
+	event.Fumble = s.IsFumble(event.AttackRoll)
+	event.Dodged = !event.Fumble && s.dodges(dice)
+	if !event.Dodged {
+		event.Critical = s.IsCritical(event.AttackRoll)
+		hurt(defender, s.Damage(event.AttackRoll, event.DefenceRoll), event)
 	}
+}
 
-	if playerName == "testA" {
-		attackFromCurrentPlayer := attackA * 4
-		defenceFromOtherPlayer := strengthB * 4
-		damageToOtherPlayer := max(0, attackFromCurrentPlayer-defenceFromOtherPlayer)
-		currentHealthB = max(0, healthB-damageToOtherPlayer)
-		roundResult = fmt.Sprintf("%s attacked %s for %d damage", nameA, nameB, damageToOtherPlayer)
+// strikeOn describes how one fighter strikes another. The strength roll of a defending fighter
+// counts double, and the status effects of both fighters weaken their attack and strength.
+//
+// Parameters:
+//   - rules: The rules of the match.
+//   - attacker: A pointer to the state of the attacking player.
+//   - defender: A pointer to the state of the defending player.
+//
+// Returns:
+//   - Strike: The dice and weights of the strike.
+func strikeOn(rules Rules, attacker, defender *fighter) Strike {
+	s := newStrike(rules, effectiveAttack(attacker), attacker.class, effectiveStrength(defender), defender.agility, defender.class)
+	if defender.defending {
+		s.DefenceWeight *= 2
 	}
+	return s
+}
 
-	if playerName == "testB" {
-		attackFromCurrentPlayer := attackB * 4
-		defenceFromOtherPlayer := strengthA * 4
-		damageToOtherPlayer := max(0, attackFromCurrentPlayer-defenceFromOtherPlayer)
-		currentHealthA = max(0, healthA-damageToOtherPlayer)
-		roundResult = fmt.Sprintf("%s attacked %s for %d damage", nameB, nameA, damageToOtherPlayer)
+// hurt deals damage to a fighter, recording it in the event. A shielded fighter takes half the
+// damage, which uses up their shield.
+//
+// Parameters:
+//   - target: A pointer to the state of the player taking the damage.
+//   - damage: The damage dealt before any shield.
+//   - event: A pointer to the event of the round.
+func hurt(target *fighter, damage int, event *RoundEvent) {
+	if target.shielded && damage > 0 {
+		event.Absorbed = damage - damage/2
+		damage /= 2
+		target.shielded = false
 	}
+	event.Damage = damage
+	target.health = max(0, target.health-damage)
+}
 
-	return roundResult, currentHealthA, currentHealthB
+// heal restores health to a fighter, never above their starting health.
+//
+// Parameters:
+//   - target: A pointer to the state of the player to heal.
+//   - amount: The health to restore.
+//
+// Returns:
+//   - int: The health actually restored.
+func heal(target *fighter, amount int) int {
+	restored := max(0, min(amount, target.startHealth-target.health))
+	target.health += restored
+	return restored
 }
 
 // GetConductRound is a wrapper function that exposes the conductRound functionality for testing purposes.
+// The players fight without a class or mana.
 //
 // Parameters:
+//   - dice: The dice used for the attack and defence rolls.
+//   - rules: The rules of the match, which determine the number of sides of each die and the damage formula.
 //   - currentPlayer: A pointer to the current player (type *player.Player).
 //   - nameA: The name of Player A.
 //   - healthA: The current health of Player A.
@@ -184,11 +456,17 @@ func conductRound(currentPlayer *player.Player, nameA string, healthA int, stren
 //   - attackB: The attack attribute of Player B.
 //
 // Returns:
-//   - string: A description of the round result.
+//   - RoundEvent: The event describing the round.
 //   - int: The updated health of Player A.
 //   - int: The updated health of Player B.
-func GetConductRound(currentPlayer *player.Player, nameA string, healthA int, strengthA int, attackA int, nameB string, healthB int, strengthB int, attackB int) (string, int, int) {
-	return conductRound(currentPlayer, nameA, healthA, strengthA, attackA, nameB, healthB, strengthB, attackB)
+func GetConductRound(dice Dice, rules Rules, currentPlayer *player.Player, nameA string, healthA int, strengthA int, attackA int, nameB string, healthB int, strengthB int, attackB int) (RoundEvent, int, int) {
+	playerName, _, _, _ := player.GetPlayerBaseAttributes(currentPlayer)
+	a := &fighter{name: nameA, health: healthA, startHealth: healthA, strength: strengthA, attack: attackA}
+	b := &fighter{name: nameB, health: healthB, startHealth: healthB, strength: strengthB, attack: attackB}
+	if playerName == nameB {
+		return conductRound(dice, rules, b, a, Action{Kind: ActionAttack}), a.health, b.health
+	}
+	return conductRound(dice, rules, a, b, Action{Kind: ActionAttack}), a.health, b.health
 }
 
 // max returns the maximum of two integers.
@@ -237,6 +515,8 @@ func GetSwitchCurrentPlayer(currentPlayer **player.Player, playerA *player.Playe
 }
 
 // MatchResult determines the result of a match based on the health attributes of two players.
+// Both players fall in the same turn when a poison or burn tick finishes off the attacker who just
+// knocked out the defender; such a double knockout is a draw.
 //
 // Parameters:
 //   - nameA: The name of Player A.
@@ -245,12 +525,20 @@ func GetSwitchCurrentPlayer(currentPlayer **player.Player, playerA *player.Playe
 //   - healthB: The current health of Player B.
 //
 // Returns:
-//   - string: A message indicating the winner of the match. The message is formatted as "{winner} wins".
-func MatchResult(nameA string, healthA int, nameB string, healthB int) string {
+//   - OutcomeKind: Win if one player's health reached zero, Draw if both players are still standing
+//     or both fell.
+//   - string: The name of the winner, or an empty string for a draw.
+func MatchResult(nameA string, healthA int, nameB string, healthB int) (OutcomeKind, string) {
+	if healthA <= 0 && healthB <= 0 {
+		return Draw, ""
+	}
 	if healthA <= 0 {
-		return fmt.Sprintf("%s wins", nameB)
+		return Win, nameB
+	}
+	if healthB <= 0 {
+		return Win, nameA
 	}
-	return fmt.Sprintf("%s wins", nameA)
+	return Draw, ""
 }
 
 // GetMatchResult is a testing wrapper for the MatchResult function.
@@ -262,7 +550,9 @@ func MatchResult(nameA string, healthA int, nameB string, healthB int) string {
 //   - healthB: The current health of Player B.
 //
 // Returns:
-//   - string: A message indicating the winner of the match. The message is formatted as "{winner} wins".
-func GetMatchResult(nameA string, healthA int, nameB string, healthB int) string {
+//   - OutcomeKind: Win if one player's health reached zero, Draw if both players are still standing
+//     or both fell.
+//   - string: The name of the winner, or an empty string for a draw.
+func GetMatchResult(nameA string, healthA int, nameB string, healthB int) (OutcomeKind, string) {
 	return MatchResult(nameA, healthA, nameB, healthB)
 }
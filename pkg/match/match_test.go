@@ -1,9 +1,11 @@
 package match
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"proj/pkg/player"
+	"reflect"
 	"testing"
 )
 
@@ -14,6 +16,16 @@ const (
 	resetColor = "\033[0m"
 )
 
+// newTestMatch creates a match for a test, failing the test if the match is invalid.
+func newTestMatch(t *testing.T, playerA, playerB *player.Player, opts ...Option) *Match {
+	t.Helper()
+	m, err := NewMatch(playerA, playerB, opts...)
+	if err != nil {
+		t.Fatalf(redColor+"Expected NewMatch to succeed, got %v"+resetColor, err)
+	}
+	return m
+}
+
 // TestGetDeterminStartingPlayer tests the GetDeterminStartingPlayer function,
 // which determines the starting player for a match based on the health attributes
 // of the players.
@@ -33,7 +45,7 @@ func TestGetDeterminStartingPlayer(t *testing.T) {
 	//		check that playerB is the starting player
 	playerA := player.NewPlayer("PlayerA", 100, 10, 5)
 	playerB := player.NewPlayer("PlayerB", 50, 5, 2)
-	match := NewMatch(playerA, playerB)
+	match := newTestMatch(t, playerA, playerB)
 	startingPlayer := GetDeterminStartingPlayer(match)
 	startingPlayerName, _, _, _ := player.GetPlayerBaseAttributes(startingPlayer)
 	if startingPlayerName != "PlayerB" {
@@ -46,7 +58,7 @@ func TestGetDeterminStartingPlayer(t *testing.T) {
 	//		check that playerB is the starting player
 	playerA = player.NewPlayer("PlayerA", 100, 5, 2)
 	playerB = player.NewPlayer("PlayerB", 100, 10, 5)
-	match = NewMatch(playerA, playerB)
+	match = newTestMatch(t, playerA, playerB)
 	startingPlayer = GetDeterminStartingPlayer(match)
 	startingPlayerName, _, _, _ = player.GetPlayerBaseAttributes(startingPlayer)
 	if startingPlayerName != "PlayerA" {
@@ -59,7 +71,7 @@ func TestGetDeterminStartingPlayer(t *testing.T) {
 	// 		check that playerA is the starting player
 	playerA = player.NewPlayer("PlayerA", 50, 5, 2)
 	playerB = player.NewPlayer("PlayerB", 100, 10, 5)
-	match = NewMatch(playerA, playerB)
+	match = newTestMatch(t, playerA, playerB)
 	startingPlayer = GetDeterminStartingPlayer(match)
 	startingPlayerName, _, _, _ = player.GetPlayerBaseAttributes(startingPlayer)
 	if startingPlayerName != "PlayerA" {
@@ -128,20 +140,20 @@ func TestGetIsMatchOver(t *testing.T) {
 }
 
 func TestGetConductRound(t *testing.T) {
-	// TEST 1: creating a current player with name "testA".
+	// TEST 1: PlayerA is the current player and both dice are fixed to roll 4.
 	// playerA is the current player, playerB is the opponent
 	// playerA attributes: health 100, strength 10, attack 10
 	// playerB attributes: health 50, strength 5, attack 2
 	//expected health of PlayerB after round = 50 - max(0, 10*4 - 5*4) = 30
-	playerA := player.NewPlayer("testA", 100, 10, 10)
+	playerA := player.NewPlayer("PlayerA", 100, 10, 10)
 	//playerB := player.NewPlayer("PlayerB", 50, 5, 2)
 	currentPlayer := playerA
-	roundResult, healthA, healthB := conductRound(currentPlayer, "testA", 100, 10, 10, "PlayerB", 50, 5, 2)
+	roundResult, healthA, healthB := GetConductRound(NewFixedDice(4), DefaultRules(), currentPlayer, "PlayerA", 100, 10, 10, "PlayerB", 50, 5, 2)
 	if healthB != 30 {
 		t.Errorf(redColor+"Expected healthB to be 30, got %d"+resetColor, healthB)
 	}
-	if roundResult != "testA attacked PlayerB for 20 damage" {
-		t.Errorf(redColor+"Expected roundResult to be 'testA attacked PlayerB for 20 damage', got %s"+resetColor, roundResult)
+	if roundResult.String() != "PlayerA attacked PlayerB for 20 damage" {
+		t.Errorf(redColor+"Expected roundResult to be 'PlayerA attacked PlayerB for 20 damage', got %s"+resetColor, roundResult)
 	}
 	if healthA != 100 {
 		t.Errorf(redColor+"Expected healthA to be 100, got %d"+resetColor, healthA)
@@ -149,20 +161,20 @@ func TestGetConductRound(t *testing.T) {
 		fmt.Println(greenColor + "TestGetConductRound : Test1 : Passed" + resetColor)
 	}
 
-	// TEST 2: creating a current player with name "testA".
+	// TEST 2: PlayerA is the current player and both dice are fixed to roll 4.
 	// playerA is the current player, playerB is the opponent
 	// playerA attributes: health 100, strength 10, attack 4
 	// playerB attributes: health 50, strength 5, attack 2
 	//expected health of PlayerB after round = 50 - max(0, 4*4 - 5*4) = 50
-	playerA = player.NewPlayer("testA", 100, 10, 4)
+	playerA = player.NewPlayer("PlayerA", 100, 10, 4)
 	//playerB := player.NewPlayer("PlayerB", 50, 5, 2)
 	currentPlayer = playerA
-	roundResult, healthA, healthB = conductRound(currentPlayer, "testA", 100, 10, 4, "PlayerB", 50, 5, 2)
+	roundResult, healthA, healthB = GetConductRound(NewFixedDice(4), DefaultRules(), currentPlayer, "PlayerA", 100, 10, 4, "PlayerB", 50, 5, 2)
 	if healthB != 50 {
 		t.Errorf(redColor+"Expected healthB to be 50, got %d"+resetColor, healthB)
 	}
-	if roundResult != "testA attacked PlayerB for 0 damage" {
-		t.Errorf(redColor+"Expected roundResult to be 'testA attacked PlayerB for 0 damage', got %s"+resetColor, roundResult)
+	if roundResult.String() != "PlayerA attacked PlayerB for 0 damage" {
+		t.Errorf(redColor+"Expected roundResult to be 'PlayerA attacked PlayerB for 0 damage', got %s"+resetColor, roundResult)
 	}
 	if healthA != 100 {
 		t.Errorf(redColor+"Expected healthA to be 100, got %d"+resetColor, healthA)
@@ -170,19 +182,19 @@ func TestGetConductRound(t *testing.T) {
 		fmt.Println(greenColor + "TestGetConductRound : Test2 : Passed" + resetColor)
 	}
 
-	// TEST 3: creating a current player with name "testB".
+	// TEST 3: PlayerB is the current player and both dice are fixed to roll 4.
 	// playerB is the current player, playerA is the opponent
 	// playerA attributes:  health 50, strength 5, attack 2
 	// playerB attributes: health 100, strength 10, attack 10
 	// expected health of PlayerA after round = 50 - max(0, 10*4 - 5*4) = 30
-	playerB := player.NewPlayer("testB", 100, 10, 10)
+	playerB := player.NewPlayer("PlayerB", 100, 10, 10)
 	currentPlayer = playerB
-	roundResult, healthA, healthB = conductRound(currentPlayer, "PlayerA", 50, 5, 2, "testB", 100, 10, 10)
+	roundResult, healthA, healthB = GetConductRound(NewFixedDice(4), DefaultRules(), currentPlayer, "PlayerA", 50, 5, 2, "PlayerB", 100, 10, 10)
 	if healthA != 30 {
 		t.Errorf(redColor+"Expected healthA to be 30, got %d"+resetColor, healthA)
 	}
-	if roundResult != "testB attacked PlayerA for 20 damage" {
-		t.Errorf(redColor+"Expected roundResult to be 'testB attacked PlayerA for 20 damage', got %s"+resetColor, roundResult)
+	if roundResult.String() != "PlayerB attacked PlayerA for 20 damage" {
+		t.Errorf(redColor+"Expected roundResult to be 'PlayerB attacked PlayerA for 20 damage', got %s"+resetColor, roundResult)
 	}
 	if healthB != 100 {
 		t.Errorf(redColor+"Expected healthB to be 100, got %d"+resetColor, healthB)
@@ -190,19 +202,19 @@ func TestGetConductRound(t *testing.T) {
 		fmt.Println(greenColor + "TestGetConductRound : Test3 : Passed" + resetColor)
 	}
 
-	// TEST 4: creating a current player with name "testB".
+	// TEST 4: PlayerB is the current player and both dice are fixed to roll 4.
 	// playerB is the current player, playerA is the opponent
 	// playerA attributes:  health 50, strength 5, attack 2
 	// playerB attributes: health 100, strength 10, attack 4
 	// expected health of PlayerA after round = 50 - max(0, 4*4 - 5*4) = 50
-	playerB = player.NewPlayer("testB", 100, 10, 4)
+	playerB = player.NewPlayer("PlayerB", 100, 10, 4)
 	currentPlayer = playerB
-	roundResult, healthA, healthB = conductRound(currentPlayer, "PlayerA", 50, 5, 2, "testB", 100, 10, 4)
+	roundResult, healthA, healthB = GetConductRound(NewFixedDice(4), DefaultRules(), currentPlayer, "PlayerA", 50, 5, 2, "PlayerB", 100, 10, 4)
 	if healthA != 50 {
 		t.Errorf(redColor+"Expected healthA to be 50, got %d"+resetColor, healthA)
 	}
-	if roundResult != "testB attacked PlayerA for 0 damage" {
-		t.Errorf(redColor+"Expected roundResult to be 'testB attacked PlayerA for 0 damage', got %s"+resetColor, roundResult)
+	if roundResult.String() != "PlayerB attacked PlayerA for 0 damage" {
+		t.Errorf(redColor+"Expected roundResult to be 'PlayerB attacked PlayerA for 0 damage', got %s"+resetColor, roundResult)
 	}
 	if healthB != 100 {
 		t.Errorf(redColor+"Expected healthB to be 100, got %d"+resetColor, healthB)
@@ -256,54 +268,333 @@ func TestGetSwitchCurrentPlayer(t *testing.T) {
 //     PlayerA wins.
 //  2. Create a match with PlayerA's health 0 and PlayerB's health 100. Check that
 //     PlayerB wins.
+//  3. Create a match with both players still standing. Check that it is not a win.
+//  4. Create a match where both players fell. Check that it is a draw.
 func TestGetMatchResult(t *testing.T) {
 	// TEST 1: playerA wins
-	matchResult := GetMatchResult("PlayerA", 100, "PlayerB", 0)
-	if matchResult != "PlayerA wins" {
-		t.Errorf(redColor+"Expected matchResult to be 'PlayerA wins', got %s"+resetColor, matchResult)
+	kind, winner := GetMatchResult("PlayerA", 100, "PlayerB", 0)
+	if kind != Win || winner != "PlayerA" {
+		t.Errorf(redColor+"Expected a win for PlayerA, got %s for '%s'"+resetColor, kind, winner)
 	} else {
 		fmt.Println(greenColor + "TestGetMatchResult : Test1 : Passed" + resetColor)
 	}
 
 	// TEST 2: playerB wins
-	matchResult = GetMatchResult("PlayerA", 0, "PlayerB", 100)
-	if matchResult != "PlayerB wins" {
-		t.Errorf(redColor+"Expected matchResult to be 'PlayerB wins', got %s"+resetColor, matchResult)
+	kind, winner = GetMatchResult("PlayerA", 0, "PlayerB", 100)
+	if kind != Win || winner != "PlayerB" {
+		t.Errorf(redColor+"Expected a win for PlayerB, got %s for '%s'"+resetColor, kind, winner)
 	} else {
 		fmt.Println(greenColor + "TestGetMatchResult : Test2 : Passed" + resetColor)
 	}
+
+	// TEST 3: nobody has won yet
+	kind, winner = GetMatchResult("PlayerA", 10, "PlayerB", 100)
+	if kind != Draw || winner != "" {
+		t.Errorf(redColor+"Expected a draw, got %s for '%s'"+resetColor, kind, winner)
+	} else {
+		fmt.Println(greenColor + "TestGetMatchResult : Test3 : Passed" + resetColor)
+	}
+
+	// TEST 4: both players fell
+	kind, winner = GetMatchResult("PlayerA", 0, "PlayerB", 0)
+	if kind != Draw || winner != "" {
+		t.Errorf(redColor+"Expected a draw, got %s for '%s'"+resetColor, kind, winner)
+	} else {
+		fmt.Println(greenColor + "TestGetMatchResult : Test4 : Passed" + resetColor)
+	}
 }
 
 func TestConductMatch(t *testing.T) {
-	//TEST 1: create a match with playerA health 100, playerB health 60
-	// attribute of playerA: name=testA, health=100, strength=20, attack=20
-	// attribute of playerB: name=testB, health=60, strength=10, attack=20
-	// expected match result: testA wins
-	playerA := player.NewPlayer("testA", 100, 20, 20)
-	playerB := player.NewPlayer("testB", 60, 10, 20)
-	match := NewMatch(playerA, playerB)
+	//TEST 1: create a match with playerA health 100, playerB health 60, every die fixed to roll 4
+	// attribute of playerA: name=PlayerA, health=100, strength=20, attack=20
+	// attribute of playerB: name=PlayerB, health=60, strength=10, attack=20
+	// expected match result: PlayerA wins
+	playerA := player.NewPlayer("PlayerA", 100, 20, 20)
+	playerB := player.NewPlayer("PlayerB", 60, 10, 20)
+	match := newTestMatch(t, playerA, playerB, WithDice(NewFixedDice(4)))
 	_, matchResult := ConductMatch(match)
-	if matchResult != "testA wins" {
-		t.Errorf(redColor+"Expected matchResult to be 'testA wins', got %s"+resetColor, matchResult)
+	if matchResult.String() != "PlayerA wins" {
+		t.Errorf(redColor+"Expected matchResult to be 'PlayerA wins', got %s"+resetColor, matchResult)
 	} else {
 		fmt.Println(greenColor + "TestConductMatch : Test1 : Passed" + resetColor)
 	}
 
-	//TEST 2: create a match with playerA health 60, playerB health 100
-	// attribute of playerA: name=testA, health=60, strength=10, attack=20
-	// attribute of playerB: name=testB, health=100, strength=20, attack=20
-	// expected match result: testB wins
-	playerA = player.NewPlayer("testA", 60, 10, 20)
-	playerB = player.NewPlayer("testB", 100, 20, 20)
-	match = NewMatch(playerA, playerB)
+	//TEST 2: create a match with playerA health 60, playerB health 100, every die fixed to roll 4
+	// attribute of playerA: name=PlayerA, health=60, strength=10, attack=20
+	// attribute of playerB: name=PlayerB, health=100, strength=20, attack=20
+	// expected match result: PlayerB wins
+	playerA = player.NewPlayer("PlayerA", 60, 10, 20)
+	playerB = player.NewPlayer("PlayerB", 100, 20, 20)
+	match = newTestMatch(t, playerA, playerB, WithDice(NewFixedDice(4)))
 	_, matchResult = ConductMatch(match)
-	if matchResult != "testB wins" {
-		t.Errorf(redColor+"Expected matchResult to be 'testB wins', got %s"+resetColor, matchResult)
+	if matchResult.String() != "PlayerB wins" {
+		t.Errorf(redColor+"Expected matchResult to be 'PlayerB wins', got %s"+resetColor, matchResult)
 	} else {
 		fmt.Println(greenColor + "TestConductMatch : Test2 : Passed" + resetColor)
 	}
 }
 
+// TestMatchOutcome tests that ConductMatch reports a complete MatchOutcome.
+//
+// Test scenarios:
+//  1. Script a match PlayerA wins in 5 rounds. Check the winner, loser, final health,
+//     round count, starting player and reason.
+//  2. PlayerA poisons PlayerB, who knocks PlayerA out 2 turns later and falls to the poison at the
+//     end of the same turn. Check that the match is a draw, even with a tie-break that would
+//     award it.
+func TestMatchOutcome(t *testing.T) {
+	//TEST 1: PlayerA deals 50 damage per attack, PlayerB deals none (see TestScriptedDice)
+	playerA := player.NewPlayer("PlayerA", 100, 5, 10)
+	playerB := player.NewPlayer("PlayerB", 120, 10, 5)
+	match := newTestMatch(t, playerA, playerB, WithDice(NewScriptedDice(6, 1, 1, 6)))
+	_, outcome := ConductMatch(match)
+	expected := MatchOutcome{Winner: playerA, Loser: playerB, FinalHealthA: 100, FinalHealthB: 0, Rounds: 5, StartingPlayer: playerA, Reason: Win}
+	if outcome != expected || match.Outcome() != expected || outcome.String() != "PlayerA wins" {
+		t.Errorf(redColor+"Expected outcome %+v, got %+v"+resetColor, expected, outcome)
+	} else {
+		fmt.Println(greenColor + "TestMatchOutcome : Test1 : Passed" + resetColor)
+	}
+
+	//TEST 2: both players fall in one turn
+	playerA = player.NewPlayer("PlayerA", 3, 1, 1, player.WithMana(10))
+	playerB = player.NewPlayer("PlayerB", 4, 1, 20)
+	rules := DefaultRules()
+	rules.TieBreak = TieBreakHealthPercent
+	match = newTestMatch(t, playerA, playerB, WithDice(NewScriptedDice(6, 1)), WithRules(rules))
+	for _, action := range []Action{{Kind: ActionCast, Spell: SpellVenom}, {Kind: ActionDefend}, {Kind: ActionDefend}, {Kind: ActionAttack}} {
+		if _, err := match.PlayTurn(action); err != nil {
+			t.Fatalf(redColor+"Expected %v to be played, got %v"+resetColor, action, err)
+		}
+	}
+	_, outcome = ConductMatch(match)
+	expected = MatchOutcome{FinalHealthA: 0, FinalHealthB: 0, Rounds: 4, StartingPlayer: playerA, Reason: Draw}
+	if outcome != expected || outcome.String() != "Draw" {
+		t.Errorf(redColor+"Expected outcome %+v, got %+v"+resetColor, expected, outcome)
+	} else {
+		fmt.Println(greenColor + "TestMatchOutcome : Test2 : Passed" + resetColor)
+	}
+}
+
+// TestRoundLimit tests that a match reaching the round limit is decided by the tie-break rule.
+//
+// Test scenarios:
+//  1. PlayerA (attack 4 vs strength 2, every die rolls 1) deals 2 damage per attack while PlayerB
+//     deals none. After 4 rounds the health tie-break awards PlayerB, who has more health left, a timeout win.
+//  2. The same match with the draw tie-break is declared a draw.
+//  3. The health-percent tie-break favours PlayerA, who has lost none of their health.
+//  4. The default rules set no round limit, so the same match goes on until PlayerA wins after 99 rounds.
+//  5. WithSafetyLimit limits rules without a round limit to SafetyRounds and keeps any other limit.
+func TestRoundLimit(t *testing.T) {
+	//TEST 1: health tie-break
+	playerA := player.NewPlayer("PlayerA", 10, 5, 4)
+	playerB := player.NewPlayer("PlayerB", 100, 2, 1)
+	rules := DefaultRules()
+	rules.MaxRounds = 4
+	match := newTestMatch(t, playerA, playerB, WithDice(NewFixedDice(1)), WithRules(rules))
+	roundResults, matchResult := ConductMatch(match)
+	if len(roundResults) != 4 || matchResult.Reason != Timeout || matchResult.Winner != playerB || matchResult.String() != "PlayerB wins on timeout" {
+		t.Errorf(redColor+"Expected PlayerB to win on timeout after 4 rounds, got %s after %d rounds"+resetColor, matchResult, len(roundResults))
+	} else {
+		fmt.Println(greenColor + "TestRoundLimit : Test1 : Passed" + resetColor)
+	}
+
+	//TEST 2: draw tie-break
+	rules.TieBreak = TieBreakDraw
+	match = newTestMatch(t, playerA, playerB, WithDice(NewFixedDice(1)), WithRules(rules))
+	_, matchResult = ConductMatch(match)
+	if !matchResult.IsDraw() || matchResult.Winner != nil || matchResult.String() != "Draw" {
+		t.Errorf(redColor+"Expected a draw, got %s"+resetColor, matchResult)
+	} else {
+		fmt.Println(greenColor + "TestRoundLimit : Test2 : Passed" + resetColor)
+	}
+
+	//TEST 3: health-percent tie-break, PlayerA keeps 10/10 and PlayerB keeps 96/100
+	rules.TieBreak = TieBreakHealthPercent
+	match = newTestMatch(t, playerA, playerB, WithDice(NewFixedDice(1)), WithRules(rules))
+	_, matchResult = ConductMatch(match)
+	if matchResult.String() != "PlayerA wins on timeout" {
+		t.Errorf(redColor+"Expected PlayerA to win on timeout, got %s"+resetColor, matchResult)
+	} else {
+		fmt.Println(greenColor + "TestRoundLimit : Test3 : Passed" + resetColor)
+	}
+
+	//TEST 4: no round limit
+	match = newTestMatch(t, playerA, playerB, WithDice(NewFixedDice(1)))
+	roundResults, matchResult = ConductMatch(match)
+	if DefaultRules().MaxRounds != 0 || len(roundResults) != 99 || matchResult.String() != "PlayerA wins" {
+		t.Errorf(redColor+"Expected PlayerA to win after 99 rounds, got %s after %d rounds"+resetColor, matchResult, len(roundResults))
+	} else {
+		fmt.Println(greenColor + "TestRoundLimit : Test4 : Passed" + resetColor)
+	}
+
+	//TEST 5: safety limit
+	if limited, kept := DefaultRules().WithSafetyLimit(), rules.WithSafetyLimit(); limited.MaxRounds != SafetyRounds || kept.MaxRounds != 4 {
+		t.Errorf(redColor+"Expected round limits of %d and 4, got %d and %d"+resetColor, SafetyRounds, limited.MaxRounds, kept.MaxRounds)
+	} else {
+		fmt.Println(greenColor + "TestRoundLimit : Test5 : Passed" + resetColor)
+	}
+}
+
+// TestSeededMatchIsReproducible tests that two matches created with the same seed
+// conduct exactly the same sequence of rounds and record that seed.
+//
+// Test scenarios:
+//  1. Conduct two matches between identical players with seed 42. Check that both
+//     matches produce identical round results and the same overall result.
+//  2. Check that the seed is recorded on the match.
+func TestSeededMatchIsReproducible(t *testing.T) {
+	//TEST 1: same seed, same players => same rounds and same result
+	first := newTestMatch(t, player.NewPlayer("PlayerA", 50, 5, 10), player.NewPlayer("PlayerB", 100, 10, 5), WithSeed(42))
+	second := newTestMatch(t, player.NewPlayer("PlayerA", 50, 5, 10), player.NewPlayer("PlayerB", 100, 10, 5), WithSeed(42))
+	firstRounds, firstResult := ConductMatch(first)
+	secondRounds, secondResult := ConductMatch(second)
+	if len(firstRounds) != len(secondRounds) {
+		t.Fatalf(redColor+"Expected %d rounds, got %d"+resetColor, len(firstRounds), len(secondRounds))
+	}
+	for i := range firstRounds {
+		if firstRounds[i] != secondRounds[i] {
+			t.Errorf(redColor+"Round %d differs: '%s' vs '%s'"+resetColor, i+1, firstRounds[i], secondRounds[i])
+		}
+	}
+	if firstResult.String() != secondResult.String() || firstResult.FinalHealthA != secondResult.FinalHealthA || firstResult.FinalHealthB != secondResult.FinalHealthB {
+		t.Errorf(redColor+"Expected matchResult to be '%s', got %s"+resetColor, firstResult, secondResult)
+	} else {
+		fmt.Println(greenColor + "TestSeededMatchIsReproducible : Test1 : Passed" + resetColor)
+	}
+
+	//TEST 2: the seed is recorded on the match
+	if first.Seed() != 42 {
+		t.Errorf(redColor+"Expected seed to be 42, got %d"+resetColor, first.Seed())
+	} else {
+		fmt.Println(greenColor + "TestSeededMatchIsReproducible : Test2 : Passed" + resetColor)
+	}
+}
+
+// TestRandomDiceRoll tests that RandomDice always rolls within [1, sides].
+func TestRandomDiceRoll(t *testing.T) {
+	dice := NewRandomDice(7)
+	for i := 0; i < 1000; i++ {
+		if roll := dice.Roll(6); roll < 1 || roll > 6 {
+			t.Fatalf(redColor+"Expected roll in [1, 6], got %d"+resetColor, roll)
+		}
+	}
+	fmt.Println(greenColor + "TestRandomDiceRoll : Test1 : Passed" + resetColor)
+}
+
+// TestFixedDiceRoll tests that FixedDice rolls its value, clamped to the faces of the die.
+//
+// Test scenarios:
+//  1. A value of 8 rolls 6 on a six-sided die and 8 on a 100-sided die, and a value of 0 rolls 1.
+func TestFixedDiceRoll(t *testing.T) {
+	//TEST 1: clamped to [1, sides]
+	eight, zero := NewFixedDice(8), NewFixedDice(0)
+	if six, hundred, one := eight.Roll(6), eight.Roll(100), zero.Roll(6); six != 6 || hundred != 8 || one != 1 {
+		t.Errorf(redColor+"Expected rolls of 6, 8 and 1, got %d, %d and %d"+resetColor, six, hundred, one)
+	} else {
+		fmt.Println(greenColor + "TestFixedDiceRoll : Test1 : Passed" + resetColor)
+	}
+}
+
+// TestScriptedDice tests that a match rolling ScriptedDice follows the script exactly.
+//
+// Test scenarios:
+//  1. Script the rolls 6,1 then 1,6 for a match where PlayerA moves first. Check that
+//     PlayerA deals 60-10=50 damage and PlayerB deals 0 damage in the first two rounds.
+//  2. Check that the script starts over once it is exhausted.
+//  3. Check that creating ScriptedDice without rolls panics at once, rather than on the first roll.
+func TestScriptedDice(t *testing.T) {
+	//TEST 1: PlayerA (attack 10) rolls 6 against PlayerB's defence (strength 10) of 1,
+	// then PlayerB (attack 5) rolls 1 against PlayerA's defence (strength 5) of 6
+	playerA := player.NewPlayer("PlayerA", 100, 5, 10)
+	playerB := player.NewPlayer("PlayerB", 120, 10, 5)
+	match := newTestMatch(t, playerA, playerB, WithDice(NewScriptedDice(6, 1, 1, 6)))
+	roundResults, _ := ConductMatch(match)
+	if roundResults[0] != "PlayerA attacked PlayerB for 50 damage" {
+		t.Errorf(redColor+"Expected round 1 to be 'PlayerA attacked PlayerB for 50 damage', got %s"+resetColor, roundResults[0])
+	}
+	if roundResults[1] != "PlayerB attacked PlayerA for 0 damage" {
+		t.Errorf(redColor+"Expected round 2 to be 'PlayerB attacked PlayerA for 0 damage', got %s"+resetColor, roundResults[1])
+	} else {
+		fmt.Println(greenColor + "TestScriptedDice : Test1 : Passed" + resetColor)
+	}
+
+	//TEST 2: the script repeats, so PlayerB falls after three attacks by PlayerA
+	if len(roundResults) != 5 || roundResults[4] != "PlayerA attacked PlayerB for 50 damage" {
+		t.Errorf(redColor+"Expected 5 rounds ending with PlayerA's third attack, got %v"+resetColor, roundResults)
+	} else {
+		fmt.Println(greenColor + "TestScriptedDice : Test2 : Passed" + resetColor)
+	}
+
+	//TEST 3: no rolls
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Errorf(redColor + "Expected NewScriptedDice without rolls to panic" + resetColor)
+			} else {
+				fmt.Println(greenColor + "TestScriptedDice : Test3 : Passed" + resetColor)
+			}
+		}()
+		NewScriptedDice()
+	}()
+}
+
+// TestEvents tests that ConductMatch records a structured RoundEvent for every round.
+//
+// Test scenarios:
+//  1. Script the rolls 6,1 then 1,6. Check every field of the first recorded event.
+//  2. Check that the string round results are derived from the events.
+func TestEvents(t *testing.T) {
+	//TEST 1: PlayerA (attack 10) rolls 6 against PlayerB's defence (strength 10) of 1
+	playerA := player.NewPlayer("PlayerA", 100, 5, 10)
+	playerB := player.NewPlayer("PlayerB", 120, 10, 5)
+	match := newTestMatch(t, playerA, playerB, WithDice(NewScriptedDice(6, 1, 1, 6)))
+	roundResults, _ := ConductMatch(match)
+	events := match.Events()
+	expected := RoundEvent{Round: 1, Attacker: "PlayerA", Defender: "PlayerB", AttackRoll: 6, DefenceRoll: 1, Damage: 50, DefenderHealthBefore: 120, DefenderHealthAfter: 70}
+	if !reflect.DeepEqual(events[0], expected) {
+		t.Errorf(redColor+"Expected first event to be %+v, got %+v"+resetColor, expected, events[0])
+	} else {
+		fmt.Println(greenColor + "TestEvents : Test1 : Passed" + resetColor)
+	}
+
+	//TEST 2: one string result per event, rendered from the event
+	if len(events) != len(roundResults) || events[1].Round != 2 || roundResults[1] != events[1].String() {
+		t.Errorf(redColor+"Expected round results to match events, got %v and %+v"+resetColor, roundResults, events)
+	} else {
+		fmt.Println(greenColor + "TestEvents : Test2 : Passed" + resetColor)
+	}
+}
+
+// TestNewMatchValidation tests that NewMatch rejects matches that cannot be played.
+//
+// Test scenarios:
+//  1. Players with the same name are rejected with ErrDuplicateName.
+//  2. A player with no health is rejected with player.ErrNonPositiveHealth.
+//  3. A player whose best attack cannot beat the opponent's worst defence is rejected with ErrAttackCannotPenetrate.
+//  4. Invalid rules are rejected with ErrInvalidRules.
+func TestNewMatchValidation(t *testing.T) {
+	rules := DefaultRules()
+	rules.DiceSides = 0
+	cases := []struct {
+		playerA, playerB *player.Player
+		opts             []Option
+		expected         error
+	}{
+		{player.NewPlayer("PlayerA", 100, 10, 5), player.NewPlayer("PlayerA", 50, 5, 2), nil, ErrDuplicateName},
+		{player.NewPlayer("PlayerA", 0, 10, 5), player.NewPlayer("PlayerB", 50, 5, 2), nil, player.ErrNonPositiveHealth},
+		{player.NewPlayer("PlayerA", 100, 10, 5), player.NewPlayer("PlayerB", 50, 5, 1), nil, ErrAttackCannotPenetrate},
+		{player.NewPlayer("PlayerA", 100, 10, 5), player.NewPlayer("PlayerB", 50, 5, 2), []Option{WithRules(rules)}, ErrInvalidRules},
+	}
+	for i, tc := range cases {
+		m, err := NewMatch(tc.playerA, tc.playerB, tc.opts...)
+		if m != nil || !errors.Is(err, tc.expected) {
+			t.Errorf(redColor+"Expected %v, got %v"+resetColor, tc.expected, err)
+		} else {
+			fmt.Printf(greenColor+"TestNewMatchValidation : Test%d : Passed\n"+resetColor, i+1)
+		}
+	}
+}
+
 // TestMain runs the main testing suite.
 func TestMain(m *testing.M) {
 	fmt.Println("Testing Match package...")
@@ -0,0 +1,129 @@
+package match
+
+import (
+	"fmt"
+	"proj/pkg/player"
+)
+
+// OutcomeKind describes how a match ended.
+type OutcomeKind int
+
+const (
+	// Win means one player's health reached zero.
+	Win OutcomeKind = iota
+	// Timeout means the round limit was reached and the tie-break rule picked a winner.
+	Timeout
+	// Draw means the round limit was reached and the tie-break rule declared a draw, or both players
+	// fell in the same turn.
+	Draw
+)
+
+// String returns the name of the outcome kind.
+//
+// Returns:
+//   - string: "win", "timeout" or "draw".
+func (k OutcomeKind) String() string {
+	switch k {
+	case Win:
+		return "win"
+	case Timeout:
+		return "timeout"
+	case Draw:
+		return "draw"
+	}
+	return fmt.Sprintf("OutcomeKind(%d)", int(k))
+}
+
+// TieBreak selects how a match that reaches the round limit is decided.
+type TieBreak string
+
+const (
+	// TieBreakHealth awards the match to the player with the highest remaining health.
+	TieBreakHealth TieBreak = "health"
+	// TieBreakHealthPercent awards the match to the player with the highest percentage of their starting health left.
+	TieBreakHealthPercent TieBreak = "health-percent"
+	// TieBreakDraw declares the match a draw.
+	TieBreakDraw TieBreak = "draw"
+)
+
+// breakTie decides a match that reached the round limit with both players still standing.
+// A tie-break that finds both players level declares a draw.
+//
+// Parameters:
+//   - tieBreak: The tie-break rule to apply.
+//   - nameA: The name of Player A.
+//   - healthA: The remaining health of Player A.
+//   - startHealthA: The starting health of Player A.
+//   - nameB: The name of Player B.
+//   - healthB: The remaining health of Player B.
+//   - startHealthB: The starting health of Player B.
+//
+// Returns:
+//   - OutcomeKind: Timeout if a winner was picked, Draw otherwise.
+//   - string: The name of the winner, or an empty string for a draw.
+func breakTie(tieBreak TieBreak, nameA string, healthA, startHealthA int, nameB string, healthB, startHealthB int) (OutcomeKind, string) {
+	var scoreA, scoreB int
+	switch tieBreak {
+	case TieBreakHealth:
+		scoreA, scoreB = healthA, healthB
+	case TieBreakHealthPercent:
+		// Compare healthA/startHealthA with healthB/startHealthB without dividing.
+		scoreA, scoreB = healthA*startHealthB, healthB*startHealthA
+	default:
+		return Draw, ""
+	}
+
+	switch {
+	case scoreA > scoreB:
+		return Timeout, nameA
+	case scoreB > scoreA:
+		return Timeout, nameB
+	}
+	return Draw, ""
+}
+
+// MatchOutcome describes the result of a conducted match.
+type MatchOutcome struct {
+	Winner         *player.Player // Winner is a pointer to the winning player, nil for a draw.
+	Loser          *player.Player // Loser is a pointer to the losing player, nil for a draw.
+	FinalHealthA   int            // FinalHealthA is the health Player A finished the match with.
+	FinalHealthB   int            // FinalHealthB is the health Player B finished the match with.
+	Rounds         int            // Rounds is the number of rounds the match lasted.
+	StartingPlayer *player.Player // StartingPlayer is a pointer to the player who attacked first.
+	Reason         OutcomeKind    // Reason describes how the match ended.
+}
+
+// IsDraw reports whether the match ended without a winner.
+//
+// Returns:
+//   - bool: true for a draw, false otherwise.
+func (o MatchOutcome) IsDraw() bool {
+	return o.Reason == Draw
+}
+
+// String renders the outcome as a message.
+//
+// Returns:
+//   - string: "{winner} wins", "{winner} wins on timeout" or "Draw", and an empty string
+//     for a match that has not been conducted yet.
+func (o MatchOutcome) String() string {
+	if o.Reason == Draw {
+		return "Draw"
+	}
+	if o.Winner == nil {
+		return ""
+	}
+	winner, _, _, _ := player.GetPlayerBaseAttributes(o.Winner)
+	if o.Reason == Timeout {
+		return fmt.Sprintf("%s wins on timeout", winner)
+	}
+	return fmt.Sprintf("%s wins", winner)
+}
+
+// Outcome returns the outcome of the match. It is the zero MatchOutcome until the match has been conducted.
+//
+// Returns:
+//   - MatchOutcome: The outcome of the match.
+func (m *Match) Outcome() MatchOutcome {
+	return m.outcome
+}
@@ -0,0 +1,195 @@
+package match
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// FirstMover selects which player attacks first in a match.
+type FirstMover string
+
+const (
+	// FirstMoverLowerHealth lets the player with lower health attack first, Player A on equal health.
+	FirstMoverLowerHealth FirstMover = "lower-health"
+	// FirstMoverHigherHealth lets the player with higher health attack first, Player A on equal health.
+	FirstMoverHigherHealth FirstMover = "higher-health"
+	// FirstMoverPlayerA always lets Player A attack first.
+	FirstMoverPlayerA FirstMover = "player-a"
+	// FirstMoverPlayerB always lets Player B attack first.
+	FirstMoverPlayerB FirstMover = "player-b"
+)
+
+// Rules describes the mechanics a match is played under.
+//
+// The damage of an attack is (attack*attackRoll*AttackPercent - strength*defenceRoll*DefencePercent) / 100,
+// never below zero, so the default percentages of 100 give attack*attackRoll - strength*defenceRoll.
+//
+// Critical hits, fumbles and dodging are optional and off by default. With Criticals, rolling the
+// highest face of the attack die multiplies the damage by CriticalMultiplier; with Fumbles, rolling
+// a 1 misses; with Dodge, a defender avoids an attack that did not fumble with a chance in percent
+// equal to their agility, checked by rolling a 100-sided die after the attack and defence rolls.
+//
+// Players with mana regenerate ManaRegen mana after each of their turns, up to the size of their
+// mana pool, and spend it casting the spells of the spellbook; see Spells.
+//
+// The heal action costs nothing, so a player who heals cannot heal again for HealCooldown of their
+// following turns; otherwise a player could out-heal their opponent's damage forever.
+type Rules struct {
+	DiceSides      int        `json:"diceSides"`      // DiceSides is the number of sides of every attack and defence die.
+	FirstMover     FirstMover `json:"firstMover"`     // FirstMover selects which player attacks first.
+	AttackPercent  int        `json:"attackPercent"`  // AttackPercent weighs the attacker's attack*roll in the damage formula.
+	DefencePercent int        `json:"defencePercent"` // DefencePercent weighs the defender's strength*roll in the damage formula.
+	MaxRounds      int        `json:"maxRounds"`      // MaxRounds is the number of rounds after which the tie-break decides the match; 0 means no limit.
+	TieBreak       TieBreak   `json:"tieBreak"`       // TieBreak decides a match that reaches MaxRounds.
+
+	Criticals          bool `json:"criticals"`          // Criticals makes an attack roll of the highest face a critical hit, multiplying its damage.
+	CriticalMultiplier int  `json:"criticalMultiplier"` // CriticalMultiplier is the factor a critical hit multiplies the damage by.
+	Fumbles            bool `json:"fumbles"`            // Fumbles makes an attack roll of 1 a fumble, which misses outright.
+	Dodge              bool `json:"dodge"`              // Dodge lets a defender dodge an attack, with their agility as the chance in percent.
+
+	ManaRegen int `json:"manaRegen"` // ManaRegen is the mana a player regenerates after each of their turns.
+
+	HealCooldown int `json:"healCooldown"` // HealCooldown is the number of a player's turns after healing during which they cannot heal again.
+}
+
+// SafetyRounds is the round limit WithSafetyLimit gives rules that set none, for matches that must
+// end even between players who heal or defend faster than they hurt each other.
+const SafetyRounds = 1000
+
+// DefaultRules returns the standard rules of the Magical Arena.
+//
+// Returns:
+//   - Rules: The default rules, rolling six-sided dice, letting the player with lower health
+//     attack first, setting no round limit, regenerating 2 mana a turn and letting a player heal
+//     once every 4 turns. A match that sets a MaxRounds is awarded to the player with the most
+//     health left when it reaches the limit.
+func DefaultRules() Rules {
+	return Rules{
+		DiceSides:      6,
+		FirstMover:     FirstMoverLowerHealth,
+		AttackPercent:  100,
+		DefencePercent: 100,
+		TieBreak:       TieBreakHealth,
+
+		CriticalMultiplier: 2,
+
+		ManaRegen: 2,
+
+		HealCooldown: 3,
+	}
+}
+
+// LoadRules reads rules from a JSON file, such as:
+//
+//	{"diceSides": 8, "firstMover": "higher-health", "maxRounds": 200, "tieBreak": "draw", "criticals": true}
+//
+// Settings missing from the file keep their DefaultRules values, and the result is validated.
+//
+// Parameters:
+//   - path: The path of the rules file.
+//
+// Returns:
+//   - Rules: The loaded rules.
+//   - error: An error, if the file cannot be read or describes invalid rules.
+func LoadRules(path string) (Rules, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Rules{}, err
+	}
+	rules := DefaultRules()
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return Rules{}, fmt.Errorf("invalid rules file: %w", err)
+	}
+	if err := rules.Validate(); err != nil {
+		return Rules{}, err
+	}
+	return rules, nil
+}
+
+// Validate checks that the rules describe a playable match.
+//
+// Returns:
+//   - error: An error describing the first invalid setting, if any.
+func (r Rules) Validate() error {
+	if r.DiceSides < 2 {
+		return fmt.Errorf("dice must have at least 2 sides, got %d", r.DiceSides)
+	}
+	switch r.FirstMover {
+	case FirstMoverLowerHealth, FirstMoverHigherHealth, FirstMoverPlayerA, FirstMoverPlayerB:
+	default:
+		return fmt.Errorf("unknown first mover %q", r.FirstMover)
+	}
+	if r.AttackPercent <= 0 {
+		return fmt.Errorf("attack percent must be greater than 0, got %d", r.AttackPercent)
+	}
+	if r.DefencePercent < 0 {
+		return fmt.Errorf("defence percent must not be negative, got %d", r.DefencePercent)
+	}
+	if r.MaxRounds < 0 {
+		return fmt.Errorf("max rounds must not be negative, got %d", r.MaxRounds)
+	}
+	switch r.TieBreak {
+	case TieBreakHealth, TieBreakHealthPercent, TieBreakDraw:
+	default:
+		return fmt.Errorf("unknown tie-break %q", r.TieBreak)
+	}
+	if r.Criticals && r.CriticalMultiplier < 1 {
+		return fmt.Errorf("critical multiplier must be at least 1, got %d", r.CriticalMultiplier)
+	}
+	if r.ManaRegen < 0 {
+		return fmt.Errorf("mana regeneration must not be negative, got %d", r.ManaRegen)
+	}
+	if r.HealCooldown < 0 {
+		return fmt.Errorf("heal cooldown must not be negative, got %d", r.HealCooldown)
+	}
+	return nil
+}
+
+// Damage calculates the damage an attack deals given both dice rolls: the attacker's weighted attack
+// multiplied by their roll, minus the defender's weighted strength multiplied by their roll, never below zero.
+//
+// Parameters:
+//   - attack: The attack attribute of the attacker.
+//   - strength: The strength attribute of the defender.
+//   - attackRoll: The value rolled on the attacker's die.
+//   - defenceRoll: The value rolled on the defender's die.
+//
+// Returns:
+//   - int: The damage dealt to the defender.
+func (r Rules) Damage(attack, strength, attackRoll, defenceRoll int) int {
+	return max(0, attack*attackRoll*r.AttackPercent-strength*defenceRoll*r.DefencePercent) / 100
+}
+
+// WithSafetyLimit returns the rules limited to SafetyRounds rounds if they set no round limit,
+// and unchanged otherwise.
+//
+// Returns:
+//   - Rules: The rules with a round limit.
+func (r Rules) WithSafetyLimit() Rules {
+	if r.MaxRounds == 0 {
+		r.MaxRounds = SafetyRounds
+	}
+	return r
+}
+
+// WithRules makes the match play under the provided rules instead of DefaultRules.
+//
+// Parameters:
+//   - rules: The rules of the match.
+//
+// Returns:
+//   - Option: An option to pass to NewMatch.
+func WithRules(rules Rules) Option {
+	return func(m *Match) {
+		m.rules = rules
+	}
+}
+
+// Rules returns the rules the match is played under.
+//
+// Returns:
+//   - Rules: The rules of the match.
+func (m *Match) Rules() Rules {
+	return m.rules
+}
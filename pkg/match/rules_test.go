@@ -0,0 +1,143 @@
+package match
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"proj/pkg/player"
+	"testing"
+)
+
+// TestLoadRules tests loading rules from a JSON file.
+//
+// Test scenarios:
+//  1. Load a file that sets some rules. Check those are applied and the rest keep their defaults.
+//  2. Load a file with an unknown tie-break. Check that it is rejected.
+func TestLoadRules(t *testing.T) {
+	//TEST 1: partial rules file
+	path := filepath.Join(t.TempDir(), "rules.json")
+	if err := os.WriteFile(path, []byte(`{"diceSides": 8, "firstMover": "higher-health", "maxRounds": 200}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	rules, err := LoadRules(path)
+	expected := DefaultRules()
+	expected.DiceSides = 8
+	expected.FirstMover = FirstMoverHigherHealth
+	expected.MaxRounds = 200
+	if err != nil || rules != expected {
+		t.Errorf(redColor+"Expected rules %+v, got %+v, %v"+resetColor, expected, rules, err)
+	} else {
+		fmt.Println(greenColor + "TestLoadRules : Test1 : Passed" + resetColor)
+	}
+
+	//TEST 2: invalid rules file
+	if err := os.WriteFile(path, []byte(`{"tieBreak": "coin-toss"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadRules(path); err == nil {
+		t.Errorf(redColor + "Expected an unknown tie-break to be rejected" + resetColor)
+	} else {
+		fmt.Println(greenColor + "TestLoadRules : Test2 : Passed" + resetColor)
+	}
+}
+
+// TestRulesMechanics tests that rules change the mechanics of a match.
+//
+// Test scenarios:
+//  1. With the higher-health first-mover rule, the player with more health starts.
+//  2. With a defence weight of 50%, 10*4 attack against 10*4 defence deals 20 damage.
+//  3. Whether a strike can penetrate follows the number of dice sides.
+func TestRulesMechanics(t *testing.T) {
+	//TEST 1: higher health attacks first
+	rules := DefaultRules()
+	rules.FirstMover = FirstMoverHigherHealth
+	playerA := player.NewPlayer("PlayerA", 50, 10, 10)
+	playerB := player.NewPlayer("PlayerB", 100, 10, 10)
+	if starting := newTestMatch(t, playerA, playerB, WithRules(rules)).StartingPlayer(); starting != playerB {
+		t.Errorf(redColor + "Expected PlayerB to attack first" + resetColor)
+	} else {
+		fmt.Println(greenColor + "TestRulesMechanics : Test1 : Passed" + resetColor)
+	}
+
+	//TEST 2: weighted damage formula
+	rules = DefaultRules()
+	rules.DefencePercent = 50
+	if damage := rules.Damage(10, 10, 4, 4); damage != 20 {
+		t.Errorf(redColor+"Expected damage to be 20, got %d"+resetColor, damage)
+	} else {
+		fmt.Println(greenColor + "TestRulesMechanics : Test2 : Passed" + resetColor)
+	}
+
+	//TEST 3: attack 2 cannot penetrate strength 12 with six-sided dice, but can with eight-sided dice
+	rules = DefaultRules()
+	weak, tough := player.NewPlayer("Weak", 100, 10, 2), player.NewPlayer("Tough", 100, 12, 10)
+	if NewStrike(rules, weak, tough).CanPenetrate() {
+		t.Errorf(redColor + "Expected attack 2 not to penetrate strength 12 on six-sided dice" + resetColor)
+	}
+	rules.DiceSides = 8
+	if !NewStrike(rules, weak, tough).CanPenetrate() {
+		t.Errorf(redColor + "Expected attack 2 to penetrate strength 12 on eight-sided dice" + resetColor)
+	} else {
+		fmt.Println(greenColor + "TestRulesMechanics : Test3 : Passed" + resetColor)
+	}
+}
+
+// TestOptionalRules tests critical hits, fumbles and dodging.
+//
+// Test scenarios:
+//  1. With criticals, attack 10 rolling 6 against strength 10 rolling 1 deals (60 - 10) * 2 = 100 damage.
+//  2. With fumbles, attack 20 rolling 1 misses strength 5, although it would otherwise deal 15 damage.
+//  3. With dodging, a defender with agility 30 dodges on a d100 roll of 30 but not of 31.
+//  4. Without dodging, no d100 is rolled even against a defender with agility.
+//  5. Criticals with a multiplier below 1 are invalid.
+func TestOptionalRules(t *testing.T) {
+	rules := DefaultRules()
+	rules.Criticals, rules.Fumbles, rules.Dodge = true, true, true
+
+	//TEST 1: critical hit
+	attacker := &fighter{name: "PlayerA", health: 100, strength: 10, attack: 10}
+	defender := &fighter{name: "PlayerB", health: 120, strength: 10, attack: 10}
+	event := conductRound(NewScriptedDice(6, 1), rules, attacker, defender, Action{Kind: ActionAttack})
+	if !event.Critical || event.Damage != 100 || event.String() != "PlayerA attacked PlayerB for 100 damage with a critical hit" {
+		t.Errorf(redColor+"Expected a critical hit for 100 damage, got %+v"+resetColor, event)
+	} else {
+		fmt.Println(greenColor + "TestOptionalRules : Test1 : Passed" + resetColor)
+	}
+
+	//TEST 2: fumble
+	attacker = &fighter{name: "PlayerA", health: 100, strength: 10, attack: 20}
+	defender = &fighter{name: "PlayerB", health: 120, strength: 5, attack: 10}
+	event = conductRound(NewScriptedDice(1, 1), rules, attacker, defender, Action{Kind: ActionAttack})
+	if !event.Fumble || event.Damage != 0 || defender.health != 120 || event.String() != "PlayerA fumbled the attack on PlayerB" {
+		t.Errorf(redColor+"Expected a fumble, got %+v"+resetColor, event)
+	} else {
+		fmt.Println(greenColor + "TestOptionalRules : Test2 : Passed" + resetColor)
+	}
+
+	//TEST 3: dodge
+	defender = &fighter{name: "PlayerB", health: 120, strength: 5, attack: 10, agility: 30}
+	dodged := conductRound(NewScriptedDice(4, 1, 30), rules, attacker, defender, Action{Kind: ActionAttack})
+	hit := conductRound(NewScriptedDice(4, 1, 31), rules, attacker, defender, Action{Kind: ActionAttack})
+	if !dodged.Dodged || dodged.Damage != 0 || dodged.String() != "PlayerB dodged the attack of PlayerA" || hit.Dodged || hit.Damage != 75 {
+		t.Errorf(redColor+"Expected a dodge, then a hit for 75 damage, got %+v, %+v"+resetColor, dodged, hit)
+	} else {
+		fmt.Println(greenColor + "TestOptionalRules : Test3 : Passed" + resetColor)
+	}
+
+	//TEST 4: no dodge roll without the rule
+	dice := &sidesDice{}
+	conductRound(dice, DefaultRules(), attacker, defender, Action{Kind: ActionAttack})
+	if len(dice.sides) != 2 {
+		t.Errorf(redColor+"Expected only the attack and defence rolls, got %v"+resetColor, dice.sides)
+	} else {
+		fmt.Println(greenColor + "TestOptionalRules : Test4 : Passed" + resetColor)
+	}
+
+	//TEST 5: invalid critical multiplier
+	rules.CriticalMultiplier = 0
+	if err := rules.Validate(); err == nil {
+		t.Errorf(redColor + "Expected a critical multiplier of 0 to be rejected" + resetColor)
+	} else {
+		fmt.Println(greenColor + "TestOptionalRules : Test5 : Passed" + resetColor)
+	}
+}
@@ -0,0 +1,173 @@
+package match
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Errors returned for spells, usable with errors.Is.
+var (
+	ErrUnknownSpell     = errors.New("unknown spell")
+	ErrInsufficientMana = errors.New("not enough mana to cast the spell")
+	ErrSpellOnCooldown  = errors.New("spell is still on cooldown")
+)
+
+// The names of the spells in the spellbook.
+const (
+	SpellFireball = "fireball"
+	SpellHeal     = "heal"
+	SpellShield   = "shield"
+	SpellDrain    = "drain"
+	SpellVenom    = "venom"
+	SpellRenew    = "renew"
+	SpellIgnite   = "ignite"
+	SpellDaze     = "daze"
+)
+
+// Spell is an ability a player with mana can cast on their turn instead of attacking.
+type Spell struct {
+	Name        string // Name identifies the spell.
+	Cost        int    // Cost is the mana the caster spends to cast the spell.
+	Cooldown    int    // Cooldown is the number of the caster's turns after casting during which the spell cannot be cast again.
+	Description string // Description explains what the spell does.
+}
+
+// spellbook lists every spell a player can cast, cheapest first.
+var spellbook = []Spell{
+	{Name: SpellShield, Cost: 6, Cooldown: 3, Description: "Halves the damage of the next attack or spell that hurts the caster."},
+	{Name: SpellVenom, Cost: 7, Cooldown: 2, Description: "Poisons the opponent for 3 turns, stacking up to 3 times."},
+	{Name: SpellHeal, Cost: 8, Cooldown: 3, Description: "Restores a quarter of the caster's starting health."},
+	{Name: SpellRenew, Cost: 9, Cooldown: 3, Description: "Restores 3 health now and at the start of the caster's next 2 turns."},
+	{Name: SpellFireball, Cost: 10, Cooldown: 2, Description: "Burns the opponent for attack times a roll, ignoring their strength."},
+	{Name: SpellIgnite, Cost: 11, Cooldown: 3, Description: "Sets the opponent burning for 3 turns, lowering their strength."},
+	{Name: SpellDrain, Cost: 12, Cooldown: 2, Description: "Strikes the opponent and heals the caster by the damage dealt."},
+	{Name: SpellDaze, Cost: 15, Cooldown: 4, Description: "Stuns the opponent, who loses their next turn."},
+}
+
+// Spells returns the spellbook: every spell a player with mana can cast, cheapest first. Their effects are:
+//   - shield halves the damage of the next attack or spell that hurts the caster.
+//   - heal restores a quarter of the caster's starting health, never above it.
+//   - fireball rolls the caster's attack die and deals attack*roll damage, weighted like an attack,
+//     which the opponent's strength and agility do nothing to stop.
+//   - drain strikes like an attack that cannot be dodged and heals the caster by the damage dealt.
+//   - venom, ignite and daze afflict the opponent with poison, burn and stun, and renew afflicts the
+//     caster with regen, restoring its health at once for the turn it is cast; see EffectKind.
+//
+// Returns:
+//   - []Spell: A copy of the spellbook.
+func Spells() []Spell {
+	return append([]Spell(nil), spellbook...)
+}
+
+// FindSpell returns the spell in the spellbook with the given name, ignoring case.
+//
+// Parameters:
+//   - name: The name of the spell.
+//
+// Returns:
+//   - Spell: The spell.
+//   - error: An error wrapping ErrUnknownSpell, if the spellbook has no spell of that name.
+//
+// Example:
+//
+//	fireball, err := FindSpell("Fireball")
+//	fmt.Printf("%s costs %d mana\n", fireball.Name, fireball.Cost)
+func FindSpell(name string) (Spell, error) {
+	name = strings.TrimSpace(name)
+	for _, spell := range spellbook {
+		if strings.EqualFold(spell.Name, name) {
+			return spell, nil
+		}
+	}
+	return Spell{}, fmt.Errorf("%w %q", ErrUnknownSpell, name)
+}
+
+// checkCast checks whether a player can cast a spell on their turn.
+//
+// Parameters:
+//   - name: The name of the player.
+//   - mana: The mana the player has left.
+//   - cooldown: The number of turns until the player can cast the spell again.
+//   - spell: The spell to cast.
+//
+// Returns:
+//   - error: An error wrapping ErrInsufficientMana or ErrSpellOnCooldown, or nil if the spell can be cast.
+func checkCast(name string, mana, cooldown int, spell Spell) error {
+	if mana < spell.Cost {
+		return fmt.Errorf("%w: %s costs %d, %s has %d", ErrInsufficientMana, spell.Name, spell.Cost, name, mana)
+	}
+	if cooldown > 0 {
+		return fmt.Errorf("%w: %s can cast %s again in %d turns", ErrSpellOnCooldown, name, spell.Name, cooldown)
+	}
+	return nil
+}
+
+// cast casts a spell, spending the caster's mana, starting its cooldown and recording its effect
+// in the event.
+//
+// Parameters:
+//   - dice: The dice of the match.
+//   - rules: The rules of the match.
+//   - spell: The spell to cast, which the caster must be able to cast.
+//   - caster: A pointer to the state of the casting player.
+//   - target: A pointer to the state of their opponent.
+//   - event: A pointer to the event of the round.
+func cast(dice Dice, rules Rules, spell Spell, caster, target *fighter, event *RoundEvent) {
+	caster.mana -= spell.Cost
+	// The cooldown counts down at the end of each of the caster's turns, this one included.
+	caster.cooldowns[spell.Name] = spell.Cooldown + 1
+	event.Spell = spell.Name
+
+	strike := strikeOn(rules, caster, target)
+	switch spell.Name {
+	case SpellFireball:
+		event.AttackRoll = dice.Roll(strike.AttackSides)
+		hurt(target, strike.Attack*event.AttackRoll*strike.AttackWeight/10000, event)
+	case SpellHeal:
+		event.Healing = heal(caster, caster.startHealth/4)
+	case SpellShield:
+		caster.shielded = true
+	case SpellDrain:
+		event.AttackRoll, event.DefenceRoll = strike.roll(dice)
+		hurt(target, strike.Damage(event.AttackRoll, event.DefenceRoll), event)
+		event.Healing = heal(caster, event.Damage)
+	case SpellVenom:
+		afflict(target, Effect{Kind: EffectPoison, Turns: effectTurns, Stacks: 1}, event)
+	case SpellRenew:
+		event.Healing = heal(caster, regenHealing)
+		afflict(caster, Effect{Kind: EffectRegen, Turns: effectTurns, Stacks: 1}, event)
+	case SpellIgnite:
+		afflict(target, Effect{Kind: EffectBurn, Turns: effectTurns, Stacks: 1}, event)
+	case SpellDaze:
+		afflict(target, Effect{Kind: EffectStun, Turns: 1, Stacks: 1}, event)
+	}
+}
+
+// autoPriority is the order in which autoAction considers the spells. Spells with status effects
+// are left to strategies.
+var autoPriority = []string{SpellHeal, SpellDrain, SpellFireball, SpellShield}
+
+// autoAction picks what a player does on their turn when the match plays it for them. A player
+// with mana casts the first spell they can of: heal when below half their starting health, drain
+// when hurt at all, fireball, and shield when not already shielded. Otherwise they attack.
+//
+// Parameters:
+//   - caster: A pointer to the state of the player whose turn it is.
+//
+// Returns:
+//   - Action: The action of the player.
+func autoAction(caster *fighter) Action {
+	for _, name := range autoPriority {
+		spell, _ := FindSpell(name)
+		switch {
+		case checkCast(caster.name, caster.mana, caster.cooldowns[name], spell) != nil:
+		case name == SpellHeal && caster.health*2 >= caster.startHealth:
+		case name == SpellDrain && caster.health >= caster.startHealth:
+		case name == SpellShield && caster.shielded:
+		default:
+			return Action{Kind: ActionCast, Spell: name}
+		}
+	}
+	return Action{Kind: ActionAttack}
+}
@@ -0,0 +1,103 @@
+package match
+
+import (
+	"errors"
+	"fmt"
+	"proj/pkg/player"
+	"testing"
+)
+
+// TestSpells tests casting the spells of the spellbook during a match.
+//
+// Test scenarios:
+//  1. A caster at full health casts fireball, which ignores the defender's strength: attack 10
+//     rolling 4 deals 40 damage against strength 100, and costs 10 of their 20 mana, of which they
+//     regenerate 2 after their turn.
+//  2. Over four turns, the caster casts fireball, then shield while fireball cools down, then
+//     attacks without the mana for either, then casts fireball again once its cooldown is over.
+//  3. A shielded defender takes half the damage of an attack, and the shield is used up.
+//  4. A caster below half health casts heal, recovering a quarter of their starting health, and
+//     then drain, which heals them by the damage dealt up to their starting health.
+//  5. Spells are found ignoring case, an unknown spell fails with ErrUnknownSpell, and casting
+//     without enough mana or during a cooldown fails with ErrInsufficientMana or ErrSpellOnCooldown.
+//  6. A player without mana never casts.
+func TestSpells(t *testing.T) {
+	//TEST 1: fireball
+	caster := newFighter(player.NewPlayer("PlayerA", 100, 10, 10, player.WithMana(20)))
+	defender := newFighter(player.NewPlayer("PlayerB", 100, 100, 10))
+	event := conductRound(NewScriptedDice(4), DefaultRules(), caster, defender, autoAction(caster))
+	if event.Spell != SpellFireball || event.Damage != 40 || defender.health != 60 || caster.mana != 12 ||
+		event.String() != "PlayerA cast fireball on PlayerB for 40 damage" {
+		t.Errorf(redColor+"Expected a fireball for 40 damage, got %+v and %d mana"+resetColor, event, caster.mana)
+	} else {
+		fmt.Println(greenColor + "TestSpells : Test1 : Passed" + resetColor)
+	}
+
+	//TEST 2: cooldowns and mana regeneration
+	caster = newFighter(player.NewPlayer("PlayerA", 100, 10, 10, player.WithMana(20)))
+	defender = newFighter(player.NewPlayer("PlayerB", 100, 100, 10))
+	dice := NewScriptedDice(1)
+	var spells []string
+	var mana []int
+	for turn := 0; turn < 4; turn++ {
+		spells = append(spells, conductRound(dice, DefaultRules(), caster, defender, autoAction(caster)).Spell)
+		mana = append(mana, caster.mana)
+	}
+	if fmt.Sprint(spells) != "[fireball shield  fireball]" || fmt.Sprint(mana) != "[12 8 10 2]" {
+		t.Errorf(redColor+"Expected fireball, shield, an attack and fireball, got %q with mana %v"+resetColor, spells, mana)
+	} else {
+		fmt.Println(greenColor + "TestSpells : Test2 : Passed" + resetColor)
+	}
+
+	//TEST 3: shield
+	attacker := newFighter(player.NewPlayer("PlayerA", 100, 10, 10))
+	defender = newFighter(player.NewPlayer("PlayerB", 100, 5, 10))
+	defender.shielded = true
+	event = conductRound(NewScriptedDice(6, 2), DefaultRules(), attacker, defender, Action{Kind: ActionAttack})
+	if event.Damage != 25 || event.Absorbed != 25 || defender.health != 75 || defender.shielded ||
+		event.String() != "PlayerA attacked PlayerB for 25 damage, 25 absorbed by the shield of PlayerB" {
+		t.Errorf(redColor+"Expected the shield to absorb half of 50 damage, got %+v"+resetColor, event)
+	} else {
+		fmt.Println(greenColor + "TestSpells : Test3 : Passed" + resetColor)
+	}
+
+	//TEST 4: heal and drain
+	caster = newFighter(player.NewPlayer("PlayerA", 100, 10, 10, player.WithMana(30)))
+	caster.health = 40
+	defender = newFighter(player.NewPlayer("PlayerB", 100, 5, 10))
+	dice = NewScriptedDice(6, 1)
+	healed := conductRound(dice, DefaultRules(), caster, defender, autoAction(caster))
+	healthAfterHeal := caster.health
+	drained := conductRound(dice, DefaultRules(), caster, defender, autoAction(caster))
+	if healed.Spell != SpellHeal || healed.Healing != 25 || healthAfterHeal != 65 || healed.String() != "PlayerA cast heal and recovered 25 health" ||
+		drained.Spell != SpellDrain || drained.Damage != 55 || drained.Healing != 35 || caster.health != 100 || defender.health != 45 ||
+		drained.String() != "PlayerA cast drain on PlayerB for 55 damage and recovered 35 health" {
+		t.Errorf(redColor+"Expected heal for 25 then drain for 55 healing 35, got %+v, %+v"+resetColor, healed, drained)
+	} else {
+		fmt.Println(greenColor + "TestSpells : Test4 : Passed" + resetColor)
+	}
+
+	//TEST 5: spellbook errors
+	fireball, err := FindSpell(" FIREBALL ")
+	_, errUnknown := FindSpell("meteor")
+	poor := newFighter(player.NewPlayer("PlayerA", 100, 10, 10, player.WithMana(5)))
+	tired := newFighter(player.NewPlayer("PlayerA", 100, 10, 10, player.WithMana(50)))
+	tired.cooldowns[SpellFireball] = 1
+	errMana := poor.state().CanCast(fireball)
+	errCooldown := tired.state().CanCast(fireball)
+	if err != nil || fireball.Cost != 10 || len(Spells()) != 8 || !errors.Is(errUnknown, ErrUnknownSpell) ||
+		!errors.Is(errMana, ErrInsufficientMana) || !errors.Is(errCooldown, ErrSpellOnCooldown) {
+		t.Errorf(redColor+"Expected fireball and the spell errors, got %+v, %v, %v, %v, %v"+resetColor, fireball, err, errUnknown, errMana, errCooldown)
+	} else {
+		fmt.Println(greenColor + "TestSpells : Test5 : Passed" + resetColor)
+	}
+
+	//TEST 6: no mana
+	attacker = newFighter(player.NewPlayer("PlayerA", 10, 10, 10))
+	attacker.health = 1
+	if action := autoAction(attacker); action.Kind != ActionAttack {
+		t.Errorf(redColor+"Expected a player without mana to attack, got %+v"+resetColor, action)
+	} else {
+		fmt.Println(greenColor + "TestSpells : Test6 : Passed" + resetColor)
+	}
+}
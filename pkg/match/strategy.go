@@ -0,0 +1,58 @@
+package match
+
+// Strategy chooses the actions of a player in a match played automatically, so players can be
+// controlled by anything from a fixed rule to a search of every dice outcome. The strategies of
+// the strategy package are ready to use.
+type Strategy interface {
+	// Choose picks the action of the player whose turn it is, the attacker of the turn.
+	//
+	// Parameters:
+	//   - turn: The state of the match at the start of the turn.
+	//
+	// Returns:
+	//   - Action: The action to take. An action the player cannot take is replaced by an attack.
+	Choose(turn Turn) Action
+}
+
+// WithStrategies makes ConductMatch ask the given strategies for the actions of the players.
+// A nil strategy leaves its player to the default: casting a spell whenever one is worth casting,
+// and otherwise attacking. If either player has a strategy and the rules set no MaxRounds, the
+// match is limited to SafetyRounds rounds, after which the tie-break rule decides it: strategies
+// can settle into a stalemate, such as one player defending every turn against an opponent too
+// weak to hurt them through it.
+//
+// Parameters:
+//   - strategyA: The strategy of Player A, or nil.
+//   - strategyB: The strategy of Player B, or nil.
+//
+// Returns:
+//   - Option: An option to pass to NewMatch.
+//
+// Example:
+//
+//	m, err := NewMatch(playerA, playerB, WithStrategies(strategy.NewGreedy(), strategy.NewExpectimax()))
+func WithStrategies(strategyA, strategyB Strategy) Option {
+	return func(m *Match) {
+		m.strategyA, m.strategyB = strategyA, strategyB
+	}
+}
+
+// chooseAction picks the action of the player whose turn it is, asking their strategy if they
+// have one.
+//
+// Returns:
+//   - Action: An action the player can take.
+func (m *Match) chooseAction() Action {
+	strategy := m.strategyA
+	if m.attacker == m.b {
+		strategy = m.strategyB
+	}
+	if strategy == nil {
+		return autoAction(m.attacker)
+	}
+	action := strategy.Choose(m.NextTurn())
+	if checkAction(m.attacker, action) != nil {
+		return Action{Kind: ActionAttack}
+	}
+	return action
+}
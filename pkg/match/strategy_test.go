@@ -0,0 +1,83 @@
+package match
+
+import (
+	"fmt"
+	"proj/pkg/player"
+	"testing"
+)
+
+// fixedStrategy is a Strategy that always takes the same action.
+type fixedStrategy Action
+
+// Choose returns the fixed action.
+func (s fixedStrategy) Choose(turn Turn) Action {
+	return Action(s)
+}
+
+// healer is a Strategy that heals whenever it can and defends otherwise, so it never attacks.
+type healer struct{}
+
+// Choose heals if heal is among the possible actions, and defends otherwise.
+func (healer) Choose(turn Turn) Action {
+	for _, action := range turn.Actions() {
+		if action.Kind == ActionHeal {
+			return action
+		}
+	}
+	return Action{Kind: ActionDefend}
+}
+
+// TestStrategies tests that ConductMatch asks the players' strategies for their actions.
+//
+// Test scenarios:
+//  1. A player whose strategy always defends never attacks, and loses.
+//  2. An action the player cannot take, such as casting without mana, is replaced by an attack,
+//     and a player without a strategy attacks as before.
+//  3. A player who heals whenever they can and defends otherwise cannot out-heal an opponent with
+//     attack 8, and is knocked out well within 1000 rounds under the default heal cooldown.
+func TestStrategies(t *testing.T) {
+	playerA := player.NewPlayer("PlayerA", 100, 10, 10)
+	playerB := player.NewPlayer("PlayerB", 100, 10, 10)
+
+	//TEST 1: always defending
+	m, _ := NewMatch(playerA, playerB, WithSeed(1), WithStrategies(fixedStrategy{Kind: ActionDefend}, nil))
+	_, outcome := ConductMatch(m)
+	passed := outcome.Winner == playerB
+	for _, event := range m.Events() {
+		if event.Attacker == "PlayerA" && event.Action != ActionDefend || event.Attacker == "PlayerB" && event.Action != "" {
+			passed = false
+		}
+	}
+	if !passed {
+		t.Errorf(redColor+"Expected PlayerA to defend every turn and lose, got %v in %v"+resetColor, outcome, m.RoundResults())
+	} else {
+		fmt.Println(greenColor + "TestStrategies : Test1 : Passed" + resetColor)
+	}
+
+	//TEST 2: impossible actions
+	m, _ = NewMatch(playerA, playerB, WithSeed(1), WithStrategies(fixedStrategy{Kind: ActionCast, Spell: SpellFireball}, nil))
+	ConductMatch(m)
+	plain, _ := NewMatch(playerA, playerB, WithSeed(1))
+	ConductMatch(plain)
+	if fmt.Sprint(m.Events()) != fmt.Sprint(plain.Events()) {
+		t.Errorf(redColor+"Expected impossible casts to be replaced by attacks, got %v"+resetColor, m.RoundResults())
+	} else {
+		fmt.Println(greenColor + "TestStrategies : Test2 : Passed" + resetColor)
+	}
+
+	//TEST 3: healing every turn it can
+	rules := DefaultRules()
+	rules.MaxRounds = 1000
+	attacker := player.NewPlayer("PlayerB", 100, 10, 8)
+	passed = true
+	for seed := int64(1); seed <= 5; seed++ {
+		m, _ = NewMatch(playerA, attacker, WithSeed(seed), WithRules(rules), WithStrategies(healer{}, nil))
+		if _, outcome = ConductMatch(m); outcome.Winner != attacker || outcome.Reason != Win {
+			t.Errorf(redColor+"Expected the healing PlayerA to lose with seed %d, got %v"+resetColor, seed, outcome)
+			passed = false
+		}
+	}
+	if passed {
+		fmt.Println(greenColor + "TestStrategies : Test3 : Passed" + resetColor)
+	}
+}
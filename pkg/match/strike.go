@@ -0,0 +1,165 @@
+package match
+
+import "proj/pkg/player"
+
+// Strike describes how one player attacks another under the rules of a match, once the class of
+// each player is applied: the dice both players roll, how much their attributes weigh, and the
+// chances of a critical hit, a fumble or a dodge.
+//
+// The damage of a strike is (attack*attackRoll*AttackWeight - strength*defenceRoll*DefenceWeight) / 10000,
+// never below zero. For players without a class the weights are the percentages of the rules
+// times 100, so the damage is exactly that of Rules.Damage. A critical hit multiplies the damage
+// and a fumble deals none.
+type Strike struct {
+	Attack        int // Attack is the attack attribute of the attacker.
+	Strength      int // Strength is the strength attribute of the defender.
+	AttackSides   int // AttackSides is the number of sides of the attacker's die.
+	DefenceSides  int // DefenceSides is the number of sides of the defender's dice.
+	DefenceRolls  int // DefenceRolls is the number of dice the defender rolls, of which the highest counts.
+	AttackWeight  int // AttackWeight weighs the attacker's attack*roll, in hundredths of a percent.
+	DefenceWeight int // DefenceWeight weighs the defender's strength*roll, in hundredths of a percent.
+
+	Criticals          bool // Criticals is true if an attack roll of AttackSides is a critical hit.
+	CriticalMultiplier int  // CriticalMultiplier multiplies the damage of a critical hit.
+	Fumbles            bool // Fumbles is true if an attack roll of 1 misses.
+	DodgeChance        int  // DodgeChance is the defender's chance, in percent, of dodging an attack that did not fumble.
+}
+
+// NewStrike describes how an attacker strikes a defender under the given rules.
+//
+// Parameters:
+//   - rules: The rules of the match.
+//   - attacker: A pointer to the attacking player.
+//   - defender: A pointer to the defending player.
+//
+// Returns:
+//   - Strike: The dice and weights of the strike.
+//
+// Example:
+//
+//	s := NewStrike(DefaultRules(), mage, warrior)
+//	fmt.Println(s.Damage(s.AttackSides, 1)) // the most damage the mage can deal
+func NewStrike(rules Rules, attacker, defender *player.Player) Strike {
+	_, _, _, attack := player.GetPlayerBaseAttributes(attacker)
+	_, _, strength, _ := player.GetPlayerBaseAttributes(defender)
+	return newStrike(rules, attack, player.GetPlayerClass(attacker), strength, player.GetPlayerAgility(defender), player.GetPlayerClass(defender))
+}
+
+// newStrike describes a strike from the attributes and classes of both players.
+//
+// Parameters:
+//   - rules: The rules of the match.
+//   - attack: The attack attribute of the attacker.
+//   - attackerClass: The class of the attacker.
+//   - strength: The strength attribute of the defender.
+//   - agility: The agility attribute of the defender.
+//   - defenderClass: The class of the defender.
+//
+// Returns:
+//   - Strike: The dice and weights of the strike.
+func newStrike(rules Rules, attack int, attackerClass player.Class, strength, agility int, defenderClass player.Class) Strike {
+	s := Strike{
+		Attack:        attack,
+		Strength:      strength,
+		AttackSides:   rules.DiceSides,
+		DefenceSides:  rules.DiceSides,
+		DefenceRolls:  max(1, defenderClass.DefenceRolls),
+		AttackWeight:  rules.AttackPercent * (100 + attackerClass.Power),
+		DefenceWeight: rules.DefencePercent * (100 + defenderClass.Armour),
+
+		Criticals:          rules.Criticals,
+		CriticalMultiplier: rules.CriticalMultiplier,
+		Fumbles:            rules.Fumbles,
+	}
+	if rules.Dodge {
+		s.DodgeChance = agility
+	}
+	if attackerClass.AttackDice > 0 {
+		s.AttackSides = attackerClass.AttackDice
+	}
+	if defenderClass.DefenceDice > 0 {
+		s.DefenceSides = defenderClass.DefenceDice
+	}
+	return s
+}
+
+// Damage calculates the damage of the strike given both dice rolls, taking critical hits and
+// fumbles into account. A dodge is not a matter of the rolls, so it is left to the caller.
+//
+// Parameters:
+//   - attackRoll: The value rolled on the attacker's die.
+//   - defenceRoll: The value of the defender's highest die.
+//
+// Returns:
+//   - int: The damage dealt to the defender.
+func (s Strike) Damage(attackRoll, defenceRoll int) int {
+	if s.IsFumble(attackRoll) {
+		return 0
+	}
+	damage := max(0, s.Attack*attackRoll*s.AttackWeight-s.Strength*defenceRoll*s.DefenceWeight) / 10000
+	if s.IsCritical(attackRoll) {
+		damage *= s.CriticalMultiplier
+	}
+	return damage
+}
+
+// IsCritical reports whether an attack roll is a critical hit: the highest face of the attack
+// die, when critical hits are on.
+//
+// Parameters:
+//   - attackRoll: The value rolled on the attacker's die.
+//
+// Returns:
+//   - bool: true for a critical hit, false otherwise.
+func (s Strike) IsCritical(attackRoll int) bool {
+	return s.Criticals && attackRoll == s.AttackSides
+}
+
+// IsFumble reports whether an attack roll is a fumble: a 1, when fumbles are on.
+//
+// Parameters:
+//   - attackRoll: The value rolled on the attacker's die.
+//
+// Returns:
+//   - bool: true for a fumble, false otherwise.
+func (s Strike) IsFumble(attackRoll int) bool {
+	return s.Fumbles && attackRoll == 1
+}
+
+// CanPenetrate reports whether the strike can ever deal damage, that is whether the best possible
+// attack roll against the worst possible defence roll deals any damage.
+//
+// Returns:
+//   - bool: true if the attacker can deal damage, false otherwise.
+func (s Strike) CanPenetrate() bool {
+	return s.Damage(s.AttackSides, 1) > 0
+}
+
+// roll rolls the attacker's die and the defender's dice.
+//
+// Parameters:
+//   - dice: The dice of the match.
+//
+// Returns:
+//   - int: The attack roll.
+//   - int: The highest defence roll.
+func (s Strike) roll(dice Dice) (int, int) {
+	attackRoll := dice.Roll(s.AttackSides)
+	defenceRoll := 0
+	for i := 0; i < s.DefenceRolls; i++ {
+		defenceRoll = max(defenceRoll, dice.Roll(s.DefenceSides))
+	}
+	return attackRoll, defenceRoll
+}
+
+// dodges rolls whether the defender dodges the attack. The 100-sided die is only rolled if the
+// defender has a chance of dodging.
+//
+// Parameters:
+//   - dice: The dice of the match.
+//
+// Returns:
+//   - bool: true if the defender dodges, false otherwise.
+func (s Strike) dodges(dice Dice) bool {
+	return s.DodgeChance > 0 && dice.Roll(100) <= s.DodgeChance
+}
@@ -0,0 +1,65 @@
+package match
+
+import (
+	"errors"
+	"fmt"
+	"proj/pkg/player"
+	"testing"
+)
+
+// sidesDice is a Dice that always rolls the highest face and records the sides of every die rolled.
+type sidesDice struct {
+	sides []int // sides holds the number of sides of every die rolled, in order.
+}
+
+// Roll records the sides of the die and rolls its highest face.
+func (d *sidesDice) Roll(sides int) int {
+	d.sides = append(d.sides, sides)
+	return sides
+}
+
+// TestClassMechanics tests how character classes change the strikes of a match.
+//
+// Test scenarios:
+//  1. A Warrior's armour adds a quarter to the weight of their strength: attack 10 rolling 6 against
+//     strength 10 rolling 4 deals 60 - 50 = 10 damage instead of 20.
+//  2. A Rogue rolls two defence dice and keeps the higher: rolls of 6, then 1 and 5, defend with 5.
+//  3. A Mage attacks with a 10-sided die, and so can damage a defender an unclassed player cannot.
+func TestClassMechanics(t *testing.T) {
+	warrior, _ := player.FindClass("Warrior")
+	mage, _ := player.FindClass("Mage")
+	rogue, _ := player.FindClass("Rogue")
+
+	//TEST 1: Warrior armour
+	attacker := &fighter{name: "PlayerA", health: 100, strength: 10, attack: 10}
+	defender := &fighter{name: "PlayerB", health: 100, strength: 10, attack: 10, class: warrior}
+	event := conductRound(NewScriptedDice(6, 4), DefaultRules(), attacker, defender, Action{Kind: ActionAttack})
+	if event.Damage != 10 || defender.health != 90 {
+		t.Errorf(redColor+"Expected the Warrior to take 10 damage, got %+v"+resetColor, event)
+	} else {
+		fmt.Println(greenColor + "TestClassMechanics : Test1 : Passed" + resetColor)
+	}
+
+	//TEST 2: Rogue defence rolls
+	defender = &fighter{name: "PlayerB", health: 100, strength: 10, attack: 10, class: rogue}
+	event = conductRound(NewScriptedDice(6, 1, 5), DefaultRules(), attacker, defender, Action{Kind: ActionAttack})
+	if event.AttackRoll != 6 || event.DefenceRoll != 5 || event.Damage != 10 {
+		t.Errorf(redColor+"Expected the Rogue to defend with 5 and take 10 damage, got %+v"+resetColor, event)
+	} else {
+		fmt.Println(greenColor + "TestClassMechanics : Test2 : Passed" + resetColor)
+	}
+
+	//TEST 3: Mage attack die
+	dice := &sidesDice{}
+	plain := player.NewPlayer("PlayerA", 100, 12, 2)
+	wizard := player.NewPlayer("PlayerA", 100, 12, 2, player.WithClass(mage))
+	opponent := player.NewPlayer("PlayerB", 50, 12, 10)
+	_, err := NewMatch(plain, opponent)
+	m := newTestMatch(t, wizard, opponent, WithDice(dice), WithRules(Rules{DiceSides: 6, FirstMover: FirstMoverPlayerA, AttackPercent: 100, DefencePercent: 100, MaxRounds: 1, TieBreak: TieBreakDraw}))
+	ConductMatch(m)
+	if !errors.Is(err, ErrAttackCannotPenetrate) || len(dice.sides) != 2 || dice.sides[0] != 10 || dice.sides[1] != 6 {
+		t.Errorf(redColor+"Expected the Mage alone to penetrate with a 10-sided die, got %v, %v"+resetColor, err, dice.sides)
+	} else {
+		fmt.Println(greenColor + "TestClassMechanics : Test3 : Passed" + resetColor)
+	}
+}
@@ -0,0 +1,400 @@
+package match
+
+import (
+	"errors"
+	"fmt"
+	"proj/pkg/player"
+)
+
+// Errors returned by PlayTurn, usable with errors.Is. Spells that cannot be cast are reported
+// with the spell errors, such as ErrInsufficientMana.
+var (
+	ErrMatchOver      = errors.New("the match is over")
+	ErrUnknownAction  = errors.New("unknown action")
+	ErrHealOnCooldown = errors.New("heal is still on cooldown")
+)
+
+// ActionKind is the kind of action a player takes on their turn.
+type ActionKind string
+
+const (
+	// ActionAttack strikes the opponent with the player's dice.
+	ActionAttack ActionKind = "attack"
+	// ActionDefend doubles the player's strength roll against every attack until their next turn.
+	ActionDefend ActionKind = "defend"
+	// ActionHeal tends the player's wounds, restoring a tenth of their starting health. A player who
+	// heals cannot heal again for the HealCooldown of the rules.
+	ActionHeal ActionKind = "heal"
+	// ActionCast casts a spell from the spellbook.
+	ActionCast ActionKind = "cast"
+)
+
+// Action is what a player does on their turn.
+type Action struct {
+	Kind  ActionKind // Kind is the kind of action.
+	Spell string     // Spell is the name of the spell to cast, for ActionCast.
+}
+
+// FighterState is the state of a player at a point in a match.
+type FighterState struct {
+	Name        string         // Name is the name of the player.
+	Health      int            // Health is the current health of the player.
+	StartHealth int            // StartHealth is the health the player started the match with, which healing never exceeds.
+	Strength    int            // Strength is the strength attribute of the player.
+	Attack      int            // Attack is the attack attribute of the player.
+	Agility     int            // Agility is the agility attribute of the player.
+	Class       player.Class   // Class is the character class of the player.
+	Mana        int            // Mana is the mana the player has left to cast spells.
+	MaxMana     int            // MaxMana is the mana pool of the player.
+	Cooldowns   map[string]int // Cooldowns holds the number of the player's turns until each spell can be cast again; spells not listed are ready.
+	HealReady   int            // HealReady is the number of the player's turns until they can heal again, 0 if they can heal now.
+	Shielded    bool           // Shielded is true while a shield spell protects the player.
+	Defending   bool           // Defending is true while the player's strength roll counts double.
+	Effects     []Effect       // Effects are the status effects on the player, in the order they were first applied.
+}
+
+// CanCast checks whether the player can cast a spell on their turn.
+//
+// Parameters:
+//   - spell: The spell to cast.
+//
+// Returns:
+//   - error: An error wrapping ErrInsufficientMana or ErrSpellOnCooldown, or nil if the spell can be cast.
+func (s FighterState) CanCast(spell Spell) error {
+	return checkCast(s.Name, s.Mana, s.Cooldowns[spell.Name], spell)
+}
+
+// Stunned reports whether the player is stunned, and so loses their turn whatever their action.
+//
+// Returns:
+//   - bool: true if the player is stunned, false otherwise.
+func (s FighterState) Stunned() bool {
+	return findEffect(s.Effects, EffectStun) >= 0
+}
+
+// Turn is the state of a match at the start of a turn.
+type Turn struct {
+	Round    int          // Round is the number of the round about to be played.
+	Attacker FighterState // Attacker is the player whose turn it is.
+	Defender FighterState // Defender is their opponent.
+	Rules    Rules        // Rules are the rules the match is played under.
+}
+
+// Branch is one way a turn can play out.
+type Branch struct {
+	Probability float64    // Probability is the chance of the turn playing out this way.
+	Event       RoundEvent // Event describes the round.
+	Next        Turn       // Next is the state of the match at the start of the following turn.
+}
+
+// Over reports whether the match is over at the start of the turn: a player's health reached
+// zero or the round limit of the rules was reached.
+//
+// Returns:
+//   - bool: true if the turn cannot be played, false otherwise.
+func (t Turn) Over() bool {
+	return isMatchOver(t.Attacker.Health, t.Defender.Health) || isRoundLimitReached(t.Rules, t.Round-1)
+}
+
+// Actions lists every action the player whose turn it is can take: attack, defend, heal unless
+// healing is cooling down, and cast each spell they have the mana for and that is not cooling
+// down. A stunned player loses their turn whatever they choose, so attacking is listed as their
+// only action.
+//
+// Returns:
+//   - []Action: The possible actions, attacking first.
+func (t Turn) Actions() []Action {
+	if t.Attacker.Stunned() {
+		return []Action{{Kind: ActionAttack}}
+	}
+	actions := []Action{{Kind: ActionAttack}, {Kind: ActionDefend}}
+	if t.Attacker.HealReady == 0 {
+		actions = append(actions, Action{Kind: ActionHeal})
+	}
+	for _, spell := range spellbook {
+		if t.Attacker.CanCast(spell) == nil {
+			actions = append(actions, Action{Kind: ActionCast, Spell: spell.Name})
+		}
+	}
+	return actions
+}
+
+// Branches enumerates every way the turn can play out if the player whose turn it is takes an
+// action, by playing it with every combination of dice rolls. Combinations that are bound to
+// play out the same, such as all the defence rolls of a defender with several dice that leave
+// the same highest roll, are weighted together, so the probabilities add up to 1.
+//
+// Parameters:
+//   - action: The action of the player whose turn it is.
+//
+// Returns:
+//   - []Branch: The ways the turn can play out.
+//   - error: An error wrapping ErrUnknownAction or one of the spell errors, if the action cannot be taken.
+//
+// Example:
+//
+//	branches, _ := turn.Branches(Action{Kind: ActionAttack})
+//	expected := 0.0
+//	for _, b := range branches {
+//		expected += b.Probability * float64(b.Event.Damage)
+//	}
+func (t Turn) Branches(action Action) ([]Branch, error) {
+	attacker, defender := t.Attacker.fighter(), t.Defender.fighter()
+	if err := checkAction(attacker, action); err != nil {
+		return nil, err
+	}
+
+	sequences := []rollSequence{{nil, 1}}
+	if !t.Attacker.Stunned() {
+		sequences = rollSequences(strikeOn(t.Rules, attacker, defender), action)
+	}
+	branches := make([]Branch, len(sequences))
+	for i, sequence := range sequences {
+		attacker, defender := t.Attacker.fighter(), t.Defender.fighter()
+		// Actions that roll nothing have an empty sequence, which NewScriptedDice would reject.
+		dice := &ScriptedDice{rolls: sequence.rolls}
+		event := conductRound(dice, t.Rules, attacker, defender, action)
+		event.Round = t.Round
+		next := Turn{Round: t.Round + 1, Attacker: defender.state(), Defender: attacker.state(), Rules: t.Rules}
+		branches[i] = Branch{Probability: sequence.probability, Event: event, Next: next}
+	}
+	return branches, nil
+}
+
+// NextTurn returns the state of the match at the start of the next turn: whose turn it is, and
+// the health, mana and spells of both players.
+//
+// Returns:
+//   - Turn: The state of the match.
+//
+// Example:
+//
+//	turn := m.NextTurn()
+//	fmt.Printf("Round %d: %s to play with %d health\n", turn.Round, turn.Attacker.Name, turn.Attacker.Health)
+func (m *Match) NextTurn() Turn {
+	m.start()
+	return Turn{Round: len(m.events) + 1, Attacker: m.attacker.state(), Defender: m.defender.state(), Rules: m.rules}
+}
+
+// Over reports whether the match is over: a player's health reached zero or the round limit of
+// the rules was reached.
+//
+// Returns:
+//   - bool: true if no more turns can be played, false otherwise.
+func (m *Match) Over() bool {
+	m.start()
+	return isMatchOver(m.a.health, m.b.health) || isRoundLimitReached(m.rules, len(m.events))
+}
+
+// PlayTurn plays the next turn of the match with the action chosen by the player whose turn it
+// is, so a match can be played turn by turn. Once the match is over, ConductMatch returns its
+// outcome; if it is not over yet, ConductMatch plays the remaining turns automatically.
+//
+// Parameters:
+//   - action: The action of the player whose turn it is.
+//
+// Returns:
+//   - RoundEvent: The event describing the round.
+//   - error: ErrMatchOver if the match is over, or an error wrapping ErrUnknownAction,
+//     ErrHealOnCooldown, ErrUnknownSpell, ErrInsufficientMana or ErrSpellOnCooldown if the action
+//     cannot be taken.
+//     No turn is played on error.
+//
+// Example:
+//
+//	for !m.Over() {
+//		event, err := m.PlayTurn(Action{Kind: ActionAttack})
+//		...
+//	}
+//	_, outcome := ConductMatch(m)
+func (m *Match) PlayTurn(action Action) (RoundEvent, error) {
+	if m.Over() {
+		return RoundEvent{}, ErrMatchOver
+	}
+	if err := checkAction(m.attacker, action); err != nil {
+		return RoundEvent{}, err
+	}
+	return m.playTurn(action), nil
+}
+
+// start prepares the state of both players the first time the match is played or inspected.
+func (m *Match) start() {
+	if m.a != nil {
+		return
+	}
+	m.a, m.b = newFighter(m.PlayerA), newFighter(m.PlayerB)
+	m.attacker, m.defender = m.a, m.b
+	if determineStartingPlayer(m) == m.PlayerB {
+		m.attacker, m.defender = m.b, m.a
+	}
+}
+
+// playTurn plays the next turn of the match with a valid action, records its event and passes
+// the turn to the other player.
+//
+// Parameters:
+//   - action: The action of the player whose turn it is.
+//
+// Returns:
+//   - RoundEvent: The event describing the round.
+func (m *Match) playTurn(action Action) RoundEvent {
+	event := conductRound(m.dice, m.rules, m.attacker, m.defender, action)
+	event.Round = len(m.events) + 1
+	m.events = append(m.events, event)
+	m.attacker, m.defender = m.defender, m.attacker
+	return event
+}
+
+// checkAction checks whether a player can take an action on their turn.
+//
+// Parameters:
+//   - f: A pointer to the state of the player whose turn it is.
+//   - action: The action to take.
+//
+// Returns:
+//   - error: An error wrapping ErrUnknownAction, ErrHealOnCooldown or one of the spell errors, or
+//     nil if the action can be taken.
+func checkAction(f *fighter, action Action) error {
+	switch action.Kind {
+	case ActionAttack, ActionDefend:
+		return nil
+	case ActionHeal:
+		if f.healReady > 0 {
+			return fmt.Errorf("%w: %s can heal again in %d turns", ErrHealOnCooldown, f.name, f.healReady)
+		}
+		return nil
+	case ActionCast:
+		spell, err := FindSpell(action.Spell)
+		if err != nil {
+			return err
+		}
+		return checkCast(f.name, f.mana, f.cooldowns[spell.Name], spell)
+	}
+	return fmt.Errorf("%w %q", ErrUnknownAction, action.Kind)
+}
+
+// state captures the state of a fighter for callers outside the engine.
+//
+// Returns:
+//   - FighterState: The state of the player.
+func (f *fighter) state() FighterState {
+	cooldowns := make(map[string]int)
+	for name, turns := range f.cooldowns {
+		if turns > 0 {
+			cooldowns[name] = turns
+		}
+	}
+	return FighterState{
+		Name:        f.name,
+		Health:      f.health,
+		StartHealth: f.startHealth,
+		Strength:    f.strength,
+		Attack:      f.attack,
+		Agility:     f.agility,
+		Class:       f.class,
+		Mana:        f.mana,
+		MaxMana:     f.maxMana,
+		Cooldowns:   cooldowns,
+		HealReady:   f.healReady,
+		Shielded:    f.shielded,
+		Defending:   f.defending,
+		Effects:     append([]Effect(nil), f.effects...),
+	}
+}
+
+// fighter recreates the engine state of a player from their state.
+//
+// Returns:
+//   - *fighter: A pointer to a new fighter in the same state.
+func (s FighterState) fighter() *fighter {
+	cooldowns := make(map[string]int, len(s.Cooldowns))
+	for name, turns := range s.Cooldowns {
+		cooldowns[name] = turns
+	}
+	return &fighter{
+		name:        s.Name,
+		health:      s.Health,
+		startHealth: s.StartHealth,
+		strength:    s.Strength,
+		attack:      s.Attack,
+		agility:     s.Agility,
+		class:       s.Class,
+		mana:        s.Mana,
+		maxMana:     s.MaxMana,
+		cooldowns:   cooldowns,
+		healReady:   s.HealReady,
+		shielded:    s.Shielded,
+		defending:   s.Defending,
+		effects:     append([]Effect(nil), s.Effects...),
+	}
+}
+
+// rollSequence is a sequence of dice rolls that plays out a turn in one particular way.
+type rollSequence struct {
+	rolls       []int   // rolls are the values rolled, in the order the engine rolls them.
+	probability float64 // probability is the chance of the turn playing out this way.
+}
+
+// rollSequences enumerates the rolls an action can be played with. The defender's dice are
+// enumerated by their highest roll, with the remaining dice rolling 1, and a dodge roll only by
+// whether it dodges.
+//
+// Parameters:
+//   - s: The strike of the player whose turn it is on their opponent.
+//   - action: The action of the player whose turn it is.
+//
+// Returns:
+//   - []rollSequence: The roll sequences, whose probabilities add up to 1.
+func rollSequences(s Strike, action Action) []rollSequence {
+	spell, _ := FindSpell(action.Spell)
+	switch {
+	case action.Kind == ActionCast && spell.Name == SpellFireball:
+		sequences := make([]rollSequence, s.AttackSides)
+		for attackRoll := 1; attackRoll <= s.AttackSides; attackRoll++ {
+			sequences[attackRoll-1] = rollSequence{[]int{attackRoll}, 1 / float64(s.AttackSides)}
+		}
+		return sequences
+	case action.Kind != ActionAttack && !(action.Kind == ActionCast && spell.Name == SpellDrain):
+		return []rollSequence{{nil, 1}}
+	}
+
+	var sequences []rollSequence
+	combinations := float64(s.AttackSides * power(s.DefenceSides, s.DefenceRolls))
+	for attackRoll := 1; attackRoll <= s.AttackSides; attackRoll++ {
+		for defenceRoll := 1; defenceRoll <= s.DefenceSides; defenceRoll++ {
+			rolls := []int{attackRoll, defenceRoll}
+			for i := 1; i < s.DefenceRolls; i++ {
+				rolls = append(rolls, 1)
+			}
+			// The ways for the highest of the defence dice to be exactly defenceRoll.
+			probability := float64(power(defenceRoll, s.DefenceRolls)-power(defenceRoll-1, s.DefenceRolls)) / combinations
+
+			// Only an attack can be dodged, and only if it did not fumble.
+			if action.Kind != ActionAttack || s.IsFumble(attackRoll) || s.DodgeChance == 0 {
+				sequences = append(sequences, rollSequence{rolls, probability})
+				continue
+			}
+			dodge := float64(s.DodgeChance) / 100
+			sequences = append(sequences,
+				rollSequence{append(rolls[:len(rolls):len(rolls)], s.DodgeChance), probability * dodge},
+				rollSequence{append(rolls[:len(rolls):len(rolls)], 100), probability * (1 - dodge)})
+		}
+	}
+	return sequences
+}
+
+// power raises a base to a non-negative integer exponent.
+//
+// Parameters:
+//   - base: The base.
+//   - exponent: The exponent.
+//
+// Returns:
+//   - int: base to the power of exponent.
+func power(base, exponent int) int {
+	result := 1
+	for i := 0; i < exponent; i++ {
+		result *= base
+	}
+	return result
+}
@@ -0,0 +1,192 @@
+package match
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"proj/pkg/player"
+	"reflect"
+	"testing"
+)
+
+// TestPlayTurn tests playing a match turn by turn with chosen actions.
+//
+// Test scenarios:
+//  1. The first turn belongs to the starting player, and attacking passes the turn: attack 10
+//     rolling 6 against strength 10 rolling 1 deals 50 damage.
+//  2. A defending player's strength roll counts double against the next attack: rolling 6 against
+//     2 deals 60 - 40 = 20 damage instead of 40, and the stance ends with that attack.
+//  3. Healing restores a tenth of the starting health.
+//  4. Casting an unknown spell fails without playing the turn, and a fireball puts the spell on
+//     cooldown and spends mana.
+//  5. ConductMatch finishes the match automatically, after which PlayTurn fails with ErrMatchOver,
+//     and an unknown action fails with ErrUnknownAction.
+func TestPlayTurn(t *testing.T) {
+	rules := DefaultRules()
+	rules.FirstMover = FirstMoverPlayerA
+	playerA := player.NewPlayer("PlayerA", 100, 10, 10, player.WithMana(20))
+	playerB := player.NewPlayer("PlayerB", 100, 10, 10)
+	m, _ := NewMatch(playerA, playerB, WithRules(rules), WithDice(NewScriptedDice(6, 1, 6, 2, 3)))
+
+	//TEST 1: attack
+	first := m.NextTurn()
+	event, err := m.PlayTurn(Action{Kind: ActionAttack})
+	if err != nil || first.Round != 1 || first.Attacker.Name != "PlayerA" || first.Attacker.Mana != 20 || event.Damage != 50 ||
+		m.NextTurn().Attacker.Name != "PlayerB" || m.NextTurn().Round != 2 {
+		t.Errorf(redColor+"Expected PlayerA to deal 50 damage and pass the turn, got %+v, %+v, %v"+resetColor, first, event, err)
+	} else {
+		fmt.Println(greenColor + "TestPlayTurn : Test1 : Passed" + resetColor)
+	}
+
+	//TEST 2: defend
+	defended, errDefend := m.PlayTurn(Action{Kind: ActionDefend})
+	defending := m.NextTurn().Defender.Defending
+	event, err = m.PlayTurn(Action{Kind: ActionAttack})
+	if errDefend != nil || err != nil || !defending || defended.String() != "PlayerB took a defensive stance" ||
+		event.Damage != 20 || event.DefenderHealthAfter != 30 || m.NextTurn().Attacker.Defending {
+		t.Errorf(redColor+"Expected the defence to halve the damage to 20, got %+v, %+v"+resetColor, defended, event)
+	} else {
+		fmt.Println(greenColor + "TestPlayTurn : Test2 : Passed" + resetColor)
+	}
+
+	//TEST 3: heal
+	event, err = m.PlayTurn(Action{Kind: ActionHeal})
+	if err != nil || event.Healing != 10 || m.NextTurn().Defender.Health != 40 || event.String() != "PlayerB tended their wounds and recovered 10 health" {
+		t.Errorf(redColor+"Expected PlayerB to recover 10 health, got %+v, %v"+resetColor, event, err)
+	} else {
+		fmt.Println(greenColor + "TestPlayTurn : Test3 : Passed" + resetColor)
+	}
+
+	//TEST 4: cast
+	_, errUnknown := m.PlayTurn(Action{Kind: ActionCast, Spell: "meteor"})
+	event, err = m.PlayTurn(Action{Kind: ActionCast, Spell: "Fireball"})
+	caster := m.NextTurn().Defender
+	if !errors.Is(errUnknown, ErrUnknownSpell) || err != nil || event.Round != 5 || event.Damage != 30 || caster.Mana != 12 ||
+		caster.Cooldowns[SpellFireball] != 2 || !errors.Is(caster.CanCast(Spell{Name: SpellFireball}), ErrSpellOnCooldown) {
+		t.Errorf(redColor+"Expected an unknown spell, then a fireball for 30 damage, got %v, %+v, %v, %+v"+resetColor, errUnknown, event, err, caster)
+	} else {
+		fmt.Println(greenColor + "TestPlayTurn : Test4 : Passed" + resetColor)
+	}
+
+	//TEST 5: finishing the match
+	_, errAction := m.PlayTurn(Action{Kind: "dance"})
+	_, outcome := ConductMatch(m)
+	_, errOver := m.PlayTurn(Action{Kind: ActionAttack})
+	if !errors.Is(errAction, ErrUnknownAction) || !m.Over() || outcome.Rounds < 6 || !reflect.DeepEqual(m.Events()[4], event) || !errors.Is(errOver, ErrMatchOver) {
+		t.Errorf(redColor+"Expected the match to be finished automatically, got %v, %+v, %v"+resetColor, errAction, outcome, errOver)
+	} else {
+		fmt.Println(greenColor + "TestPlayTurn : Test5 : Passed" + resetColor)
+	}
+}
+
+// TestHealCooldown tests that a player who heals cannot heal again until the heal cooldown of the
+// rules has passed.
+//
+// Test scenarios:
+//  1. After healing, heal is missing from the player's actions and healing fails with
+//     ErrHealOnCooldown without playing the turn, for 3 of their turns under the default rules.
+//  2. On their 4th turn after healing, the player can heal again.
+func TestHealCooldown(t *testing.T) {
+	rules := DefaultRules()
+	rules.FirstMover = FirstMoverPlayerA
+	m := newTestMatch(t, player.NewPlayer("PlayerA", 100, 10, 10), player.NewPlayer("PlayerB", 100, 10, 10), WithDice(NewFixedDice(1)), WithRules(rules))
+
+	//TEST 1: cooling down
+	if _, err := m.PlayTurn(Action{Kind: ActionHeal}); err != nil {
+		t.Fatal(err)
+	}
+	passed := true
+	for i := 3; i > 0; i-- {
+		m.PlayTurn(Action{Kind: ActionDefend})
+		turn := m.NextTurn()
+		_, err := m.PlayTurn(Action{Kind: ActionHeal})
+		if turn.Attacker.HealReady != i || len(turn.Actions()) != 2 || !errors.Is(err, ErrHealOnCooldown) || m.NextTurn().Round != turn.Round {
+			t.Errorf(redColor+"Expected heal to be ready in %d turns, got %+v, %v, %v"+resetColor, i, turn.Attacker, turn.Actions(), err)
+			passed = false
+		}
+		m.PlayTurn(Action{Kind: ActionDefend})
+	}
+	if passed {
+		fmt.Println(greenColor + "TestHealCooldown : Test1 : Passed" + resetColor)
+	}
+
+	//TEST 2: ready again
+	m.PlayTurn(Action{Kind: ActionDefend})
+	turn := m.NextTurn()
+	event, err := m.PlayTurn(Action{Kind: ActionHeal})
+	if turn.Attacker.HealReady != 0 || len(turn.Actions()) != 3 || err != nil || event.Action != ActionHeal {
+		t.Errorf(redColor+"Expected PlayerA to heal again, got %+v, %+v, %v"+resetColor, turn.Attacker, event, err)
+	} else {
+		fmt.Println(greenColor + "TestHealCooldown : Test2 : Passed" + resetColor)
+	}
+}
+
+// TestBranches tests enumerating the ways a turn can play out.
+//
+// Test scenarios:
+//  1. An attack of 10 against strength 10 with six-sided dice has 36 branches adding up to 1,
+//     and deals 350/36 damage on average.
+//  2. Against a Rogue with agility 20, under the dodge rule, the attack has 72 branches, and the
+//     Rogue dodges with probability 0.2.
+//  3. A fireball has a branch per attack roll, and a heal a single certain branch, after which the
+//     opponent is to play the next round.
+//  4. A player without mana can attack, defend or heal; with 10 mana they can also cast shield, venom,
+//     heal, renew and fireball but not ignite, drain or daze.
+func TestBranches(t *testing.T) {
+	turn := Turn{
+		Round:    1,
+		Attacker: FighterState{Name: "PlayerA", Health: 100, StartHealth: 100, Strength: 10, Attack: 10, Mana: 10, MaxMana: 10},
+		Defender: FighterState{Name: "PlayerB", Health: 100, StartHealth: 100, Strength: 10, Attack: 10},
+		Rules:    DefaultRules(),
+	}
+
+	//TEST 1: attack
+	branches, err := turn.Branches(Action{Kind: ActionAttack})
+	var total, damage float64
+	for _, branch := range branches {
+		total += branch.Probability
+		damage += branch.Probability * float64(branch.Event.Damage)
+	}
+	if err != nil || len(branches) != 36 || math.Abs(total-1) > 1e-9 || math.Abs(damage-350.0/36) > 1e-9 {
+		t.Errorf(redColor+"Expected 36 branches averaging %.3f damage, got %d branches adding up to %f averaging %f, %v"+resetColor, 350.0/36, len(branches), total, damage, err)
+	} else {
+		fmt.Println(greenColor + "TestBranches : Test1 : Passed" + resetColor)
+	}
+
+	//TEST 2: dodging Rogue
+	rogue, _ := player.FindClass("Rogue")
+	dodging := turn
+	dodging.Rules.Dodge = true
+	dodging.Defender.Class, dodging.Defender.Agility = rogue, 20
+	branches, err = dodging.Branches(Action{Kind: ActionAttack})
+	var dodged float64
+	for _, branch := range branches {
+		if branch.Event.Dodged {
+			dodged += branch.Probability
+		}
+	}
+	if err != nil || len(branches) != 72 || math.Abs(dodged-0.2) > 1e-9 {
+		t.Errorf(redColor+"Expected 72 branches dodging with probability 0.2, got %d, %f, %v"+resetColor, len(branches), dodged, err)
+	} else {
+		fmt.Println(greenColor + "TestBranches : Test2 : Passed" + resetColor)
+	}
+
+	//TEST 3: fireball and heal
+	fireballs, errFireball := turn.Branches(Action{Kind: ActionCast, Spell: SpellFireball})
+	heals, errHeal := turn.Branches(Action{Kind: ActionHeal})
+	if errFireball != nil || errHeal != nil || len(fireballs) != 6 || fireballs[5].Event.Damage != 60 || len(heals) != 1 ||
+		heals[0].Probability != 1 || heals[0].Next.Round != 2 || heals[0].Next.Attacker.Name != "PlayerB" {
+		t.Errorf(redColor+"Expected 6 fireball branches and a certain heal, got %+v, %+v"+resetColor, fireballs, heals)
+	} else {
+		fmt.Println(greenColor + "TestBranches : Test3 : Passed" + resetColor)
+	}
+
+	//TEST 4: possible actions
+	withoutMana := turn
+	withoutMana.Attacker.Mana = 0
+	if actions := turn.Actions(); len(withoutMana.Actions()) != 3 || fmt.Sprint(actions) != "[{attack } {defend } {heal } {cast shield} {cast venom} {cast heal} {cast renew} {cast fireball}]" {
+		t.Errorf(redColor+"Expected 3 actions without mana and 8 with it, got %v, %v"+resetColor, withoutMana.Actions(), actions)
+	} else {
+		fmt.Println(greenColor + "TestBranches : Test4 : Passed" + resetColor)
+	}
+}
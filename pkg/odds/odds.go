@@ -0,0 +1,179 @@
+package odds
+
+import (
+	"errors"
+	"proj/pkg/match"
+	"proj/pkg/player"
+)
+
+// MaxStates is the largest number of health combinations Calculate will evaluate.
+const MaxStates = 1 << 22
+
+// Errors returned by Calculate, usable with errors.Is.
+var (
+	// ErrStateSpaceTooLarge is returned when the players' health values are too large to evaluate exactly.
+	ErrStateSpaceTooLarge = errors.New("player health too large to calculate exact odds")
+	// ErrSpellcaster is returned when a player has mana, since the spells they cast depend on more
+	// than their health.
+	ErrSpellcaster = errors.New("exact odds cannot be calculated for players who cast spells")
+)
+
+// Odds holds the exact outcome probabilities of a match between two players.
+type Odds struct {
+	WinA           float64 // WinA is the probability that Player A wins.
+	WinB           float64 // WinB is the probability that Player B wins.
+	ExpectedRounds float64 // ExpectedRounds is the expected number of rounds the match lasts.
+}
+
+// Calculate computes the exact probability of each player winning a match, and the expected number
+// of rounds, without sampling. It evaluates every reachable pair of health values by dynamic
+// programming, weighting each attack by the probability of every attack and defence roll
+// combination, exactly as match.ConductMatch plays them.
+//
+// The round limit of the rules is not taken into account: the odds are those of a match played
+// until one player falls. That is exact under the default rules, which set no limit; under rules
+// that set a MaxRounds, matches that would outlast it count as wins although the tie-break
+// decides them. Players with mana are not supported, as the spells they cast depend on their mana
+// and cooldowns as well as their health.
+//
+// Parameters:
+//   - playerA: A pointer to the first player in the match.
+//   - playerB: A pointer to the second player in the match.
+//   - rules: The rules the match is played under.
+//
+// Returns:
+//   - Odds: The outcome probabilities of the match.
+//   - error: An error, if the odds cannot be calculated: ErrStateSpaceTooLarge, ErrSpellcaster, or
+//     for matches rejected by match.NewMatch the same errors.
+//
+// Example:
+//
+//	o, err := Calculate(playerA, playerB, match.DefaultRules())
+//	fmt.Printf("A wins %.1f%% of the time\n", o.WinA*100)
+func Calculate(playerA, playerB *player.Player, rules match.Rules) (Odds, error) {
+	m, err := match.NewMatch(playerA, playerB, match.WithRules(rules))
+	if err != nil {
+		return Odds{}, err
+	}
+
+	_, healthA, _, _ := player.GetPlayerBaseAttributes(playerA)
+	_, healthB, _, _ := player.GetPlayerBaseAttributes(playerB)
+	if (healthA+1)*(healthB+1) > MaxStates {
+		return Odds{}, ErrStateSpaceTooLarge
+	}
+	if player.GetPlayerMana(playerA) > 0 || player.GetPlayerMana(playerB) > 0 {
+		return Odds{}, ErrSpellcaster
+	}
+
+	// Both players can damage each other, so missA and missB are below 1.
+	damageByA, missA := damageDistribution(match.NewStrike(rules, playerA, playerB))
+	damageByB, missB := damageDistribution(match.NewStrike(rules, playerB, playerA))
+
+	// winA*[i] is the probability that Player A wins, and rounds*[i] the expected number of remaining
+	// rounds, from the state indexed i with Player A (suffix A) or Player B (suffix B) about to attack.
+	stride := healthB + 1
+	states := (healthA + 1) * stride
+	winAA, winAB := make([]float64, states), make([]float64, states)
+	roundsA, roundsB := make([]float64, states), make([]float64, states)
+
+	// When an attack can deal no damage the turn passes with health unchanged, so each state depends
+	// on its mirror with the other player to move. Solving that pair of equations gives the divisor.
+	divisor := 1 - missA*missB
+
+	for hA := 1; hA <= healthA; hA++ {
+		for hB := 1; hB <= healthB; hB++ {
+			var winAfterA, roundsAfterA float64
+			for _, outcome := range damageByA {
+				if hB-outcome.damage <= 0 {
+					winAfterA += outcome.probability
+					continue
+				}
+				next := hA*stride + hB - outcome.damage
+				winAfterA += outcome.probability * winAB[next]
+				roundsAfterA += outcome.probability * roundsB[next]
+			}
+
+			var winAfterB, roundsAfterB float64
+			for _, outcome := range damageByB {
+				if hA-outcome.damage <= 0 {
+					continue
+				}
+				next := (hA-outcome.damage)*stride + hB
+				winAfterB += outcome.probability * winAA[next]
+				roundsAfterB += outcome.probability * roundsA[next]
+			}
+
+			i := hA*stride + hB
+			winAA[i] = (winAfterA + missA*winAfterB) / divisor
+			winAB[i] = winAfterB + missB*winAA[i]
+			roundsA[i] = (1 + roundsAfterA + missA*(1+roundsAfterB)) / divisor
+			roundsB[i] = 1 + roundsAfterB + missB*roundsA[i]
+		}
+	}
+
+	start := healthA*stride + healthB
+	winA, rounds := winAB[start], roundsB[start]
+	if m.StartingPlayer() == playerA {
+		winA, rounds = winAA[start], roundsA[start]
+	}
+	return Odds{WinA: winA, WinB: 1 - winA, ExpectedRounds: rounds}, nil
+}
+
+// damageOutcome is the probability of an attack dealing a particular positive amount of damage.
+type damageOutcome struct {
+	damage      int     // damage is the damage dealt.
+	probability float64 // probability is the chance of dealing exactly that damage.
+}
+
+// damageDistribution enumerates every attack and defence roll combination of a single attack.
+// A defender rolling several defence dice keeps the highest, so each defence roll is weighted by
+// the number of ways it can come out highest. Critical hits and fumbles are part of the damage of
+// each combination, and a defender who can dodge avoids every damaging attack with their chance.
+//
+// Parameters:
+//   - strike: The strike, which determines the dice and the damage formula.
+//
+// Returns:
+//   - []damageOutcome: The probability of each positive amount of damage.
+//   - float64: The probability that the attack deals no damage.
+func damageDistribution(strike match.Strike) ([]damageOutcome, float64) {
+	combinations := strike.AttackSides * power(strike.DefenceSides, strike.DefenceRolls)
+	counts := make(map[int]int)
+	var damages []int
+
+	for attackRoll := 1; attackRoll <= strike.AttackSides; attackRoll++ {
+		for defenceRoll := 1; defenceRoll <= strike.DefenceSides; defenceRoll++ {
+			damage := strike.Damage(attackRoll, defenceRoll)
+			if counts[damage] == 0 && damage > 0 {
+				damages = append(damages, damage)
+			}
+			// The ways for the highest of the defence dice to be exactly defenceRoll.
+			counts[damage] += power(defenceRoll, strike.DefenceRolls) - power(defenceRoll-1, strike.DefenceRolls)
+		}
+	}
+
+	hit := 1 - float64(strike.DodgeChance)/100
+	miss := 1.0
+	outcomes := make([]damageOutcome, len(damages))
+	for i, damage := range damages {
+		outcomes[i] = damageOutcome{damage, float64(counts[damage]) / float64(combinations) * hit}
+		miss -= outcomes[i].probability
+	}
+	return outcomes, max(0, miss)
+}
+
+// power raises a base to a non-negative integer exponent.
+//
+// Parameters:
+//   - base: The base.
+//   - exponent: The exponent.
+//
+// Returns:
+//   - int: base to the power of exponent.
+func power(base, exponent int) int {
+	result := 1
+	for i := 0; i < exponent; i++ {
+		result *= base
+	}
+	return result
+}
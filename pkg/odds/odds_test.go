@@ -0,0 +1,114 @@
+package odds
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"proj/pkg/match"
+	"proj/pkg/player"
+	"testing"
+)
+
+// ANSI escape codes for text color
+const (
+	redColor   = "\033[31m"
+	greenColor = "\033[32m"
+	resetColor = "\033[0m"
+)
+
+// TestCalculate tests the exact odds calculation.
+//
+// Test scenarios:
+//  1. Two players with 1 health who always deal damage: the starting player always wins in one round.
+//  2. Check that the exact odds agree with many seeded matches to within sampling error.
+//  3. Players who cannot damage each other are rejected like match.NewMatch rejects them, and a
+//     player with mana is rejected with ErrSpellcaster.
+//  4. Check that the exact odds of a Mage against a Rogue, who roll different dice and keep the
+//     higher of two defence dice, agree with many seeded matches.
+//  5. Check that the exact odds under critical hits, fumbles and dodging agree with many seeded matches.
+func TestCalculate(t *testing.T) {
+	//TEST 1: PlayerA has no more health than PlayerB, so attacks first and wins immediately
+	o, err := Calculate(player.NewPlayer("PlayerA", 1, 1, 10), player.NewPlayer("PlayerB", 1, 1, 10), match.DefaultRules())
+	if err != nil || math.Abs(o.WinA-1) > 1e-9 || math.Abs(o.ExpectedRounds-1) > 1e-9 {
+		t.Errorf(redColor+"Expected PlayerA to win in exactly 1 round, got %+v, %v"+resetColor, o, err)
+	} else {
+		fmt.Println(greenColor + "TestCalculate : Test1 : Passed" + resetColor)
+	}
+
+	//TEST 2: exact odds agree with sampled matches
+	playerA := player.NewPlayer("PlayerA", 50, 5, 10)
+	playerB := player.NewPlayer("PlayerB", 100, 10, 5)
+	o, err = Calculate(playerA, playerB, match.DefaultRules())
+	if err != nil {
+		t.Fatalf(redColor+"Expected Calculate to succeed, got %v"+resetColor, err)
+	}
+	sampledWinA, sampledRounds := sample(playerA, playerB, match.DefaultRules())
+	if math.Abs(sampledWinA-o.WinA) > 0.02 || math.Abs(sampledRounds-o.ExpectedRounds)/o.ExpectedRounds > 0.02 {
+		t.Errorf(redColor+"Expected odds %+v to agree with sampled win rate %.3f and rounds %.2f"+resetColor, o, sampledWinA, sampledRounds)
+	} else {
+		fmt.Println(greenColor + "TestCalculate : Test2 : Passed" + resetColor)
+	}
+
+	//TEST 3: no damage possible in either direction
+	_, err = Calculate(player.NewPlayer("PlayerA", 10, 100, 1), player.NewPlayer("PlayerB", 10, 100, 1), match.DefaultRules())
+	_, errMana := Calculate(player.NewPlayer("PlayerA", 10, 5, 10, player.WithMana(20)), player.NewPlayer("PlayerB", 10, 5, 10), match.DefaultRules())
+	if !errors.Is(err, match.ErrAttackCannotPenetrate) || !errors.Is(errMana, ErrSpellcaster) {
+		t.Errorf(redColor+"Expected match.ErrAttackCannotPenetrate and ErrSpellcaster, got %v, %v"+resetColor, err, errMana)
+	} else {
+		fmt.Println(greenColor + "TestCalculate : Test3 : Passed" + resetColor)
+	}
+
+	//TEST 4: classes
+	mage, _ := player.FindClass("Mage")
+	rogue, _ := player.FindClass("Rogue")
+	playerA = player.NewPlayer("PlayerA", 60, 6, 8, player.WithClass(mage))
+	playerB = player.NewPlayer("PlayerB", 60, 5, 8, player.WithClass(rogue))
+	o, err = Calculate(playerA, playerB, match.DefaultRules())
+	sampledWinA, sampledRounds = sample(playerA, playerB, match.DefaultRules())
+	if err != nil || math.Abs(sampledWinA-o.WinA) > 0.02 || math.Abs(sampledRounds-o.ExpectedRounds)/o.ExpectedRounds > 0.02 {
+		t.Errorf(redColor+"Expected odds %+v, %v to agree with sampled win rate %.3f and rounds %.2f"+resetColor, o, err, sampledWinA, sampledRounds)
+	} else {
+		fmt.Println(greenColor + "TestCalculate : Test4 : Passed" + resetColor)
+	}
+
+	//TEST 5: critical hits, fumbles and dodging
+	rules := match.DefaultRules()
+	rules.Criticals, rules.Fumbles, rules.Dodge = true, true, true
+	playerA = player.NewPlayer("PlayerA", 50, 5, 10, player.WithAgility(25))
+	playerB = player.NewPlayer("PlayerB", 100, 10, 5)
+	o, err = Calculate(playerA, playerB, rules)
+	sampledWinA, sampledRounds = sample(playerA, playerB, rules)
+	if err != nil || math.Abs(sampledWinA-o.WinA) > 0.02 || math.Abs(sampledRounds-o.ExpectedRounds)/o.ExpectedRounds > 0.02 {
+		t.Errorf(redColor+"Expected odds %+v, %v to agree with sampled win rate %.3f and rounds %.2f"+resetColor, o, err, sampledWinA, sampledRounds)
+	} else {
+		fmt.Println(greenColor + "TestCalculate : Test5 : Passed" + resetColor)
+	}
+}
+
+// sample conducts many seeded matches under the given rules.
+//
+// Returns:
+//   - float64: The fraction of matches won by Player A.
+//   - float64: The average number of rounds per match.
+func sample(playerA, playerB *player.Player, rules match.Rules) (float64, float64) {
+	const samples = 20000
+	winsA, rounds := 0, 0
+	for seed := int64(0); seed < samples; seed++ {
+		m, _ := match.NewMatch(playerA, playerB, match.WithSeed(seed), match.WithRules(rules))
+		roundResults, _ := match.ConductMatch(m)
+		if m.Outcome().Winner == playerA {
+			winsA++
+		}
+		rounds += len(roundResults)
+	}
+	return float64(winsA) / samples, float64(rounds) / samples
+}
+
+// TestMain runs the main testing suite.
+func TestMain(m *testing.M) {
+	fmt.Println("Testing odds package...")
+	Result := m.Run()
+	fmt.Println("Testing complete.")
+	os.Exit(Result)
+}
@@ -0,0 +1,190 @@
+package player
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Errors returned for character classes, usable with errors.Is.
+var (
+	ErrUnknownClass = errors.New("unknown character class")
+	ErrInvalidClass = errors.New("invalid character class")
+)
+
+// MaxDefenceRolls is the largest number of defence dice a class may roll.
+const MaxDefenceRolls = 5
+
+// Class is a character class, changing how a player rolls and how their attributes weigh in the
+// damage formula. The zero Class is no class at all: the player fights exactly by the rules.
+//
+// Classes are data: the built-in Warrior, Mage and Rogue are defined in classes.json, and more can
+// be added from a file with LoadClasses.
+type Class struct {
+	Name         string `json:"name"`                   // Name identifies the class.
+	Description  string `json:"description,omitempty"`  // Description explains the class to the user picking it.
+	AttackDice   int    `json:"attackDice,omitempty"`   // AttackDice is the number of sides of the class's attack die; 0 rolls the dice of the rules.
+	DefenceDice  int    `json:"defenceDice,omitempty"`  // DefenceDice is the number of sides of the class's defence die; 0 rolls the dice of the rules.
+	DefenceRolls int    `json:"defenceRolls,omitempty"` // DefenceRolls is the number of defence dice rolled, of which the highest counts; 0 rolls one.
+	Power        int    `json:"power,omitempty"`        // Power is the percentage added to the weight of the player's attack in the damage formula.
+	Armour       int    `json:"armour,omitempty"`       // Armour is the percentage added to the weight of the player's strength in the damage formula.
+}
+
+//go:embed classes.json
+var builtinClasses []byte
+
+// classes holds the known classes: the built-in ones, followed by any loaded with LoadClasses.
+var classes = mustDecodeClasses(builtinClasses)
+
+// Classes returns the known character classes, the built-in ones first.
+//
+// Returns:
+//   - []Class: A copy of the known classes.
+func Classes() []Class {
+	return append([]Class(nil), classes...)
+}
+
+// FindClass returns the known character class with the given name, ignoring case.
+//
+// Parameters:
+//   - name: The name of the class.
+//
+// Returns:
+//   - Class: The class.
+//   - error: An error wrapping ErrUnknownClass, if no known class has that name.
+//
+// Example:
+//
+//	warrior, err := FindClass("warrior")
+//	hero := NewPlayer("Hero", 100, 10, 5, WithClass(warrior))
+func FindClass(name string) (Class, error) {
+	name = strings.TrimSpace(name)
+	for _, class := range classes {
+		if strings.EqualFold(class.Name, name) {
+			return class, nil
+		}
+	}
+	return Class{}, fmt.Errorf("%w %q", ErrUnknownClass, name)
+}
+
+// LoadClasses reads character classes from a JSON file holding an array of classes, such as:
+//
+//	[{"name": "Paladin", "description": "Sturdy and steady.", "defenceDice": 8, "armour": 10}]
+//
+// The classes are validated and become known alongside the built-in ones; a class with the name
+// of a known class replaces it. LoadClasses is meant to be called once at startup, before any
+// player is created or decoded.
+//
+// Parameters:
+//   - path: The path of the classes file.
+//
+// Returns:
+//   - error: An error wrapping ErrInvalidClass, if the file cannot be read or holds an invalid class.
+func LoadClasses(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	loaded, err := decodeClasses(data)
+	if err != nil {
+		return err
+	}
+
+next:
+	for _, class := range loaded {
+		for i := range classes {
+			if strings.EqualFold(classes[i].Name, class.Name) {
+				classes[i] = class
+				continue next
+			}
+		}
+		classes = append(classes, class)
+	}
+	return nil
+}
+
+// Validate checks that a class can be played: it must have a name, dice of at least two sides,
+// between 1 and MaxDefenceRolls defence dice, and must not take away all of an attribute's weight.
+//
+// Returns:
+//   - error: An error wrapping ErrInvalidClass describing the first invalid setting, if any.
+func (c Class) Validate() error {
+	switch {
+	case strings.TrimSpace(c.Name) == "":
+		return fmt.Errorf("%w: a class must have a name", ErrInvalidClass)
+	case c.AttackDice == 1 || c.AttackDice < 0:
+		return fmt.Errorf("%w: %s: attack dice must have at least 2 sides, got %d", ErrInvalidClass, c.Name, c.AttackDice)
+	case c.DefenceDice == 1 || c.DefenceDice < 0:
+		return fmt.Errorf("%w: %s: defence dice must have at least 2 sides, got %d", ErrInvalidClass, c.Name, c.DefenceDice)
+	case c.DefenceRolls < 0 || c.DefenceRolls > MaxDefenceRolls:
+		return fmt.Errorf("%w: %s: defence rolls must be between 1 and %d, got %d", ErrInvalidClass, c.Name, MaxDefenceRolls, c.DefenceRolls)
+	case c.Power <= -100:
+		return fmt.Errorf("%w: %s: power must be greater than -100, got %d", ErrInvalidClass, c.Name, c.Power)
+	case c.Armour < -100:
+		return fmt.Errorf("%w: %s: armour must not be below -100, got %d", ErrInvalidClass, c.Name, c.Armour)
+	}
+	return nil
+}
+
+// WithClass makes a player created by NewPlayer a member of the given class.
+//
+// Parameters:
+//   - class: The class of the player.
+//
+// Returns:
+//   - Option: An option to pass to NewPlayer.
+func WithClass(class Class) Option {
+	return func(p *Player) {
+		p.class = class
+	}
+}
+
+// GetPlayerClass returns the class of a player, the zero Class for a player without one.
+//
+// Parameters:
+//   - p: A pointer to the Player.
+//
+// Returns:
+//   - Class: The class of the player.
+func GetPlayerClass(p *Player) Class {
+	return p.class
+}
+
+// decodeClasses decodes and validates a JSON array of classes.
+//
+// Parameters:
+//   - data: The JSON array.
+//
+// Returns:
+//   - []Class: The decoded classes.
+//   - error: An error wrapping ErrInvalidClass, if the JSON is malformed or a class is invalid.
+func decodeClasses(data []byte) ([]Class, error) {
+	var decoded []Class
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidClass, err)
+	}
+	for _, class := range decoded {
+		if err := class.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return decoded, nil
+}
+
+// mustDecodeClasses decodes the built-in classes, which are known to be valid.
+//
+// Parameters:
+//   - data: The JSON array of the built-in classes.
+//
+// Returns:
+//   - []Class: The built-in classes.
+func mustDecodeClasses(data []byte) []Class {
+	decoded, err := decodeClasses(data)
+	if err != nil {
+		panic(err)
+	}
+	return decoded
+}
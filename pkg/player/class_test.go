@@ -0,0 +1,82 @@
+package player
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestClasses tests the catalogue of character classes and players belonging to them.
+//
+// Test scenarios:
+//  1. The built-in Warrior, Mage and Rogue are known, can be found ignoring case, and an unknown
+//     class fails with ErrUnknownClass.
+//  2. A player with a class survives a round trip through JSON and the text format, and an
+//     unknown class or setting is rejected.
+//  3. Loading a classes file adds a new class and replaces a built-in one, and a file holding an
+//     invalid class fails with ErrInvalidClass without changing the known classes.
+//  4. A player with an invalid class fails validation with ErrInvalidClass.
+func TestClasses(t *testing.T) {
+	defer func(saved []Class) { classes = saved }(Classes())
+
+	//TEST 1: built-in classes
+	builtin := Classes()
+	rogue, err := FindClass(" rogue ")
+	_, errUnknown := FindClass("Bard")
+	if len(builtin) != 3 || builtin[0].Name != "Warrior" || builtin[1].Name != "Mage" || err != nil ||
+		rogue.DefenceRolls != 2 || !errors.Is(errUnknown, ErrUnknownClass) {
+		t.Errorf(redColor+"Expected Warrior, Mage and Rogue, got %v, %v, %v, %v"+resetColor, builtin, rogue, err, errUnknown)
+	} else {
+		fmt.Println(greenColor + "TestClasses : Test1 : Passed" + resetColor)
+	}
+
+	//TEST 2: round trips with a class
+	hero := NewPlayer("Hero", 100, 10, 5, WithClass(rogue))
+	data, _ := json.Marshal(hero)
+	fromJSON := &Player{}
+	errJSON := json.Unmarshal(data, fromJSON)
+	text, _ := hero.MarshalText()
+	fromText, errText := ParseText(string(text))
+	_, errClass := ParseText("Hero:100:10:5:class=Bard")
+	_, errSetting := ParseText("Hero:100:10:5:mood=grumpy")
+	if errJSON != nil || errText != nil || string(data) != `{"name":"Hero","health":100,"strength":10,"attack":5,"class":"Rogue"}` ||
+		string(text) != "Hero:100:10:5:class=Rogue" || *fromJSON != *hero || *fromText != *hero ||
+		!errors.Is(errClass, ErrUnknownClass) || !errors.Is(errSetting, ErrInvalidFormat) {
+		t.Errorf(redColor+"Expected a Rogue to survive both round trips, got %s, %s, %v, %v, %v, %v"+resetColor, data, text, errJSON, errText, errClass, errSetting)
+	} else {
+		fmt.Println(greenColor + "TestClasses : Test2 : Passed" + resetColor)
+	}
+
+	//TEST 3: loading classes
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "classes.json")
+	invalid := filepath.Join(dir, "invalid.json")
+	if err := os.WriteFile(valid, []byte(`[{"name":"Paladin","defenceDice":8},{"name":"mage","attackDice":12}]`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(invalid, []byte(`[{"name":"Juggler","attackDice":1}]`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	errValid := LoadClasses(valid)
+	errInvalid := LoadClasses(invalid)
+	paladin, errPaladin := FindClass("Paladin")
+	mage, _ := FindClass("Mage")
+	_, errJuggler := FindClass("Juggler")
+	if errValid != nil || errPaladin != nil || paladin.DefenceDice != 8 || mage.AttackDice != 12 || len(Classes()) != 4 ||
+		!errors.Is(errInvalid, ErrInvalidClass) || !errors.Is(errJuggler, ErrUnknownClass) {
+		t.Errorf(redColor+"Expected Paladin added and Mage replaced, got %v, %v, %v, %v"+resetColor, Classes(), errValid, errInvalid, errJuggler)
+	} else {
+		fmt.Println(greenColor + "TestClasses : Test3 : Passed" + resetColor)
+	}
+
+	//TEST 4: invalid class
+	err = Validate(NewPlayer("Hero", 100, 10, 5, WithClass(Class{Name: "Glass cannon", Power: -100})))
+	if !errors.Is(err, ErrInvalidClass) {
+		t.Errorf(redColor+"Expected ErrInvalidClass, got %v"+resetColor, err)
+	} else {
+		fmt.Println(greenColor + "TestClasses : Test4 : Passed" + resetColor)
+	}
+}
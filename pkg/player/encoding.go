@@ -0,0 +1,283 @@
+package player
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidFormat is returned when a player definition cannot be parsed.
+var ErrInvalidFormat = errors.New("invalid player definition")
+
+// playerJSON is the JSON form of a player.
+type playerJSON struct {
+	Name     string `json:"name"`              // Name is the name of the player.
+	Health   int    `json:"health"`            // Health is the health attribute of the player.
+	Strength int    `json:"strength"`          // Strength is the strength attribute of the player.
+	Attack   int    `json:"attack"`            // Attack is the attack attribute of the player.
+	Agility  int    `json:"agility,omitempty"` // Agility is the agility attribute of the player, if they have any.
+	Class    string `json:"class,omitempty"`   // Class is the name of the player's class, if they have one.
+	Mana     int    `json:"mana,omitempty"`    // Mana is the mana pool of the player, if they have one.
+}
+
+// MarshalJSON encodes a player as a JSON object with its name, health, strength and attack, and
+// its agility, the name of its class and its mana if it has them.
+//
+// Returns:
+//   - []byte: The JSON encoding of the player.
+//   - error: An error, if the player cannot be encoded.
+//
+// Example:
+//
+//	data, _ := json.Marshal(NewPlayer("Hero", 100, 10, 5))
+//	fmt.Println(string(data)) // {"name":"Hero","health":100,"strength":10,"attack":5}
+func (p *Player) MarshalJSON() ([]byte, error) {
+	return json.Marshal(playerJSON{p.name, p.health, p.strength, p.attack, p.agility, p.class.Name, p.mana})
+}
+
+// UnmarshalJSON decodes a player from a JSON object and validates it, so a shared character
+// definition can never load a player that cannot enter a match.
+//
+// Parameters:
+//   - data: The JSON encoding of the player.
+//
+// Returns:
+//   - error: An error wrapping ErrInvalidFormat if the JSON is malformed, ErrUnknownClass if the
+//     class is not known, or one of the Validate errors if the player is invalid. The player is
+//     left unchanged on error.
+func (p *Player) UnmarshalJSON(data []byte) error {
+	var decoded playerJSON
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidFormat, err)
+	}
+	opts, err := classOption(decoded.Class)
+	if err != nil {
+		return err
+	}
+	opts = append(opts, WithAgility(decoded.Agility), WithMana(decoded.Mana))
+	return p.set(NewPlayer(decoded.Name, decoded.Health, decoded.Strength, decoded.Attack, opts...))
+}
+
+// MarshalText encodes a player in the text format "Name:Health:Strength:Attack", followed by
+// ":agility=Agility", ":class=Class" and ":mana=Mana" if the player has them.
+//
+// Returns:
+//   - []byte: The text encoding of the player.
+//   - error: An error, if the player cannot be encoded.
+//
+// Example:
+//
+//	text, _ := NewPlayer("Hero", 100, 10, 5).MarshalText()
+//	fmt.Println(string(text)) // Hero:100:10:5
+func (p *Player) MarshalText() ([]byte, error) {
+	text := fmt.Sprintf("%s:%d:%d:%d", p.name, p.health, p.strength, p.attack)
+	if p.agility != 0 {
+		text += fmt.Sprintf(":agility=%d", p.agility)
+	}
+	if p.class.Name != "" {
+		text += ":class=" + p.class.Name
+	}
+	if p.mana != 0 {
+		text += fmt.Sprintf(":mana=%d", p.mana)
+	}
+	return []byte(text), nil
+}
+
+// UnmarshalText decodes a player from the text format "Name:Health:Strength:Attack" and
+// validates it. The attributes may be followed by settings written as "key=value": "agility"
+// sets the player's agility, "class" names their class and "mana" sets their mana pool, e.g.
+// "Hero:100:10:5:agility=20:class=Rogue". The three fields before the settings are the
+// attributes, so the name may itself contain colons.
+//
+// Parameters:
+//   - text: The text encoding of the player.
+//
+// Returns:
+//   - error: An error wrapping ErrInvalidFormat if the text is malformed, ErrUnknownClass if the
+//     class is not known, or one of the Validate errors if the player is invalid. The player is
+//     left unchanged on error.
+func (p *Player) UnmarshalText(text []byte) error {
+	fields := strings.Split(string(text), ":")
+
+	var opts []Option
+	for len(fields) > 0 && strings.Contains(fields[len(fields)-1], "=") {
+		key, value, _ := strings.Cut(fields[len(fields)-1], "=")
+		fields = fields[:len(fields)-1]
+		switch strings.TrimSpace(key) {
+		case "class":
+			classOpts, err := classOption(value)
+			if err != nil {
+				return err
+			}
+			opts = append(opts, classOpts...)
+		case "agility":
+			agility, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil {
+				return fmt.Errorf("%w: %q has a non-numeric agility %q", ErrInvalidFormat, text, value)
+			}
+			opts = append(opts, WithAgility(agility))
+		case "mana":
+			mana, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil {
+				return fmt.Errorf("%w: %q has a non-numeric mana %q", ErrInvalidFormat, text, value)
+			}
+			opts = append(opts, WithMana(mana))
+		default:
+			return fmt.Errorf("%w: %q has an unknown setting %q", ErrInvalidFormat, text, key)
+		}
+	}
+	if len(fields) < 4 {
+		return fmt.Errorf("%w: %q must be given as Name:Health:Strength:Attack", ErrInvalidFormat, text)
+	}
+
+	n := len(fields) - 3
+	attributes := make([]int, 3)
+	for i, field := range fields[n:] {
+		value, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil {
+			return fmt.Errorf("%w: %q has a non-numeric attribute %q", ErrInvalidFormat, text, field)
+		}
+		attributes[i] = value
+	}
+	name := strings.TrimSpace(strings.Join(fields[:n], ":"))
+	return p.set(NewPlayer(name, attributes[0], attributes[1], attributes[2], opts...))
+}
+
+// ParseText decodes a player from the text format "Name:Health:Strength:Attack" and validates it.
+//
+// Parameters:
+//   - text: The text encoding of the player.
+//
+// Returns:
+//   - *Player: A pointer to the decoded player.
+//   - error: An error wrapping ErrInvalidFormat or one of the Validate errors.
+//
+// Example:
+//
+//	hero, err := ParseText("Hero:100:10:5")
+func ParseText(text string) (*Player, error) {
+	p := &Player{}
+	if err := p.UnmarshalText([]byte(text)); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Decode reads the players defined in a character file. The file holds either JSON, as a single
+// player object or an array of them, or text with one "Name:Health:Strength:Attack" player per
+// line; blank lines and lines starting with "#" are skipped. Every player is validated.
+//
+// Parameters:
+//   - data: The contents of the character file.
+//
+// Returns:
+//   - []*Player: The decoded players, in file order.
+//   - error: An error wrapping ErrInvalidFormat or one of the Validate errors, naming the line of
+//     a text file it occurred on. A JSON array holding null instead of a player is ErrInvalidFormat.
+func Decode(data []byte) ([]*Player, error) {
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("[")):
+		var players []*Player
+		if err := json.Unmarshal(trimmed, &players); err != nil {
+			return nil, jsonError(err)
+		}
+		for i, p := range players {
+			if p == nil {
+				return nil, fmt.Errorf("%w: player %d of the array is null", ErrInvalidFormat, i+1)
+			}
+		}
+		return players, nil
+	case bytes.HasPrefix(trimmed, []byte("{")):
+		p := &Player{}
+		if err := json.Unmarshal(trimmed, p); err != nil {
+			return nil, jsonError(err)
+		}
+		return []*Player{p}, nil
+	}
+
+	var players []*Player
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		p, err := ParseText(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		players = append(players, p)
+	}
+	return players, nil
+}
+
+// Load reads the players defined in a character file; see Decode for the accepted formats.
+//
+// Parameters:
+//   - path: The path of the character file.
+//
+// Returns:
+//   - []*Player: The decoded players, in file order.
+//   - error: An error, if the file cannot be read or holds an invalid player.
+func Load(path string) ([]*Player, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Decode(data)
+}
+
+// set validates a decoded player and copies it into p.
+//
+// Parameters:
+//   - decoded: A pointer to the decoded player.
+//
+// Returns:
+//   - error: One of the Validate errors, if the decoded player is invalid.
+func (p *Player) set(decoded *Player) error {
+	if err := Validate(decoded); err != nil {
+		return err
+	}
+	*p = *decoded
+	return nil
+}
+
+// classOption looks up a class by name for a decoded player.
+//
+// Parameters:
+//   - name: The name of the class, or an empty string for none.
+//
+// Returns:
+//   - []Option: The options giving the player the class, none for an empty name.
+//   - error: An error wrapping ErrUnknownClass, if the class is not known.
+func classOption(name string) ([]Option, error) {
+	if strings.TrimSpace(name) == "" {
+		return nil, nil
+	}
+	class, err := FindClass(name)
+	if err != nil {
+		return nil, err
+	}
+	return []Option{WithClass(class)}, nil
+}
+
+// jsonError keeps the errors returned by UnmarshalJSON and turns any other JSON syntax or type
+// error into one wrapping ErrInvalidFormat.
+//
+// Parameters:
+//   - err: The error returned by json.Unmarshal.
+//
+// Returns:
+//   - error: The error to report.
+func jsonError(err error) error {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) {
+		return fmt.Errorf("%w: %v", ErrInvalidFormat, err)
+	}
+	return err
+}
@@ -0,0 +1,124 @@
+package player
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// TestJSON tests encoding players to and decoding them from JSON.
+//
+// Test scenarios:
+//  1. A player survives a round trip through JSON with all attributes intact.
+//  2. Decoding an invalid player fails with its Validate error and leaves the target unchanged.
+//  3. Decoding malformed JSON fails with ErrInvalidFormat.
+func TestJSON(t *testing.T) {
+	//TEST 1: round trip
+	data, err := json.Marshal(NewPlayer("Hero", 100, 10, 5))
+	decoded := &Player{}
+	if err == nil {
+		err = json.Unmarshal(data, decoded)
+	}
+	if err != nil || string(data) != `{"name":"Hero","health":100,"strength":10,"attack":5}` || *decoded != *NewPlayer("Hero", 100, 10, 5) {
+		t.Errorf(redColor+"Expected Hero to survive a round trip, got %s, %v, %v"+resetColor, data, decoded, err)
+	} else {
+		fmt.Println(greenColor + "TestJSON : Test1 : Passed" + resetColor)
+	}
+
+	//TEST 2: invalid player
+	err = json.Unmarshal([]byte(`{"name":"Ghost","health":0,"strength":10,"attack":5}`), decoded)
+	if !errors.Is(err, ErrNonPositiveHealth) || *decoded != *NewPlayer("Hero", 100, 10, 5) {
+		t.Errorf(redColor+"Expected ErrNonPositiveHealth and an unchanged player, got %v, %v"+resetColor, err, decoded)
+	} else {
+		fmt.Println(greenColor + "TestJSON : Test2 : Passed" + resetColor)
+	}
+
+	//TEST 3: malformed JSON
+	if _, err := Decode([]byte(`{"name":"Hero","health":"lots"}`)); !errors.Is(err, ErrInvalidFormat) {
+		t.Errorf(redColor+"Expected ErrInvalidFormat, got %v"+resetColor, err)
+	} else {
+		fmt.Println(greenColor + "TestJSON : Test3 : Passed" + resetColor)
+	}
+}
+
+// TestText tests the "Name:Health:Strength:Attack" text format and decoding character files.
+//
+// Test scenarios:
+//  1. A player survives a round trip through the text format, even with a colon in the name.
+//  2. Text with too few fields or a non-numeric attribute fails with ErrInvalidFormat.
+//  3. A text file with comments and blank lines decodes to its players, and an invalid player
+//     is reported with its line number.
+//  4. A JSON array decodes to its players, and an array holding null fails with ErrInvalidFormat.
+//  5. A player with agility survives a round trip through the text format and JSON, and a
+//     non-numeric agility fails with ErrInvalidFormat.
+//  6. A player with mana survives a round trip through the text format and JSON, and a
+//     non-numeric mana fails with ErrInvalidFormat.
+func TestText(t *testing.T) {
+	//TEST 1: round trip
+	text, _ := NewPlayer("Sir: Lancelot", 100, 10, 5).MarshalText()
+	decoded, err := ParseText(string(text))
+	if err != nil || string(text) != "Sir: Lancelot:100:10:5" || *decoded != *NewPlayer("Sir: Lancelot", 100, 10, 5) {
+		t.Errorf(redColor+"Expected a round trip through %s, got %v, %v"+resetColor, text, decoded, err)
+	} else {
+		fmt.Println(greenColor + "TestText : Test1 : Passed" + resetColor)
+	}
+
+	//TEST 2: malformed text
+	_, errFields := ParseText("Hero:100:10")
+	_, errNumber := ParseText("Hero:100:ten:5")
+	if !errors.Is(errFields, ErrInvalidFormat) || !errors.Is(errNumber, ErrInvalidFormat) {
+		t.Errorf(redColor+"Expected ErrInvalidFormat, got %v, %v"+resetColor, errFields, errNumber)
+	} else {
+		fmt.Println(greenColor + "TestText : Test2 : Passed" + resetColor)
+	}
+
+	//TEST 3: text file
+	players, err := Decode([]byte("# The usual suspects\nHero:100:10:5\n\n  Villain:50:5:2  \n"))
+	_, errLine := Decode([]byte("Hero:100:10:5\nVillain:50:5:0\n"))
+	if err != nil || len(players) != 2 || *players[1] != *NewPlayer("Villain", 50, 5, 2) ||
+		!errors.Is(errLine, ErrNonPositiveAttack) || errLine.Error() != "line 2: Villain: player attack must be greater than 0" {
+		t.Errorf(redColor+"Expected Hero and Villain, then an error on line 2, got %v, %v, %v"+resetColor, players, err, errLine)
+	} else {
+		fmt.Println(greenColor + "TestText : Test3 : Passed" + resetColor)
+	}
+
+	//TEST 4: JSON array
+	players, err = Decode([]byte(`[{"name":"Hero","health":100,"strength":10,"attack":5},{"name":"Villain","health":50,"strength":5,"attack":2}]`))
+	nulls, errNull := Decode([]byte(`[null]`))
+	if err != nil || len(players) != 2 || *players[0] != *NewPlayer("Hero", 100, 10, 5) || nulls != nil || !errors.Is(errNull, ErrInvalidFormat) {
+		t.Errorf(redColor+"Expected Hero and Villain, then ErrInvalidFormat, got %v, %v, %v"+resetColor, players, err, errNull)
+	} else {
+		fmt.Println(greenColor + "TestText : Test4 : Passed" + resetColor)
+	}
+
+	//TEST 5: agility
+	quick := NewPlayer("Quick", 60, 5, 8, WithAgility(30))
+	text, _ = quick.MarshalText()
+	decoded, err = ParseText(string(text))
+	data, _ := json.Marshal(quick)
+	fromJSON := &Player{}
+	errJSON := json.Unmarshal(data, fromJSON)
+	_, errAgility := ParseText("Quick:60:5:8:agility=lots")
+	if err != nil || errJSON != nil || string(text) != "Quick:60:5:8:agility=30" || *decoded != *quick || *fromJSON != *quick ||
+		GetPlayerAgility(decoded) != 30 || !errors.Is(errAgility, ErrInvalidFormat) {
+		t.Errorf(redColor+"Expected Quick to keep agility 30, got %s, %v, %v, %v, %v"+resetColor, text, decoded, err, errJSON, errAgility)
+	} else {
+		fmt.Println(greenColor + "TestText : Test5 : Passed" + resetColor)
+	}
+
+	//TEST 6: mana
+	wizard := NewPlayer("Wizard", 60, 5, 8, WithMana(40))
+	text, _ = wizard.MarshalText()
+	decoded, err = ParseText(string(text))
+	data, _ = json.Marshal(wizard)
+	fromJSON = &Player{}
+	errJSON = json.Unmarshal(data, fromJSON)
+	_, errMana := ParseText("Wizard:60:5:8:mana=plenty")
+	if err != nil || errJSON != nil || string(text) != "Wizard:60:5:8:mana=40" || string(data) != `{"name":"Wizard","health":60,"strength":5,"attack":8,"mana":40}` ||
+		*decoded != *wizard || *fromJSON != *wizard || GetPlayerMana(decoded) != 40 || !errors.Is(errMana, ErrInvalidFormat) {
+		t.Errorf(redColor+"Expected Wizard to keep mana 40, got %s, %s, %v, %v, %v, %v"+resetColor, text, data, decoded, err, errJSON, errMana)
+	} else {
+		fmt.Println(greenColor + "TestText : Test6 : Passed" + resetColor)
+	}
+}
@@ -1,13 +1,38 @@
 package player
 
+import (
+	"errors"
+	"fmt"
+)
+
+// Errors returned by Validate, usable with errors.Is.
+var (
+	ErrEmptyName           = errors.New("player name must not be empty")
+	ErrNonPositiveHealth   = errors.New("player health must be greater than 0")
+	ErrNonPositiveStrength = errors.New("player strength must be greater than 0")
+	ErrNonPositiveAttack   = errors.New("player attack must be greater than 0")
+	ErrInvalidAgility      = fmt.Errorf("player agility must be between 0 and %d", MaxAgility)
+	ErrNegativeMana        = errors.New("player mana must not be negative")
+)
+
+// MaxAgility is the highest agility a player may have. Agility is a percentage chance of dodging
+// an attack, so a limit keeps every player possible to hit.
+const MaxAgility = 75
+
 // Player represents a player in the game, encapsulating their name, health, strength, and attack attributes.
 type Player struct {
 	name     string // The name of the player.
 	health   int    // The health attribute of the player.
 	strength int    // The strength attribute of the player.
 	attack   int    // The attack attribute of the player.
+	agility  int    // The agility attribute of the player: their chance, in percent, of dodging an attack.
+	class    Class  // The character class of the player; the zero Class for none.
+	mana     int    // The mana pool of the player: the most mana they can hold to cast spells.
 }
 
+// Option configures optional settings of a Player when it is created with NewPlayer.
+type Option func(*Player)
+
 // NewPlayer creates and initializes a new Player instance with the specified attributes.
 //
 // Parameters:
@@ -15,6 +40,7 @@ type Player struct {
 //   - health: The health attribute of the player.
 //   - strength: The strength attribute of the player.
 //   - attack: The attack attribute of the player.
+//   - opts: Optional settings such as WithAgility, WithClass or WithMana.
 //
 // Returns:
 //   - *Player: A pointer to the newly created Player instance.
@@ -26,8 +52,12 @@ type Player struct {
 //
 // Note: This example assumes direct access to the Player struct fields.
 // If the fields are unexported (as in this case), accessor methods should be used.
-func NewPlayer(name string, health, strength, attack int) *Player {
-	return &Player{name, health, strength, attack}
+func NewPlayer(name string, health, strength, attack int, opts ...Option) *Player {
+	p := &Player{name: name, health: health, strength: strength, attack: attack}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
 }
 
 // GetPlayerBaseAttributes returns the fundamental attributes of a player, including their name, health, strength, and attack.
@@ -50,3 +80,92 @@ func NewPlayer(name string, health, strength, attack int) *Player {
 func GetPlayerBaseAttributes(p *Player) (string, int, int, int) {
 	return p.name, p.health, p.strength, p.attack
 }
+
+// Validate checks that a player's attributes allow them to take part in a match: the player
+// must have a name, positive health, strength and attack, an agility between 0 and MaxAgility,
+// no negative mana, and a valid class if they have one.
+//
+// Parameters:
+//   - p: A pointer to the Player to validate.
+//
+// Returns:
+//   - error: An error wrapping ErrEmptyName, ErrNonPositiveHealth, ErrNonPositiveStrength,
+//     ErrNonPositiveAttack, ErrInvalidAgility or ErrNegativeMana for the first invalid attribute, ErrInvalidClass
+//     for an invalid class, or nil if the player is valid.
+//
+// Example:
+//
+//	if err := Validate(player); errors.Is(err, ErrNonPositiveHealth) {
+//		fmt.Println("Give the player some health first")
+//	}
+func Validate(p *Player) error {
+	switch {
+	case p.name == "":
+		return ErrEmptyName
+	case p.health <= 0:
+		return fmt.Errorf("%s: %w", p.name, ErrNonPositiveHealth)
+	case p.strength <= 0:
+		return fmt.Errorf("%s: %w", p.name, ErrNonPositiveStrength)
+	case p.attack <= 0:
+		return fmt.Errorf("%s: %w", p.name, ErrNonPositiveAttack)
+	case p.agility < 0 || p.agility > MaxAgility:
+		return fmt.Errorf("%s: %w", p.name, ErrInvalidAgility)
+	case p.mana < 0:
+		return fmt.Errorf("%s: %w", p.name, ErrNegativeMana)
+	case p.class != Class{}:
+		if err := p.class.Validate(); err != nil {
+			return fmt.Errorf("%s: %w", p.name, err)
+		}
+	}
+	return nil
+}
+
+// WithAgility gives a player created by NewPlayer the given agility, their chance in percent of
+// dodging an attack when the match rules allow dodging. Players have no agility by default.
+//
+// Parameters:
+//   - agility: The agility attribute of the player, between 0 and MaxAgility.
+//
+// Returns:
+//   - Option: An option to pass to NewPlayer.
+func WithAgility(agility int) Option {
+	return func(p *Player) {
+		p.agility = agility
+	}
+}
+
+// GetPlayerAgility returns the agility attribute of a player.
+//
+// Parameters:
+//   - p: A pointer to the Player.
+//
+// Returns:
+//   - int: The agility attribute of the player.
+func GetPlayerAgility(p *Player) int {
+	return p.agility
+}
+
+// WithMana gives a player created by NewPlayer a mana pool of the given size, which they spend to
+// cast spells during a match. Players have no mana by default, and so only ever attack.
+//
+// Parameters:
+//   - mana: The mana pool of the player, not negative.
+//
+// Returns:
+//   - Option: An option to pass to NewPlayer.
+func WithMana(mana int) Option {
+	return func(p *Player) {
+		p.mana = mana
+	}
+}
+
+// GetPlayerMana returns the size of a player's mana pool.
+//
+// Parameters:
+//   - p: A pointer to the Player.
+//
+// Returns:
+//   - int: The mana pool of the player.
+func GetPlayerMana(p *Player) int {
+	return p.mana
+}
@@ -1,6 +1,7 @@
 package player
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"testing"
@@ -83,6 +84,53 @@ func TestNewPlayer(t *testing.T) {
 	}
 }
 
+// TestValidate tests the Validate function.
+//
+// Test scenarios:
+//  1. A player with a name and positive attributes is valid.
+//  2. Each missing or non-positive attribute is reported with its own error.
+//  3. An agility out of range is reported with the limit set by MaxAgility.
+func TestValidate(t *testing.T) {
+	//TEST 1: valid player
+	if err := Validate(NewPlayer("Ironman", 100, 10, 5)); err != nil {
+		t.Errorf(redColor+"Expected Ironman to be valid, got %v"+resetColor, err)
+	} else {
+		fmt.Println(greenColor + "TestValidate: Test1 : Passed" + resetColor)
+	}
+
+	//TEST 2: invalid attributes
+	invalid := []struct {
+		player   *Player
+		expected error
+	}{
+		{NewPlayer("", 100, 10, 5), ErrEmptyName},
+		{NewPlayer("Ironman", 0, 10, 5), ErrNonPositiveHealth},
+		{NewPlayer("Ironman", 100, -1, 5), ErrNonPositiveStrength},
+		{NewPlayer("Ironman", 100, 10, 0), ErrNonPositiveAttack},
+		{NewPlayer("Ironman", 100, 10, 5, WithAgility(MaxAgility+1)), ErrInvalidAgility},
+		{NewPlayer("Ironman", 100, 10, 5, WithAgility(-1)), ErrInvalidAgility},
+		{NewPlayer("Ironman", 100, 10, 5, WithMana(-1)), ErrNegativeMana},
+	}
+	passed := true
+	for _, tc := range invalid {
+		if err := Validate(tc.player); !errors.Is(err, tc.expected) {
+			t.Errorf(redColor+"Expected %v, got %v"+resetColor, tc.expected, err)
+			passed = false
+		}
+	}
+	if passed {
+		fmt.Println(greenColor + "TestValidate: Test2 : Passed" + resetColor)
+	}
+
+	//TEST 3: agility limit in the message
+	err := Validate(NewPlayer("Ironman", 100, 10, 5, WithAgility(MaxAgility+1)))
+	if expected := fmt.Sprintf("Ironman: player agility must be between 0 and %d", MaxAgility); err == nil || err.Error() != expected {
+		t.Errorf(redColor+"Expected %q, got %v"+resetColor, expected, err)
+	} else {
+		fmt.Println(greenColor + "TestValidate: Test3 : Passed" + resetColor)
+	}
+}
+
 // TestMain runs the main testing suite.
 func TestMain(m *testing.M) {
 	fmt.Println("Testing player package...")
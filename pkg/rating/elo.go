@@ -0,0 +1,49 @@
+package rating
+
+import "math"
+
+// Elo is the Elo rating system. Its ratings carry no deviation.
+type Elo struct {
+	K             float64 // K is the largest change of a rating in one match.
+	InitialRating float64 // InitialRating is the rating of an unrated player.
+}
+
+// NewElo creates the Elo rating system with the customary K-factor of 32 and initial rating of 1500.
+//
+// Returns:
+//   - *Elo: A pointer to the rating system.
+func NewElo() *Elo {
+	return &Elo{K: 32, InitialRating: 1500}
+}
+
+// Name returns "elo".
+func (e *Elo) Name() string {
+	return "elo"
+}
+
+// Initial returns the initial rating of an unrated player.
+func (e *Elo) Initial() Rating {
+	return Rating{System: e.Name(), Rating: e.InitialRating}
+}
+
+// Rate moves both ratings by K times the difference between Player A's score and the score the
+// ratings predicted, so the changes of both players add up to zero.
+//
+// Parameters:
+//   - a: The rating of Player A before the match.
+//   - b: The rating of Player B before the match.
+//   - scoreA: The score of Player A: Win, Draw or Loss.
+//
+// Returns:
+//   - Rating: The rating of Player A after the match.
+//   - Rating: The rating of Player B after the match.
+func (e *Elo) Rate(a, b Rating, scoreA float64) (Rating, Rating) {
+	expectedA := 1 / (1 + math.Pow(10, (b.Rating-a.Rating)/400))
+	change := e.K * (scoreA - expectedA)
+
+	a.Rating += change
+	b.Rating -= change
+	a.Matches++
+	b.Matches++
+	return a, b
+}
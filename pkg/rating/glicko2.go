@@ -0,0 +1,142 @@
+package rating
+
+import "math"
+
+// glicko2Scale converts between the Glicko and Glicko-2 rating scales.
+const glicko2Scale = 173.7178
+
+// Glicko2 is Mark Glickman's Glicko-2 rating system, in which every match is its own rating period.
+// Ratings carry a deviation, which shrinks as a player plays and measures how reliable the rating is.
+type Glicko2 struct {
+	Tau               float64 // Tau constrains how fast the volatility changes; 0.3 to 1.2 is reasonable.
+	InitialRating     float64 // InitialRating is the rating of an unrated player.
+	InitialDeviation  float64 // InitialDeviation is the rating deviation of an unrated player.
+	InitialVolatility float64 // InitialVolatility is the volatility of an unrated player.
+}
+
+// NewGlicko2 creates the Glicko-2 rating system with the parameters recommended by Glickman:
+// tau 0.5, and an initial rating of 1500 with a deviation of 350 and a volatility of 0.06.
+//
+// Returns:
+//   - *Glicko2: A pointer to the rating system.
+func NewGlicko2() *Glicko2 {
+	return &Glicko2{Tau: 0.5, InitialRating: 1500, InitialDeviation: 350, InitialVolatility: 0.06}
+}
+
+// Name returns "glicko2".
+func (g *Glicko2) Name() string {
+	return "glicko2"
+}
+
+// Initial returns the initial rating of an unrated player.
+func (g *Glicko2) Initial() Rating {
+	return Rating{System: g.Name(), Rating: g.InitialRating, Deviation: g.InitialDeviation, Volatility: g.InitialVolatility}
+}
+
+// Rate updates both ratings from a rating period holding the single match between the players.
+//
+// Parameters:
+//   - a: The rating of Player A before the match.
+//   - b: The rating of Player B before the match.
+//   - scoreA: The score of Player A: Win, Draw or Loss.
+//
+// Returns:
+//   - Rating: The rating of Player A after the match.
+//   - Rating: The rating of Player B after the match.
+func (g *Glicko2) Rate(a, b Rating, scoreA float64) (Rating, Rating) {
+	return g.update(a, []Rating{b}, []float64{scoreA}), g.update(b, []Rating{a}, []float64{1 - scoreA})
+}
+
+// update applies the Glicko-2 algorithm to a rating over one rating period.
+//
+// Parameters:
+//   - r: The rating of the player before the period.
+//   - opponents: The ratings of the player's opponents before the period.
+//   - scores: The player's score against each opponent.
+//
+// Returns:
+//   - Rating: The rating of the player after the period.
+func (g *Glicko2) update(r Rating, opponents []Rating, scores []float64) Rating {
+	mu := (r.Rating - g.InitialRating) / glicko2Scale
+	phi := r.Deviation / glicko2Scale
+
+	// Estimated variance of the rating from the match results, and the estimated improvement.
+	var variance, improvement float64
+	for i, opponent := range opponents {
+		gPhi := g2(opponent.Deviation / glicko2Scale)
+		expected := 1 / (1 + math.Exp(-gPhi*(mu-(opponent.Rating-g.InitialRating)/glicko2Scale)))
+		variance += gPhi * gPhi * expected * (1 - expected)
+		improvement += gPhi * (scores[i] - expected)
+	}
+	variance = 1 / variance
+	delta := variance * improvement
+
+	sigma := g.volatility(phi, r.Volatility, variance, delta)
+	phiStar := math.Sqrt(phi*phi + sigma*sigma)
+	phi = 1 / math.Sqrt(1/(phiStar*phiStar)+1/variance)
+	mu += phi * phi * improvement
+
+	r.Rating = mu*glicko2Scale + g.InitialRating
+	r.Deviation = phi * glicko2Scale
+	r.Volatility = sigma
+	r.Matches += len(opponents)
+	return r
+}
+
+// volatility finds the new volatility of a player with the Illinois algorithm.
+//
+// Parameters:
+//   - phi: The rating deviation of the player on the Glicko-2 scale.
+//   - sigma: The volatility of the player before the period.
+//   - variance: The estimated variance of the rating from the match results.
+//   - delta: The estimated improvement of the rating.
+//
+// Returns:
+//   - float64: The volatility of the player after the period.
+func (g *Glicko2) volatility(phi, sigma, variance, delta float64) float64 {
+	const epsilon = 0.000001
+	a := math.Log(sigma * sigma)
+	tau2 := g.Tau * g.Tau
+	f := func(x float64) float64 {
+		ex := math.Exp(x)
+		d := phi*phi + variance + ex
+		return ex*(delta*delta-phi*phi-variance-ex)/(2*d*d) - (x-a)/tau2
+	}
+
+	lower := a
+	var upper float64
+	if delta*delta > phi*phi+variance {
+		upper = math.Log(delta*delta - phi*phi - variance)
+	} else {
+		k := 1.0
+		for f(a-k*g.Tau) < 0 {
+			k++
+		}
+		upper = a - k*g.Tau
+	}
+
+	fLower, fUpper := f(lower), f(upper)
+	for math.Abs(upper-lower) > epsilon {
+		c := lower + (lower-upper)*fLower/(fUpper-fLower)
+		fC := f(c)
+		if fC*fUpper <= 0 {
+			lower, fLower = upper, fUpper
+		} else {
+			fLower /= 2
+		}
+		upper, fUpper = c, fC
+	}
+	return math.Exp(lower / 2)
+}
+
+// g2 is the function g of the Glicko-2 algorithm, which weighs a match by the uncertainty of the
+// opponent's rating.
+//
+// Parameters:
+//   - phi: The rating deviation of the opponent on the Glicko-2 scale.
+//
+// Returns:
+//   - float64: The weight of the match.
+func g2(phi float64) float64 {
+	return 1 / math.Sqrt(1+3*phi*phi/(math.Pi*math.Pi))
+}
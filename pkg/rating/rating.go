@@ -0,0 +1,105 @@
+package rating
+
+import (
+	"errors"
+	"fmt"
+	"proj/pkg/match"
+	"proj/pkg/player"
+)
+
+// ErrUnknownSystem is returned when no rating system has the requested name.
+var ErrUnknownSystem = errors.New("unknown rating system")
+
+// Scores of a single match, from the point of view of one player.
+const (
+	Loss = 0.0 // Loss is the score of the losing player.
+	Draw = 0.5 // Draw is the score of both players in a draw.
+	Win  = 1.0 // Win is the score of the winning player.
+)
+
+// Rating is the skill rating of a player under one rating system.
+type Rating struct {
+	System     string  `json:"system"`               // System is the name of the rating system the rating belongs to.
+	Rating     float64 `json:"rating"`               // Rating is the estimated skill of the player.
+	Deviation  float64 `json:"deviation"`            // Deviation is the uncertainty of the rating; 0 for systems without one.
+	Volatility float64 `json:"volatility,omitempty"` // Volatility is the expected fluctuation of the rating, for Glicko-2.
+	Matches    int     `json:"matches"`              // Matches is the number of rated matches played.
+}
+
+// String formats a rating as its rounded value and, if it has one, its deviation.
+//
+// Returns:
+//   - string: The formatted rating, e.g. "1500 ± 350".
+func (r Rating) String() string {
+	if r.Deviation == 0 {
+		return fmt.Sprintf("%.0f", r.Rating)
+	}
+	return fmt.Sprintf("%.0f ± %.0f", r.Rating, r.Deviation)
+}
+
+// Rater is a rating system, updating the ratings of two players after a match between them.
+type Rater interface {
+	// Name returns the name the rating system is selected and recorded by.
+	Name() string
+	// Initial returns the rating of a player who has not played a rated match.
+	Initial() Rating
+	// Rate returns the ratings of both players after a match, given their ratings before it and
+	// the score of Player A: Win, Draw or Loss.
+	Rate(a, b Rating, scoreA float64) (Rating, Rating)
+}
+
+// Systems lists the names of the built-in rating systems.
+var Systems = []string{"elo", "glicko2"}
+
+// ByName returns the built-in rating system with the given name, with its default parameters.
+//
+// Parameters:
+//   - name: The name of the rating system: "elo" or "glicko2".
+//
+// Returns:
+//   - Rater: The rating system.
+//   - error: An error wrapping ErrUnknownSystem, if no rating system has that name.
+func ByName(name string) (Rater, error) {
+	switch name {
+	case "elo":
+		return NewElo(), nil
+	case "glicko2":
+		return NewGlicko2(), nil
+	}
+	return nil, fmt.Errorf("%w %q", ErrUnknownSystem, name)
+}
+
+// Current returns a player's recorded rating if it belongs to the given rating system, and the
+// system's initial rating otherwise, e.g. for a player who has never been rated.
+//
+// Parameters:
+//   - r: The rating system.
+//   - recorded: The recorded rating of the player.
+//
+// Returns:
+//   - Rating: The player's rating under the rating system.
+func Current(r Rater, recorded Rating) Rating {
+	if recorded.System != r.Name() {
+		return r.Initial()
+	}
+	return recorded
+}
+
+// Score returns the score of a player in a conducted match: Win if they won, including on
+// timeout, Loss if they lost and Draw otherwise.
+//
+// Parameters:
+//   - outcome: The outcome of the match.
+//   - p: A pointer to one of the players of the match.
+//
+// Returns:
+//   - float64: The score of the player.
+func Score(outcome match.MatchOutcome, p *player.Player) float64 {
+	switch {
+	case outcome.Winner == p:
+		return Win
+	case outcome.Loser == p:
+		return Loss
+	}
+	return Draw
+}
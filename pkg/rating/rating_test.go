@@ -0,0 +1,133 @@
+package rating
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"proj/pkg/match"
+	"proj/pkg/player"
+	"testing"
+)
+
+// ANSI escape codes for text color
+const (
+	redColor   = "\033[31m"
+	greenColor = "\033[32m"
+	resetColor = "\033[0m"
+)
+
+// near reports whether two values are within a tolerance of each other.
+func near(a, b, tolerance float64) bool {
+	return math.Abs(a-b) <= tolerance
+}
+
+// TestElo tests the Elo rating system.
+//
+// Test scenarios:
+//  1. When equally rated players meet, the winner gains 16 points and the loser loses 16.
+//  2. A draw between equally rated players changes nothing, but counts as a match.
+//  3. An upset win by a much lower rated player gains nearly the whole K-factor.
+func TestElo(t *testing.T) {
+	elo := NewElo()
+
+	//TEST 1: equal ratings, win
+	a, b := elo.Rate(elo.Initial(), elo.Initial(), Win)
+	if a.Rating != 1516 || b.Rating != 1484 || a.Matches != 1 || b.Deviation != 0 {
+		t.Errorf(redColor+"Expected 1516 and 1484, got %v and %v"+resetColor, a, b)
+	} else {
+		fmt.Println(greenColor + "TestElo : Test1 : Passed" + resetColor)
+	}
+
+	//TEST 2: equal ratings, draw
+	a, b = elo.Rate(elo.Initial(), elo.Initial(), Draw)
+	if a.Rating != 1500 || b.Rating != 1500 || b.Matches != 1 {
+		t.Errorf(redColor+"Expected unchanged ratings, got %v and %v"+resetColor, a, b)
+	} else {
+		fmt.Println(greenColor + "TestElo : Test2 : Passed" + resetColor)
+	}
+
+	//TEST 3: upset
+	underdog := Rating{System: "elo", Rating: 1200}
+	a, b = elo.Rate(underdog, elo.Initial(), Win)
+	if !near(a.Rating, 1227.17, 0.01) || !near(a.Rating+b.Rating, 2700, 1e-9) {
+		t.Errorf(redColor+"Expected the underdog to gain about 27 points, got %v and %v"+resetColor, a, b)
+	} else {
+		fmt.Println(greenColor + "TestElo : Test3 : Passed" + resetColor)
+	}
+}
+
+// TestGlicko2 tests the Glicko-2 rating system.
+//
+// Test scenarios:
+//  1. Reproduce the worked example of Glickman's paper: a 1500 player with deviation 200 beats
+//     a 1400 player, then loses to a 1550 and a 1700 player in one rating period.
+//  2. When two unrated players meet, the winner gains what the loser loses and both deviations shrink.
+func TestGlicko2(t *testing.T) {
+	glicko := NewGlicko2()
+
+	//TEST 1: Glickman's example
+	r := glicko.update(Rating{System: "glicko2", Rating: 1500, Deviation: 200, Volatility: 0.06},
+		[]Rating{{Rating: 1400, Deviation: 30}, {Rating: 1550, Deviation: 100}, {Rating: 1700, Deviation: 300}},
+		[]float64{Win, Loss, Loss})
+	if !near(r.Rating, 1464.06, 0.01) || !near(r.Deviation, 151.52, 0.01) || !near(r.Volatility, 0.05999, 0.00001) || r.Matches != 3 {
+		t.Errorf(redColor+"Expected 1464.06 ± 151.52 with volatility 0.05999, got %+v"+resetColor, r)
+	} else {
+		fmt.Println(greenColor + "TestGlicko2 : Test1 : Passed" + resetColor)
+	}
+
+	//TEST 2: two unrated players
+	a, b := glicko.Rate(glicko.Initial(), glicko.Initial(), Win)
+	if a.Rating <= 1500 || !near(a.Rating-1500, 1500-b.Rating, 1e-9) || a.Deviation >= 350 || b.Deviation >= 350 {
+		t.Errorf(redColor+"Expected symmetric changes and smaller deviations, got %v and %v"+resetColor, a, b)
+	} else {
+		fmt.Println(greenColor + "TestGlicko2 : Test2 : Passed" + resetColor)
+	}
+}
+
+// TestScore tests scoring match outcomes and selecting rating systems.
+//
+// Test scenarios:
+//  1. The winner of a conducted match scores Win and the loser Loss.
+//  2. ByName selects both built-in systems and rejects unknown names.
+//  3. Current restarts a rating recorded under another system.
+func TestScore(t *testing.T) {
+	//TEST 1: winner and loser
+	hero, villain := player.NewPlayer("Hero", 100, 10, 5), player.NewPlayer("Villain", 50, 5, 2)
+	m, err := match.NewMatch(hero, villain, match.WithSeed(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, outcome := match.ConductMatch(m)
+	if Score(outcome, hero) != Win || Score(outcome, villain) != Loss || Score(match.MatchOutcome{Reason: match.Draw}, hero) != Draw {
+		t.Errorf(redColor+"Expected Hero to score a win, got %v"+resetColor, outcome)
+	} else {
+		fmt.Println(greenColor + "TestScore : Test1 : Passed" + resetColor)
+	}
+
+	//TEST 2: rating systems by name
+	elo, errElo := ByName("elo")
+	glicko, errGlicko := ByName("glicko2")
+	_, errUnknown := ByName("trueskill")
+	if errElo != nil || errGlicko != nil || elo.Name() != "elo" || glicko.Name() != "glicko2" || !errors.Is(errUnknown, ErrUnknownSystem) {
+		t.Errorf(redColor+"Expected elo and glicko2 to be found, got %v, %v, %v"+resetColor, errElo, errGlicko, errUnknown)
+	} else {
+		fmt.Println(greenColor + "TestScore : Test2 : Passed" + resetColor)
+	}
+
+	//TEST 3: ratings of another system
+	recorded := Rating{System: "elo", Rating: 1700, Matches: 12}
+	if Current(glicko, recorded) != glicko.Initial() || Current(elo, recorded) != recorded {
+		t.Errorf(redColor + "Expected the Elo rating to be kept only under Elo" + resetColor)
+	} else {
+		fmt.Println(greenColor + "TestScore : Test3 : Passed" + resetColor)
+	}
+}
+
+// TestMain runs the main testing suite.
+func TestMain(m *testing.M) {
+	fmt.Println("Testing rating package...")
+	Result := m.Run()
+	fmt.Println("Testing complete.")
+	os.Exit(Result)
+}
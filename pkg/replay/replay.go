@@ -0,0 +1,246 @@
+package replay
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"proj/pkg/match"
+	"proj/pkg/player"
+	"reflect"
+)
+
+// FormatVersion is the version of the replay file format written by Save. Load reads every
+// version up to it:
+//   - 1 recorded the players' base attributes and the events of attacks.
+//   - 2 added the class, agility and mana of the players, critical hits, fumbles, dodges and
+//     mana regeneration to the rules, and the action and spell of each round to the events.
+//   - 3 added the status effects applied, ticked and expired in each round.
+//   - 4 added the heal cooldown to the rules.
+//
+// Some builds recorded classes, critical hits, dodges, mana and spells before the version was
+// raised to 2, in files labelled version 1. Load reads those too, as the defaults of a version
+// only fill in the rules a file leaves out.
+const FormatVersion = 4
+
+// ErrNotReproducible is returned when saving a match whose dice were not derived from its seed.
+var ErrNotReproducible = errors.New("match was not rolled with seeded dice and cannot be replayed")
+
+// ErrUnsupportedVersion is returned when loading a replay file written in an unknown format version.
+var ErrUnsupportedVersion = errors.New("unsupported replay format version")
+
+// PlayerRecord stores the starting attributes of a player taking part in a replayed match.
+type PlayerRecord struct {
+	Name     string `json:"name"`              // Name is the name of the player.
+	Health   int    `json:"health"`            // Health is the starting health of the player.
+	Strength int    `json:"strength"`          // Strength is the strength attribute of the player.
+	Attack   int    `json:"attack"`            // Attack is the attack attribute of the player.
+	Agility  int    `json:"agility,omitempty"` // Agility is the agility attribute of the player, if they have any.
+	Class    string `json:"class,omitempty"`   // Class is the name of the player's class, if they have one.
+	Mana     int    `json:"mana,omitempty"`    // Mana is the mana pool of the player, if they have one.
+}
+
+// Replay is everything needed to re-simulate a match and check that it plays out identically.
+type Replay struct {
+	Version int                `json:"version"` // Version is the format version of the replay.
+	Seed    int64              `json:"seed"`    // Seed is the seed the match dice were created with.
+	Rules   match.Rules        `json:"rules"`   // Rules are the rules the match was played under.
+	Players [2]PlayerRecord    `json:"players"` // Players holds Player A and Player B, in that order.
+	Events  []match.RoundEvent `json:"events"`  // Events are the recorded events of every round.
+	Result  string             `json:"result"`  // Result is the recorded overall result of the match.
+}
+
+// Divergence describes a round whose replayed event differs from the recorded one.
+// Recorded or Replayed is nil when that side of the comparison has no such round.
+type Divergence struct {
+	Round    int               // Round is the 1-based number of the diverging round.
+	Recorded *match.RoundEvent // Recorded is the event stored in the replay.
+	Replayed *match.RoundEvent // Replayed is the event produced by re-simulating the match.
+}
+
+// Verification is the outcome of re-simulating a replay.
+type Verification struct {
+	Divergences    []Divergence // Divergences lists every round that did not replay identically.
+	RecordedResult string       // RecordedResult is the match result stored in the replay.
+	ReplayedResult string       // ReplayedResult is the match result produced by re-simulation.
+}
+
+// OK reports whether the replay re-simulated identically, round for round.
+//
+// Returns:
+//   - bool: true if no round diverged and the results agree, false otherwise.
+func (v Verification) OK() bool {
+	return len(v.Divergences) == 0 && v.RecordedResult == v.ReplayedResult
+}
+
+// New captures a conducted match as a Replay.
+//
+// Parameters:
+//   - m: A pointer to the conducted match.
+//
+// Returns:
+//   - *Replay: A pointer to the replay of the match.
+//   - error: ErrNotReproducible if the match did not roll dice derived from its seed.
+func New(m *match.Match) (*Replay, error) {
+	if !m.Reproducible() {
+		return nil, ErrNotReproducible
+	}
+	return &Replay{
+		Version: FormatVersion,
+		Seed:    m.Seed(),
+		Rules:   m.Rules(),
+		Players: [2]PlayerRecord{NewPlayerRecord(m.PlayerA), NewPlayerRecord(m.PlayerB)},
+		Events:  m.Events(),
+		Result:  m.Outcome().String(),
+	}, nil
+}
+
+// NewPlayerRecord captures the attributes of a player, as recorded in replays and the match history.
+//
+// Parameters:
+//   - p: A pointer to the player.
+//
+// Returns:
+//   - PlayerRecord: The recorded attributes of the player.
+func NewPlayerRecord(p *player.Player) PlayerRecord {
+	name, health, strength, attack := player.GetPlayerBaseAttributes(p)
+	return PlayerRecord{name, health, strength, attack, player.GetPlayerAgility(p), player.GetPlayerClass(p).Name, player.GetPlayerMana(p)}
+}
+
+// Player creates the recorded player, ready to enter a match.
+//
+// Returns:
+//   - *player.Player: A pointer to the player.
+//   - error: An error wrapping player.ErrUnknownClass, if the recorded class is not known.
+func (r PlayerRecord) Player() (*player.Player, error) {
+	opts := []player.Option{player.WithAgility(r.Agility), player.WithMana(r.Mana)}
+	if r.Class != "" {
+		class, err := player.FindClass(r.Class)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, player.WithClass(class))
+	}
+	return player.NewPlayer(r.Name, r.Health, r.Strength, r.Attack, opts...), nil
+}
+
+// Save writes a conducted match to a replay file at the given path.
+//
+// Parameters:
+//   - path: The path of the replay file to write.
+//   - m: A pointer to the conducted match.
+//
+// Returns:
+//   - error: An error, if any.
+func Save(path string, m *match.Match) error {
+	r, err := New(m)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// Load reads a replay file from the given path.
+//
+// Parameters:
+//   - path: The path of the replay file to read.
+//
+// Returns:
+//   - *Replay: A pointer to the loaded replay.
+//   - error: An error, if any. ErrUnsupportedVersion is returned for unknown format versions,
+//     including those of files written by newer versions of the arena.
+func Load(path string) (*Replay, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var header struct {
+		Version int `json:"version"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, fmt.Errorf("invalid replay file: %w", err)
+	}
+	if header.Version < 1 || header.Version > FormatVersion {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedVersion, header.Version)
+	}
+
+	r := Replay{Rules: defaultRules(header.Version)}
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("invalid replay file: %w", err)
+	}
+	return &r, nil
+}
+
+// defaultRules returns the rules a replay file of a format version was played under, before the
+// rules it records are applied.
+//
+// Parameters:
+//   - version: The format version of the replay file.
+//
+// Returns:
+//   - match.Rules: The rules to load the recorded rules on top of.
+func defaultRules(version int) match.Rules {
+	rules := match.DefaultRules()
+	if version == 1 {
+		// Rules missing from files recorded before they became configurable keep the mechanics
+		// that were fixed at the time: no round limit, no critical hits, fumbles or dodging, and no mana.
+		rules.MaxRounds, rules.ManaRegen = 0, 0
+		rules.Criticals, rules.Fumbles, rules.Dodge = false, false, false
+	}
+	if version < 4 {
+		// The heal action could be taken every turn before it had a cooldown.
+		rules.HealCooldown = 0
+	}
+	return rules
+}
+
+// Verify re-simulates the match from the replay's players, seed and rules, and compares every
+// round with the recorded events. Each round is played with the action recorded for it, so a
+// match played turn by turn replays as faithfully as one played automatically.
+//
+// Returns:
+//   - Verification: The diverging rounds, together with the recorded and replayed results.
+//   - error: An error, if a recorded class is not known or match.NewMatch rejects the recorded
+//     players or rules.
+func (r *Replay) Verify() (Verification, error) {
+	playerA, err := r.Players[0].Player()
+	if err != nil {
+		return Verification{}, err
+	}
+	playerB, err := r.Players[1].Player()
+	if err != nil {
+		return Verification{}, err
+	}
+	m, err := match.NewMatch(playerA, playerB, match.WithSeed(r.Seed), match.WithRules(r.Rules))
+	if err != nil {
+		return Verification{}, err
+	}
+	for _, event := range r.Events {
+		// An action that cannot be taken means the match has already diverged; the remaining
+		// rounds are played automatically and reported as divergences.
+		if _, err := m.PlayTurn(event.ChosenAction()); err != nil {
+			break
+		}
+	}
+	_, outcome := match.ConductMatch(m)
+	replayed := m.Events()
+
+	verification := Verification{RecordedResult: r.Result, ReplayedResult: outcome.String()}
+	for i := 0; i < len(r.Events) || i < len(replayed); i++ {
+		var recordedEvent, replayedEvent *match.RoundEvent
+		if i < len(r.Events) {
+			recordedEvent = &r.Events[i]
+		}
+		if i < len(replayed) {
+			replayedEvent = &replayed[i]
+		}
+		if recordedEvent == nil || replayedEvent == nil || !reflect.DeepEqual(*recordedEvent, *replayedEvent) {
+			verification.Divergences = append(verification.Divergences, Divergence{i + 1, recordedEvent, replayedEvent})
+		}
+	}
+	return verification, nil
+}
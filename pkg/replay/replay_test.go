@@ -0,0 +1,286 @@
+package replay
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"proj/pkg/match"
+	"proj/pkg/player"
+	"testing"
+)
+
+// ANSI escape codes for text color
+const (
+	redColor   = "\033[31m"
+	greenColor = "\033[32m"
+	resetColor = "\033[0m"
+)
+
+// TestSaveLoadVerify tests that a saved match can be loaded back and re-simulated identically.
+//
+// Test scenarios:
+//  1. Save a seeded match, load it, and check the seed, players and events survive the round trip.
+//  2. Verify the loaded replay and check that no round diverges.
+//  3. Tamper with a recorded event and check that the divergence is flagged for that round.
+//  4. Play a match between spellcasters turn by turn, defending, healing and casting, and check
+//     that its replay verifies with the recorded actions.
+//  5. Play a match with status effects turn by turn, save and load it, and check that the applied
+//     effects and the lost turn survive the round trip and the replay verifies.
+func TestSaveLoadVerify(t *testing.T) {
+	//TEST 1: save and load a seeded match
+	m, err := match.NewMatch(player.NewPlayer("PlayerA", 50, 5, 10), player.NewPlayer("PlayerB", 100, 10, 5), match.WithSeed(7))
+	if err != nil {
+		t.Fatal(err)
+	}
+	match.ConductMatch(m)
+	path := filepath.Join(t.TempDir(), "match.json")
+	if err := Save(path, m); err != nil {
+		t.Fatalf(redColor+"Expected Save to succeed, got %v"+resetColor, err)
+	}
+	r, err := Load(path)
+	if err != nil {
+		t.Fatalf(redColor+"Expected Load to succeed, got %v"+resetColor, err)
+	}
+	if r.Seed != 7 || r.Players[0].Name != "PlayerA" || r.Players[1].Health != 100 || len(r.Events) != len(m.Events()) {
+		t.Errorf(redColor+"Expected the replay to match the saved match, got %+v"+resetColor, r)
+	} else {
+		fmt.Println(greenColor + "TestSaveLoadVerify : Test1 : Passed" + resetColor)
+	}
+
+	//TEST 2: the replay re-simulates identically
+	if verification, err := r.Verify(); err != nil || !verification.OK() {
+		t.Errorf(redColor+"Expected the replay to verify, got %+v, %v"+resetColor, verification, err)
+	} else {
+		fmt.Println(greenColor + "TestSaveLoadVerify : Test2 : Passed" + resetColor)
+	}
+
+	//TEST 3: a tampered round is flagged as diverging
+	r.Events[0].Damage++
+	verification, _ := r.Verify()
+	if verification.OK() || len(verification.Divergences) != 1 || verification.Divergences[0].Round != 1 {
+		t.Errorf(redColor+"Expected round 1 to diverge, got %+v"+resetColor, verification.Divergences)
+	} else {
+		fmt.Println(greenColor + "TestSaveLoadVerify : Test3 : Passed" + resetColor)
+	}
+
+	//TEST 4: a match played turn by turn
+	m, err = match.NewMatch(player.NewPlayer("PlayerA", 50, 5, 10, player.WithMana(20)), player.NewPlayer("PlayerB", 100, 10, 5, player.WithMana(20)), match.WithSeed(7))
+	if err != nil {
+		t.Fatal(err)
+	}
+	actions := []match.Action{{Kind: match.ActionDefend}, {Kind: match.ActionHeal}, {Kind: match.ActionCast, Spell: match.SpellShield}, {Kind: match.ActionAttack}}
+	for _, action := range actions {
+		if _, err := m.PlayTurn(action); err != nil {
+			t.Fatal(err)
+		}
+	}
+	match.ConductMatch(m)
+	r, err = New(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if verification, err := r.Verify(); err != nil || !verification.OK() || r.Events[0].Action != match.ActionDefend {
+		t.Errorf(redColor+"Expected the turn-by-turn replay to verify, got %+v, %v"+resetColor, verification, err)
+	} else {
+		fmt.Println(greenColor + "TestSaveLoadVerify : Test4 : Passed" + resetColor)
+	}
+
+	//TEST 5: status effects
+	m, err = match.NewMatch(player.NewPlayer("PlayerA", 50, 5, 10, player.WithMana(30)), player.NewPlayer("PlayerB", 100, 10, 5, player.WithMana(30)), match.WithSeed(7))
+	if err != nil {
+		t.Fatal(err)
+	}
+	actions = []match.Action{{Kind: match.ActionCast, Spell: match.SpellVenom}, {Kind: match.ActionCast, Spell: match.SpellIgnite},
+		{Kind: match.ActionCast, Spell: match.SpellDaze}, {Kind: match.ActionAttack}, {Kind: match.ActionCast, Spell: match.SpellRenew}}
+	for _, action := range actions {
+		if _, err := m.PlayTurn(action); err != nil {
+			t.Fatal(err)
+		}
+	}
+	match.ConductMatch(m)
+	if err := Save(path, m); err != nil {
+		t.Fatal(err)
+	}
+	r, err = Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if verification, err := r.Verify(); err != nil || !verification.OK() || len(r.Events[0].Applied) != 1 || !r.Events[3].Stunned {
+		t.Errorf(redColor+"Expected the replay with status effects to verify, got %+v, %v"+resetColor, verification, err)
+	} else {
+		fmt.Println(greenColor + "TestSaveLoadVerify : Test5 : Passed" + resetColor)
+	}
+}
+
+// TestSaveRejectsCustomDice tests that matches rolled with custom dice cannot be saved.
+func TestSaveRejectsCustomDice(t *testing.T) {
+	m, err := match.NewMatch(player.NewPlayer("PlayerA", 50, 5, 10), player.NewPlayer("PlayerB", 100, 2, 5), match.WithDice(match.NewFixedDice(4)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	match.ConductMatch(m)
+	err = Save(filepath.Join(t.TempDir(), "match.json"), m)
+	if !errors.Is(err, ErrNotReproducible) {
+		t.Errorf(redColor+"Expected ErrNotReproducible, got %v"+resetColor, err)
+	} else {
+		fmt.Println(greenColor + "TestSaveRejectsCustomDice : Test1 : Passed" + resetColor)
+	}
+}
+
+// TestLoadRejectsUnknownVersion tests that replay files from an unknown format version are rejected.
+//
+// Test scenarios:
+//  1. A file of a version that never existed is rejected with ErrUnsupportedVersion.
+//  2. A file written by a newer version of the arena is rejected with ErrUnsupportedVersion
+//     before its contents are read.
+func TestLoadRejectsUnknownVersion(t *testing.T) {
+	//TEST 1: unknown version
+	path := filepath.Join(t.TempDir(), "match.json")
+	if err := os.WriteFile(path, []byte(`{"version": 99}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_, err := Load(path)
+	if !errors.Is(err, ErrUnsupportedVersion) {
+		t.Errorf(redColor+"Expected ErrUnsupportedVersion, got %v"+resetColor, err)
+	} else {
+		fmt.Println(greenColor + "TestLoadRejectsUnknownVersion : Test1 : Passed" + resetColor)
+	}
+
+	//TEST 2: newer version
+	newer := fmt.Sprintf(`{"version": %d, "rules": {"diceSides": "many"}}`, FormatVersion+1)
+	if err := os.WriteFile(path, []byte(newer), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); !errors.Is(err, ErrUnsupportedVersion) {
+		t.Errorf(redColor+"Expected ErrUnsupportedVersion, got %v"+resetColor, err)
+	} else {
+		fmt.Println(greenColor + "TestLoadRejectsUnknownVersion : Test2 : Passed" + resetColor)
+	}
+}
+
+// legacyReplay is a replay file saved before the rules of a match became configurable, when
+// its rules held only the number of dice sides.
+const legacyReplay = `{
+  "version": 1,
+  "seed": 42,
+  "rules": {"diceSides": 6},
+  "players": [
+    {"name": "Hero", "health": 30, "strength": 5, "attack": 10},
+    {"name": "Villain", "health": 25, "strength": 4, "attack": 8}
+  ],
+  "events": [
+    {"round": 1, "attacker": "Villain", "defender": "Hero", "attackRoll": 6, "defenceRoll": 6, "damage": 18, "defenderHealthBefore": 30, "defenderHealthAfter": 12},
+    {"round": 2, "attacker": "Hero", "defender": "Villain", "attackRoll": 3, "defenceRoll": 1, "damage": 26, "defenderHealthBefore": 25, "defenderHealthAfter": 0}
+  ],
+  "result": "Hero wins"
+}`
+
+// TestLoadLegacy tests that replay files saved by earlier versions still load and verify.
+//
+// Test scenarios:
+//  1. A version 1 file saved before the rules were configurable loads with the mechanics of the
+//     time, without a round limit or mana, and verifies.
+//  2. A version 3 file of a match where a player healed on consecutive turns, saved before heal
+//     had a cooldown, loads without the cooldown and verifies.
+//  3. A file labelled version 1 but recording classes, critical hits, dodges and spells, as some
+//     builds saved before the version was raised to 2, loads with the rules it records and verifies.
+func TestLoadLegacy(t *testing.T) {
+	//TEST 1: rules with only the dice sides
+	path := filepath.Join(t.TempDir(), "legacy.json")
+	if err := os.WriteFile(path, []byte(legacyReplay), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	r, err := Load(path)
+	if err != nil {
+		t.Fatalf(redColor+"Expected Load to succeed, got %v"+resetColor, err)
+	}
+	verification, err := r.Verify()
+	if err != nil || !verification.OK() || r.Rules.MaxRounds != 0 || r.Rules.TieBreak != match.TieBreakHealth || r.Rules.ManaRegen != 0 {
+		t.Errorf(redColor+"Expected the legacy replay to verify, got %+v, %+v, %v"+resetColor, r.Rules, verification, err)
+	} else {
+		fmt.Println(greenColor + "TestLoadLegacy : Test1 : Passed" + resetColor)
+	}
+
+	//TEST 2: heal without a cooldown
+	rules := match.DefaultRules()
+	rules.HealCooldown = 0
+	m, err := match.NewMatch(player.NewPlayer("PlayerA", 50, 5, 10), player.NewPlayer("PlayerB", 100, 10, 5), match.WithSeed(3), match.WithRules(rules))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 4; i++ {
+		m.PlayTurn(match.Action{Kind: match.ActionHeal})
+	}
+	match.ConductMatch(m)
+	saved, err := New(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(saved)
+	var file map[string]any
+	json.Unmarshal(data, &file)
+	file["version"] = 3
+	delete(file["rules"].(map[string]any), "healCooldown")
+	data, _ = json.Marshal(file)
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	r, err = Load(path)
+	if err != nil {
+		t.Fatalf(redColor+"Expected Load to succeed, got %v"+resetColor, err)
+	}
+	verification, err = r.Verify()
+	if err != nil || !verification.OK() || r.Rules.HealCooldown != 0 || r.Events[2].Action != match.ActionHeal {
+		t.Errorf(redColor+"Expected the version 3 replay to verify without a heal cooldown, got %+v, %+v, %v"+resetColor, r.Rules, verification, err)
+	} else {
+		fmt.Println(greenColor + "TestLoadLegacy : Test2 : Passed" + resetColor)
+	}
+
+	//TEST 3: later mechanics under version 1
+	rules = match.DefaultRules()
+	rules.Criticals, rules.Fumbles, rules.Dodge, rules.HealCooldown = true, true, true, 0
+	mage, _ := player.FindClass("Mage")
+	m, err = match.NewMatch(player.NewPlayer("Merlin", 60, 5, 8, player.WithClass(mage), player.WithMana(20), player.WithAgility(20)),
+		player.NewPlayer("Hero", 100, 10, 5, player.WithAgility(10)), match.WithSeed(5), match.WithRules(rules))
+	if err != nil {
+		t.Fatal(err)
+	}
+	match.ConductMatch(m)
+	saved, err = New(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ = json.Marshal(saved)
+	file = nil
+	json.Unmarshal(data, &file)
+	file["version"] = 1
+	delete(file["rules"].(map[string]any), "healCooldown")
+	data, _ = json.Marshal(file)
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	r, err = Load(path)
+	if err != nil {
+		t.Fatalf(redColor+"Expected Load to succeed, got %v"+resetColor, err)
+	}
+	cast := false
+	for _, event := range r.Events {
+		cast = cast || event.Spell != ""
+	}
+	verification, err = r.Verify()
+	if err != nil || !verification.OK() || !cast || !r.Rules.Criticals || !r.Rules.Dodge || r.Rules.ManaRegen != rules.ManaRegen || r.Players[0].Mana != 20 {
+		t.Errorf(redColor+"Expected the early replay to verify with its recorded rules, got %+v, %+v, %v"+resetColor, r.Rules, verification, err)
+	} else {
+		fmt.Println(greenColor + "TestLoadLegacy : Test3 : Passed" + resetColor)
+	}
+}
+
+// TestMain runs the main testing suite.
+func TestMain(m *testing.M) {
+	fmt.Println("Testing replay package...")
+	Result := m.Run()
+	fmt.Println("Testing complete.")
+	os.Exit(Result)
+}
@@ -0,0 +1,386 @@
+package roster
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"proj/pkg/player"
+	"proj/pkg/rating"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Errors returned by the roster, usable with errors.Is.
+var (
+	ErrNotFound      = errors.New("player not found in roster")
+	ErrDuplicateName = errors.New("a player with that name is already in the roster")
+	ErrDuplicateID   = errors.New("an entry with that ID is already in the roster")
+	ErrNumericName   = errors.New("a player name that is a number would be read as an ID")
+)
+
+// Entry is a named player kept in the roster.
+type Entry struct {
+	ID       int           `json:"id"`                // ID identifies the entry; IDs count up from 1 and are never reused.
+	Name     string        `json:"name"`              // Name is the name of the player.
+	Health   int           `json:"health"`            // Health is the health attribute of the player.
+	Strength int           `json:"strength"`          // Strength is the strength attribute of the player.
+	Attack   int           `json:"attack"`            // Attack is the attack attribute of the player.
+	Agility  int           `json:"agility,omitempty"` // Agility is the agility attribute of the player, if they have any.
+	Class    string        `json:"class,omitempty"`   // Class is the name of the player's class, if they have one.
+	Mana     int           `json:"mana,omitempty"`    // Mana is the mana pool of the player, if they have one.
+	Rating   rating.Rating `json:"rating"`            // Rating is the skill rating of the player, updated after every rated match.
+}
+
+// Player creates a new Player with the attributes, agility, class and mana of the entry, ready to enter a match.
+// An entry whose class is no longer known creates a player without a class; Load rejects such
+// entries, so this only happens if the known classes change after the roster is loaded.
+//
+// Returns:
+//   - *player.Player: A pointer to the newly created Player instance.
+func (e Entry) Player() *player.Player {
+	opts := []player.Option{player.WithAgility(e.Agility), player.WithMana(e.Mana)}
+	if class, err := player.FindClass(e.Class); err == nil {
+		opts = append(opts, player.WithClass(class))
+	}
+	return player.NewPlayer(e.Name, e.Health, e.Strength, e.Attack, opts...)
+}
+
+// Roster is the set of players kept on disk, so they need not be retyped for every match.
+// Changes are made in memory and written to disk by Save.
+type Roster struct {
+	path    string  // path is the location of the roster file.
+	nextID  int     // nextID is the ID the next added entry receives.
+	entries []Entry // entries are the players in the roster, in the order they were added.
+}
+
+// file is the on-disk form of a roster.
+type file struct {
+	NextID  int     `json:"nextId"`  // NextID is the ID the next added entry receives.
+	Players []Entry `json:"players"` // Players are the entries of the roster.
+}
+
+// DefaultPath returns the standard location of the roster file inside the user's configuration directory.
+//
+// Returns:
+//   - string: The path of the roster file.
+//   - error: An error, if the configuration directory cannot be determined.
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "magical-arena", "roster.json"), nil
+}
+
+// Load reads the roster kept in the file at the given path. A missing file is an empty roster;
+// the file and its directory are created when the roster is first saved.
+//
+// Parameters:
+//   - path: The path of the roster file.
+//
+// Returns:
+//   - *Roster: A pointer to the loaded roster.
+//   - error: An error, if the file cannot be read or holds an invalid roster, such as one wrapping
+//     ErrDuplicateID or ErrDuplicateName if two entries share an ID or a name, or ErrNumericName
+//     if a name is a number.
+//
+// Example:
+//
+//	r, err := Load("roster.json")
+//	entry, err := r.Find("Hero")
+//	hero := entry.Player()
+func Load(path string) (*Roster, error) {
+	r := &Roster{path: path, nextID: 1}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var f file
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("invalid roster file: %w", err)
+	}
+	for i, entry := range f.Players {
+		if isNumeric(entry.Name) {
+			return nil, fmt.Errorf("invalid roster entry %d: %w: %q", entry.ID, ErrNumericName, entry.Name)
+		}
+		for _, other := range f.Players[:i] {
+			switch {
+			case other.ID == entry.ID:
+				return nil, fmt.Errorf("invalid roster entry %d: %w", entry.ID, ErrDuplicateID)
+			case strings.EqualFold(other.Name, entry.Name):
+				return nil, fmt.Errorf("invalid roster entry %d: %w: %q", entry.ID, ErrDuplicateName, entry.Name)
+			}
+		}
+		if entry.Class != "" {
+			if _, err := player.FindClass(entry.Class); err != nil {
+				return nil, fmt.Errorf("invalid roster entry %d: %w", entry.ID, err)
+			}
+		}
+		if err := player.Validate(entry.Player()); err != nil {
+			return nil, fmt.Errorf("invalid roster entry %d: %w", entry.ID, err)
+		}
+		if entry.ID >= r.nextID {
+			r.nextID = entry.ID + 1
+		}
+	}
+	r.entries = f.Players
+	if f.NextID > r.nextID {
+		r.nextID = f.NextID
+	}
+	return r, nil
+}
+
+// Save writes the roster to its file, replacing the previous contents.
+//
+// Returns:
+//   - error: An error, if the file cannot be written.
+func (r *Roster) Save() error {
+	data, err := json.MarshalIndent(file{NextID: r.nextID, Players: r.Entries()}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return err
+	}
+
+	// Write to a temporary file first, so an interrupted save never leaves a truncated roster.
+	tmp := r.path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, r.path)
+}
+
+// Entries returns the players in the roster, in the order they were added.
+//
+// Returns:
+//   - []Entry: A copy of the roster entries.
+func (r *Roster) Entries() []Entry {
+	entries := make([]Entry, len(r.entries))
+	copy(entries, r.entries)
+	return entries
+}
+
+// Add adds a player to the roster under a new ID.
+//
+// Parameters:
+//   - p: A pointer to the player to add.
+//
+// Returns:
+//   - Entry: The added entry, with its assigned ID.
+//   - error: An error wrapping one of the player.Validate errors, ErrDuplicateName or
+//     ErrNumericName, if the player cannot be added.
+func (r *Roster) Add(p *player.Player) (Entry, error) {
+	entry, err := r.newEntry(0, p)
+	if err != nil {
+		return Entry{}, err
+	}
+
+	entry.ID = r.nextID
+	r.nextID++
+	r.entries = append(r.entries, entry)
+	return entry, nil
+}
+
+// Update replaces the attributes of the entry with the given ID, keeping its ID and rating.
+//
+// Parameters:
+//   - id: The ID of the entry.
+//   - p: A pointer to the player holding the new attributes.
+//
+// Returns:
+//   - Entry: The updated entry.
+//   - error: An error wrapping ErrNotFound, ErrDuplicateName, ErrNumericName or one of the
+//     player.Validate errors, if the entry cannot be updated.
+func (r *Roster) Update(id int, p *player.Player) (Entry, error) {
+	i, err := r.index(id)
+	if err != nil {
+		return Entry{}, err
+	}
+	entry, err := r.newEntry(id, p)
+	if err != nil {
+		return Entry{}, err
+	}
+
+	entry.ID = id
+	entry.Rating = r.entries[i].Rating
+	r.entries[i] = entry
+	return entry, nil
+}
+
+// SetRating replaces the rating of the entry with the given ID.
+//
+// Parameters:
+//   - id: The ID of the entry.
+//   - rt: The new rating of the entry.
+//
+// Returns:
+//   - error: An error wrapping ErrNotFound, if no entry has that ID.
+func (r *Roster) SetRating(id int, rt rating.Rating) error {
+	i, err := r.index(id)
+	if err != nil {
+		return err
+	}
+	r.entries[i].Rating = rt
+	return nil
+}
+
+// Remove deletes the entry with the given ID. Its ID is not given to any later entry.
+//
+// Parameters:
+//   - id: The ID of the entry.
+//
+// Returns:
+//   - error: An error wrapping ErrNotFound, if no entry has that ID.
+func (r *Roster) Remove(id int) error {
+	i, err := r.index(id)
+	if err != nil {
+		return err
+	}
+	r.entries = append(r.entries[:i], r.entries[i+1:]...)
+	return nil
+}
+
+// Get returns the entry with the given ID.
+//
+// Parameters:
+//   - id: The ID of the entry.
+//
+// Returns:
+//   - Entry: The entry.
+//   - error: An error wrapping ErrNotFound, if no entry has that ID.
+func (r *Roster) Get(id int) (Entry, error) {
+	i, err := r.index(id)
+	if err != nil {
+		return Entry{}, err
+	}
+	return r.entries[i], nil
+}
+
+// Find returns the entry referred to by an ID or a name. A reference that is a number is looked
+// up as an ID, as no name can be a number; anything else is looked up as a name, ignoring case.
+//
+// Parameters:
+//   - ref: The ID or name of the entry.
+//
+// Returns:
+//   - Entry: The entry.
+//   - error: An error wrapping ErrNotFound, if no entry matches the reference.
+func (r *Roster) Find(ref string) (Entry, error) {
+	ref = strings.TrimSpace(ref)
+	if id, err := strconv.Atoi(ref); err == nil {
+		return r.Get(id)
+	}
+	for _, entry := range r.entries {
+		if strings.EqualFold(entry.Name, ref) {
+			return entry, nil
+		}
+	}
+	return Entry{}, fmt.Errorf("%w: %q", ErrNotFound, ref)
+}
+
+// Lookup returns the entry a player was created from: the entry with the player's name and
+// exactly the player's attributes, agility, class and mana. A player who merely shares an entry's name is not matched.
+//
+// Parameters:
+//   - p: A pointer to the player.
+//
+// Returns:
+//   - Entry: The entry.
+//   - bool: true if an entry matches the player, false otherwise.
+func (r *Roster) Lookup(p *player.Player) (Entry, bool) {
+	for _, entry := range r.entries {
+		if *entry.Player() == *p {
+			return entry, true
+		}
+	}
+	return Entry{}, false
+}
+
+// Leaderboard returns the entries ranked by their rating under a rating system, best first.
+// Entries not yet rated under the system carry its initial rating; among equal ratings, the
+// entry with the smaller deviation, then the one added first, ranks higher.
+//
+// Parameters:
+//   - rater: The rating system to rank by.
+//
+// Returns:
+//   - []Entry: The ranked entries.
+func (r *Roster) Leaderboard(rater rating.Rater) []Entry {
+	entries := r.Entries()
+	for i := range entries {
+		entries[i].Rating = rating.Current(rater, entries[i].Rating)
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Rating.Rating != entries[j].Rating.Rating {
+			return entries[i].Rating.Rating > entries[j].Rating.Rating
+		}
+		return entries[i].Rating.Deviation < entries[j].Rating.Deviation
+	})
+	return entries
+}
+
+// index returns the position of the entry with the given ID.
+//
+// Parameters:
+//   - id: The ID of the entry.
+//
+// Returns:
+//   - int: The position of the entry in the roster.
+//   - error: An error wrapping ErrNotFound, if no entry has that ID.
+func (r *Roster) index(id int) (int, error) {
+	for i, entry := range r.entries {
+		if entry.ID == id {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %d", ErrNotFound, id)
+}
+
+// newEntry validates a player and captures it as an entry without an ID. The name must not be
+// taken by any entry other than the one with the given ID, nor be a number, so that Find can tell
+// names and IDs apart.
+//
+// Parameters:
+//   - id: The ID of the entry being replaced, or 0 for a new entry.
+//   - p: A pointer to the player.
+//
+// Returns:
+//   - Entry: The entry for the player.
+//   - error: An error wrapping ErrDuplicateName, ErrNumericName or one of the player.Validate errors.
+func (r *Roster) newEntry(id int, p *player.Player) (Entry, error) {
+	if err := player.Validate(p); err != nil {
+		return Entry{}, err
+	}
+
+	name, health, strength, attack := player.GetPlayerBaseAttributes(p)
+	if isNumeric(name) {
+		return Entry{}, fmt.Errorf("%w: %q", ErrNumericName, name)
+	}
+	for _, entry := range r.entries {
+		if entry.ID != id && strings.EqualFold(entry.Name, name) {
+			return Entry{}, fmt.Errorf("%w: %q", ErrDuplicateName, name)
+		}
+	}
+	return Entry{Name: name, Health: health, Strength: strength, Attack: attack, Agility: player.GetPlayerAgility(p), Class: player.GetPlayerClass(p).Name, Mana: player.GetPlayerMana(p)}, nil
+}
+
+// isNumeric reports whether a player name is a number, which Find would read as an ID.
+//
+// Parameters:
+//   - name: The name of the player.
+//
+// Returns:
+//   - bool: true if the name is a number, false otherwise.
+func isNumeric(name string) bool {
+	_, err := strconv.Atoi(strings.TrimSpace(name))
+	return err == nil
+}
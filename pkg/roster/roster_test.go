@@ -0,0 +1,205 @@
+package roster
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"proj/pkg/player"
+	"proj/pkg/rating"
+	"testing"
+)
+
+// ANSI escape codes for text color
+const (
+	redColor   = "\033[31m"
+	greenColor = "\033[32m"
+	resetColor = "\033[0m"
+)
+
+// TestRoster tests managing the roster and keeping it on disk.
+//
+// Test scenarios:
+//  1. A roster whose file does not exist yet is empty.
+//  2. Add two players and check they receive increasing IDs, and that an invalid player, a
+//     name already taken (in any case) or a name that is a number is rejected.
+//  3. Find entries by ID and by name, and check an unknown reference returns ErrNotFound.
+//  4. Update an entry, keeping its ID, and check it cannot take another entry's name or a number.
+//  5. Remove an entry, save and reload the roster, and check a new entry does not reuse the ID.
+func TestRoster(t *testing.T) {
+	//TEST 1: empty roster
+	path := filepath.Join(t.TempDir(), "nested", "roster.json")
+	r, err := Load(path)
+	if err != nil || len(r.Entries()) != 0 {
+		t.Errorf(redColor+"Expected an empty roster, got %v, %v"+resetColor, r, err)
+	} else {
+		fmt.Println(greenColor + "TestRoster : Test1 : Passed" + resetColor)
+	}
+
+	//TEST 2: add players
+	hero, errHero := r.Add(player.NewPlayer("Hero", 100, 10, 5))
+	villain, errVillain := r.Add(player.NewPlayer("Villain", 50, 5, 2))
+	_, errInvalid := r.Add(player.NewPlayer("Ghost", 0, 5, 2))
+	_, errDuplicate := r.Add(player.NewPlayer("hero", 10, 1, 1))
+	_, errNumeric := r.Add(player.NewPlayer("42", 10, 1, 1))
+	if errHero != nil || errVillain != nil || hero.ID != 1 || villain.ID != 2 || len(r.Entries()) != 2 ||
+		!errors.Is(errInvalid, player.ErrNonPositiveHealth) || !errors.Is(errDuplicate, ErrDuplicateName) || !errors.Is(errNumeric, ErrNumericName) {
+		t.Errorf(redColor+"Expected IDs 1 and 2 and the invalid players rejected, got %v, %v, %v, %v, %v"+resetColor, hero, villain, errInvalid, errDuplicate, errNumeric)
+	} else {
+		fmt.Println(greenColor + "TestRoster : Test2 : Passed" + resetColor)
+	}
+
+	//TEST 3: find by ID and name
+	byID, errID := r.Find("2")
+	byName, errName := r.Find(" HERO ")
+	_, errMissing := r.Find("Nobody")
+	if errID != nil || byID != villain || errName != nil || byName != hero || !errors.Is(errMissing, ErrNotFound) {
+		t.Errorf(redColor+"Expected to find Villain by ID and Hero by name, got %v, %v, %v"+resetColor, byID, byName, errMissing)
+	} else {
+		fmt.Println(greenColor + "TestRoster : Test3 : Passed" + resetColor)
+	}
+
+	//TEST 4: update an entry
+	updated, err := r.Update(1, player.NewPlayer("Hero", 120, 12, 6))
+	_, errTaken := r.Update(1, player.NewPlayer("Villain", 120, 12, 6))
+	_, errNumber := r.Update(1, player.NewPlayer("7", 120, 12, 6))
+	if err != nil || updated != (Entry{ID: 1, Name: "Hero", Health: 120, Strength: 12, Attack: 6}) ||
+		!errors.Is(errTaken, ErrDuplicateName) || !errors.Is(errNumber, ErrNumericName) {
+		t.Errorf(redColor+"Expected Hero to be updated in place, got %v, %v, %v"+resetColor, updated, err, errTaken)
+	} else {
+		fmt.Println(greenColor + "TestRoster : Test4 : Passed" + resetColor)
+	}
+
+	//TEST 5: remove, save and reload
+	if err := r.Remove(2); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Save(); err != nil {
+		t.Fatal(err)
+	}
+	reloaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rogue, err := reloaded.Add(player.NewPlayer("Rogue", 60, 5, 8))
+	entries := reloaded.Entries()
+	if err != nil || len(entries) != 2 || entries[0] != updated || rogue.ID != 3 || !errors.Is(reloaded.Remove(2), ErrNotFound) {
+		t.Errorf(redColor+"Expected Hero to survive the reload and Rogue to get ID 3, got %v, %v"+resetColor, entries, err)
+	} else {
+		fmt.Println(greenColor + "TestRoster : Test5 : Passed" + resetColor)
+	}
+}
+
+// TestRatings tests keeping ratings on the roster.
+//
+// Test scenarios:
+//  1. Lookup finds the entry a player was created from, but not a player who only shares its name.
+//  2. A rating set on an entry survives editing the entry.
+//  3. The leaderboard ranks rated entries by rating, with unrated entries at the initial rating.
+func TestRatings(t *testing.T) {
+	r, err := Load(filepath.Join(t.TempDir(), "roster.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	hero, _ := r.Add(player.NewPlayer("Hero", 100, 10, 5))
+	villain, _ := r.Add(player.NewPlayer("Villain", 50, 5, 2))
+	rogue, _ := r.Add(player.NewPlayer("Rogue", 60, 5, 8))
+
+	//TEST 1: lookup
+	found, ok := r.Lookup(hero.Player())
+	_, impostor := r.Lookup(player.NewPlayer("Hero", 100, 10, 6))
+	if !ok || found != hero || impostor {
+		t.Errorf(redColor+"Expected only the roster Hero to be found, got %v, %v, %v"+resetColor, found, ok, impostor)
+	} else {
+		fmt.Println(greenColor + "TestRatings : Test1 : Passed" + resetColor)
+	}
+
+	//TEST 2: rating kept across edits
+	elo := rating.NewElo()
+	winner, loser := elo.Rate(elo.Initial(), elo.Initial(), rating.Win)
+	if err := r.SetRating(rogue.ID, winner); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.SetRating(villain.ID, loser); err != nil {
+		t.Fatal(err)
+	}
+	edited, err := r.Update(rogue.ID, player.NewPlayer("Rogue", 70, 5, 8))
+	if err != nil || edited.Rating != winner || !errors.Is(r.SetRating(42, winner), ErrNotFound) {
+		t.Errorf(redColor+"Expected Rogue to keep its rating, got %v, %v"+resetColor, edited, err)
+	} else {
+		fmt.Println(greenColor + "TestRatings : Test2 : Passed" + resetColor)
+	}
+
+	//TEST 3: leaderboard
+	board := r.Leaderboard(elo)
+	if len(board) != 3 || board[0].Name != "Rogue" || board[1].Name != "Hero" || board[1].Rating != elo.Initial() || board[2].Name != "Villain" {
+		t.Errorf(redColor+"Expected Rogue, Hero and Villain, got %v"+resetColor, board)
+	} else {
+		fmt.Println(greenColor + "TestRatings : Test3 : Passed" + resetColor)
+	}
+}
+
+// TestLoadInvalid tests that a roster file holding an invalid player, or a player of an unknown
+// class, is rejected.
+//
+// Test scenarios:
+//  1. A player with a negative strength is rejected with ErrNonPositiveStrength.
+//  2. A player of an unknown class is rejected with ErrUnknownClass.
+//  3. Two entries with the same ID are rejected with ErrDuplicateID.
+//  4. Two entries with the same name, in any case, are rejected with ErrDuplicateName.
+//  5. A player whose name is a number is rejected with ErrNumericName.
+func TestLoadInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "roster.json")
+	if err := os.WriteFile(path, []byte(`{"nextId":2,"players":[{"id":1,"name":"Hero","health":100,"strength":-1,"attack":5}]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); !errors.Is(err, player.ErrNonPositiveStrength) {
+		t.Errorf(redColor+"Expected ErrNonPositiveStrength, got %v"+resetColor, err)
+	} else {
+		fmt.Println(greenColor + "TestLoadInvalid : Test1 : Passed" + resetColor)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"nextId":2,"players":[{"id":1,"name":"Hero","health":100,"strength":10,"attack":5,"class":"Bard"}]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); !errors.Is(err, player.ErrUnknownClass) {
+		t.Errorf(redColor+"Expected ErrUnknownClass, got %v"+resetColor, err)
+	} else {
+		fmt.Println(greenColor + "TestLoadInvalid : Test2 : Passed" + resetColor)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"nextId":2,"players":[{"id":1,"name":"Hero","health":100,"strength":10,"attack":5},{"id":1,"name":"Villain","health":50,"strength":5,"attack":2}]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); !errors.Is(err, ErrDuplicateID) {
+		t.Errorf(redColor+"Expected ErrDuplicateID, got %v"+resetColor, err)
+	} else {
+		fmt.Println(greenColor + "TestLoadInvalid : Test3 : Passed" + resetColor)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"nextId":3,"players":[{"id":1,"name":"Hero","health":100,"strength":10,"attack":5},{"id":2,"name":"HERO","health":50,"strength":5,"attack":2}]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); !errors.Is(err, ErrDuplicateName) {
+		t.Errorf(redColor+"Expected ErrDuplicateName, got %v"+resetColor, err)
+	} else {
+		fmt.Println(greenColor + "TestLoadInvalid : Test4 : Passed" + resetColor)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"nextId":3,"players":[{"id":1,"name":"Hero","health":100,"strength":10,"attack":5},{"id":2,"name":"42","health":50,"strength":5,"attack":2}]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); !errors.Is(err, ErrNumericName) {
+		t.Errorf(redColor+"Expected ErrNumericName, got %v"+resetColor, err)
+	} else {
+		fmt.Println(greenColor + "TestLoadInvalid : Test5 : Passed" + resetColor)
+	}
+}
+
+// TestMain runs the main testing suite.
+func TestMain(m *testing.M) {
+	fmt.Println("Testing roster package...")
+	Result := m.Run()
+	fmt.Println("Testing complete.")
+	os.Exit(Result)
+}
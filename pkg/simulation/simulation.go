@@ -0,0 +1,194 @@
+package simulation
+
+import (
+	"errors"
+	"proj/pkg/match"
+	"proj/pkg/player"
+	"runtime"
+	"sync"
+)
+
+// ErrNoMatches is returned when a simulation is configured to run no matches.
+var ErrNoMatches = errors.New("number of matches must be greater than 0")
+
+// Config configures a batch simulation.
+type Config struct {
+	Matches int         // Matches is the number of independent matches to conduct.
+	Workers int         // Workers is the number of matches conducted in parallel; runtime.NumCPU() if not positive.
+	Seed    int64       // Seed seeds the dice of every match: match i is rolled with Seed+i.
+	Rules   match.Rules // Rules are the rules every match is played under.
+}
+
+// Report aggregates the outcomes of a batch simulation.
+type Report struct {
+	Matches          int         // Matches is the number of matches conducted.
+	WinsA            int         // WinsA is the number of matches won by Player A.
+	WinsB            int         // WinsB is the number of matches won by Player B.
+	Draws            int         // Draws is the number of matches declared a draw.
+	Timeouts         int         // Timeouts is the number of wins decided by the tie-break at the round limit.
+	RoundHistogram   map[int]int // RoundHistogram counts matches by the number of rounds they lasted.
+	DamageHistogramA map[int]int // DamageHistogramA counts Player A's attacks by the damage they dealt; other actions and lost turns are not counted.
+	DamageHistogramB map[int]int // DamageHistogramB counts Player B's attacks by the damage they dealt; other actions and lost turns are not counted.
+}
+
+// WinRateA returns the fraction of matches won by Player A.
+//
+// Returns:
+//   - float64: The win rate of Player A.
+func (r Report) WinRateA() float64 {
+	return float64(r.WinsA) / float64(r.Matches)
+}
+
+// WinRateB returns the fraction of matches won by Player B.
+//
+// Returns:
+//   - float64: The win rate of Player B.
+func (r Report) WinRateB() float64 {
+	return float64(r.WinsB) / float64(r.Matches)
+}
+
+// DrawRate returns the fraction of matches declared a draw.
+//
+// Returns:
+//   - float64: The draw rate.
+func (r Report) DrawRate() float64 {
+	return float64(r.Draws) / float64(r.Matches)
+}
+
+// MeanRounds returns the average number of rounds per match.
+//
+// Returns:
+//   - float64: The mean match length in rounds.
+func (r Report) MeanRounds() float64 {
+	total := 0
+	for rounds, count := range r.RoundHistogram {
+		total += rounds * count
+	}
+	return float64(total) / float64(r.Matches)
+}
+
+// newReport creates an empty Report.
+//
+// Returns:
+//   - Report: A report with no matches and empty histograms.
+func newReport() Report {
+	return Report{RoundHistogram: map[int]int{}, DamageHistogramA: map[int]int{}, DamageHistogramB: map[int]int{}}
+}
+
+// merge adds the counts of another report to this one.
+//
+// Parameters:
+//   - other: The report to merge in.
+func (r *Report) merge(other Report) {
+	r.Matches += other.Matches
+	r.WinsA += other.WinsA
+	r.WinsB += other.WinsB
+	r.Draws += other.Draws
+	r.Timeouts += other.Timeouts
+	for rounds, count := range other.RoundHistogram {
+		r.RoundHistogram[rounds] += count
+	}
+	for damage, count := range other.DamageHistogramA {
+		r.DamageHistogramA[damage] += count
+	}
+	for damage, count := range other.DamageHistogramB {
+		r.DamageHistogramB[damage] += count
+	}
+}
+
+// Run conducts cfg.Matches independent matches between the two players across a pool of workers
+// and aggregates their outcomes.
+//
+// Match i, counting from 0, is rolled with the seed cfg.Seed+i whichever worker conducts it, so a
+// given configuration produces the same report however many workers conduct it. The players are
+// only read, never modified, so they may be shared by all workers.
+//
+// Parameters:
+//   - playerA: A pointer to the first player in every match.
+//   - playerB: A pointer to the second player in every match.
+//   - cfg: The configuration of the simulation.
+//
+// Returns:
+//   - Report: The aggregated outcomes of every match.
+//   - error: An error, if the configuration is invalid or match.NewMatch rejects the players.
+//
+// Example:
+//
+//	report, err := Run(playerA, playerB, Config{Matches: 10000, Seed: 1, Rules: match.DefaultRules()})
+//	fmt.Printf("A wins %.1f%% of the time\n", report.WinRateA()*100)
+func Run(playerA, playerB *player.Player, cfg Config) (Report, error) {
+	if cfg.Matches <= 0 {
+		return Report{}, ErrNoMatches
+	}
+	if _, err := match.NewMatch(playerA, playerB, match.WithRules(cfg.Rules)); err != nil {
+		return Report{}, err
+	}
+	workers := cfg.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if workers > cfg.Matches {
+		workers = cfg.Matches
+	}
+
+	reports := make([]Report, workers)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			report := newReport()
+			// Worker w conducts matches w, w+workers, w+2*workers, ...
+			for i := w; i < cfg.Matches; i += workers {
+				// The players and rules were validated above, so the match is always valid.
+				m, _ := match.NewMatch(playerA, playerB, match.WithSeed(cfg.Seed+int64(i)), match.WithRules(cfg.Rules))
+				match.ConductMatch(m)
+				record(&report, m)
+			}
+			reports[w] = report
+		}(w)
+	}
+	wg.Wait()
+
+	total := newReport()
+	for _, report := range reports {
+		total.merge(report)
+	}
+	return total, nil
+}
+
+// record adds the outcome of a conducted match to a report.
+//
+// Parameters:
+//   - report: A pointer to the report to update.
+//   - m: A pointer to the conducted match.
+func record(report *Report, m *match.Match) {
+	nameA, _, _, _ := player.GetPlayerBaseAttributes(m.PlayerA)
+	events := m.Events()
+
+	report.Matches++
+	report.RoundHistogram[len(events)]++
+	for _, event := range events {
+		if event.Stunned || event.ChosenAction().Kind != match.ActionAttack {
+			continue
+		}
+		if event.Attacker == nameA {
+			report.DamageHistogramA[event.Damage]++
+		} else {
+			report.DamageHistogramB[event.Damage]++
+		}
+	}
+
+	outcome := m.Outcome()
+	switch {
+	case outcome.IsDraw():
+		report.Draws++
+	case outcome.Winner == m.PlayerA:
+		report.WinsA++
+	default:
+		report.WinsB++
+	}
+	if outcome.Reason == match.Timeout {
+		report.Timeouts++
+	}
+}
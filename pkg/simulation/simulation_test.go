@@ -0,0 +1,110 @@
+package simulation
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"proj/pkg/match"
+	"proj/pkg/odds"
+	"proj/pkg/player"
+	"reflect"
+	"testing"
+)
+
+// ANSI escape codes for text color
+const (
+	redColor   = "\033[31m"
+	greenColor = "\033[32m"
+	resetColor = "\033[0m"
+)
+
+// TestRun tests the batch simulator.
+//
+// Test scenarios:
+//  1. Run 20000 matches on 4 workers and check that the counts add up.
+//  2. Check that the win rate agrees with the exact odds to within sampling error.
+//  3. Run the same configuration again and check the report is identical.
+//  4. Check that a simulation of no matches is rejected.
+//  5. Run the same configuration on a single worker and check the report is identical to that of 4 workers.
+//  6. Simulate spellcasters and check the damage histograms count their attacks, not their spells.
+func TestRun(t *testing.T) {
+	//TEST 1: counts add up
+	playerA := player.NewPlayer("PlayerA", 50, 5, 10)
+	playerB := player.NewPlayer("PlayerB", 100, 10, 5)
+	cfg := Config{Matches: 20000, Workers: 4, Seed: 1, Rules: match.DefaultRules()}
+	report, err := Run(playerA, playerB, cfg)
+	if err != nil {
+		t.Fatalf(redColor+"Expected Run to succeed, got %v"+resetColor, err)
+	}
+	histogramTotal := 0
+	for _, count := range report.RoundHistogram {
+		histogramTotal += count
+	}
+	if report.Matches != 20000 || report.WinsA+report.WinsB+report.Draws != 20000 || histogramTotal != 20000 {
+		t.Errorf(redColor+"Expected 20000 matches in every count, got %d matches, %d+%d wins, %d in histogram"+resetColor, report.Matches, report.WinsA, report.WinsB, histogramTotal)
+	} else {
+		fmt.Println(greenColor + "TestRun : Test1 : Passed" + resetColor)
+	}
+
+	//TEST 2: agrees with the exact odds
+	o, err := odds.Calculate(playerA, playerB, match.DefaultRules())
+	if err != nil {
+		t.Fatalf(redColor+"Expected odds.Calculate to succeed, got %v"+resetColor, err)
+	}
+	if math.Abs(report.WinRateA()-o.WinA) > 0.02 || math.Abs(report.MeanRounds()-o.ExpectedRounds)/o.ExpectedRounds > 0.02 {
+		t.Errorf(redColor+"Expected win rate %.3f and mean rounds %.2f to agree with %+v"+resetColor, report.WinRateA(), report.MeanRounds(), o)
+	} else {
+		fmt.Println(greenColor + "TestRun : Test2 : Passed" + resetColor)
+	}
+
+	//TEST 3: the same configuration produces the same report
+	again, _ := Run(playerA, playerB, cfg)
+	if !reflect.DeepEqual(report, again) {
+		t.Errorf(redColor + "Expected the same configuration to produce the same report" + resetColor)
+	} else {
+		fmt.Println(greenColor + "TestRun : Test3 : Passed" + resetColor)
+	}
+
+	//TEST 4: no matches
+	if _, err := Run(playerA, playerB, Config{Rules: match.DefaultRules()}); !errors.Is(err, ErrNoMatches) {
+		t.Errorf(redColor+"Expected ErrNoMatches, got %v"+resetColor, err)
+	} else {
+		fmt.Println(greenColor + "TestRun : Test4 : Passed" + resetColor)
+	}
+
+	//TEST 5: independent of the number of workers
+	cfg.Workers = 1
+	if single, _ := Run(playerA, playerB, cfg); !reflect.DeepEqual(report, single) {
+		t.Errorf(redColor + "Expected a single worker to produce the same report as 4 workers" + resetColor)
+	} else {
+		fmt.Println(greenColor + "TestRun : Test5 : Passed" + resetColor)
+	}
+
+	//TEST 6: spells are not attacks
+	mageA := player.NewPlayer("MageA", 60, 5, 8, player.WithMana(30))
+	mageB := player.NewPlayer("MageB", 60, 5, 8, player.WithMana(30))
+	report, _ = Run(mageA, mageB, Config{Matches: 100, Workers: 2, Seed: 1, Rules: match.DefaultRules()})
+	rounds, attacks := 0, 0
+	for length, count := range report.RoundHistogram {
+		rounds += length * count
+	}
+	for _, histogram := range []map[int]int{report.DamageHistogramA, report.DamageHistogramB} {
+		for _, count := range histogram {
+			attacks += count
+		}
+	}
+	if attacks == 0 || attacks >= rounds {
+		t.Errorf(redColor+"Expected fewer attacks than the %d rounds, got %d"+resetColor, rounds, attacks)
+	} else {
+		fmt.Println(greenColor + "TestRun : Test6 : Passed" + resetColor)
+	}
+}
+
+// TestMain runs the main testing suite.
+func TestMain(m *testing.M) {
+	fmt.Println("Testing simulation package...")
+	Result := m.Run()
+	fmt.Println("Testing complete.")
+	os.Exit(Result)
+}
@@ -0,0 +1,145 @@
+package strategy
+
+import (
+	"math"
+	"proj/pkg/match"
+	"strconv"
+)
+
+// winValue is the value of a won match, beyond that of any match still being played.
+const winValue = 10
+
+// Expectimax searches every action of the player and of their opponent a few turns ahead,
+// averaging over every outcome of the dice, and picks the action with the best expected value.
+// Each player is assumed to pick the action best for themselves. A match still being played is
+// valued by the difference between the players' remaining shares of their starting health.
+type Expectimax struct {
+	Depth int // Depth is the number of turns searched, the player's own included; below 1 searches one.
+}
+
+// NewExpectimax creates the expectimax strategy, searching the player's turn and their opponent's reply.
+//
+// Returns:
+//   - *Expectimax: A pointer to the strategy.
+func NewExpectimax() *Expectimax {
+	return &Expectimax{Depth: 2}
+}
+
+// Choose picks the action with the highest expected value after searching Depth turns ahead.
+//
+// Parameters:
+//   - turn: The state of the match at the start of the turn.
+//
+// Returns:
+//   - match.Action: The action to take.
+func (e *Expectimax) Choose(turn match.Turn) match.Action {
+	var bestAction match.Action
+	bestValue := math.Inf(-1)
+	for _, action := range turn.Actions() {
+		if value := e.expected(turn, action, max(1, e.Depth)); value > bestValue {
+			bestAction, bestValue = action, value
+		}
+	}
+	return bestAction
+}
+
+// value is the value of a turn for the player whose turn it is, searching depth turns ahead.
+//
+// Parameters:
+//   - turn: The state of the match at the start of the turn.
+//   - depth: The number of turns left to search.
+//
+// Returns:
+//   - float64: The value of the turn.
+func (e *Expectimax) value(turn match.Turn, depth int) float64 {
+	if depth == 0 || turn.Over() {
+		return evaluate(turn)
+	}
+	best := math.Inf(-1)
+	for _, action := range turn.Actions() {
+		best = max(best, e.expected(turn, action, depth))
+	}
+	return best
+}
+
+// expected is the expected value of an action for the player taking it, averaged over the ways
+// the turn can play out. Ways leading to the same state are searched once.
+//
+// Parameters:
+//   - turn: The state of the match at the start of the turn.
+//   - action: The action of the player whose turn it is.
+//   - depth: The number of turns left to search, this one included.
+//
+// Returns:
+//   - float64: The expected value of the action.
+func (e *Expectimax) expected(turn match.Turn, action match.Action, depth int) float64 {
+	// The states are kept in the order the branches first reach them, so that the sum is added up
+	// in the same order every time and equal values compare equal.
+	branches, _ := turn.Branches(action)
+	var states []match.Branch
+	index := make(map[string]int)
+	for _, branch := range branches {
+		key := stateKey(branch.Next)
+		if i, ok := index[key]; ok {
+			states[i].Probability += branch.Probability
+			continue
+		}
+		index[key] = len(states)
+		states = append(states, branch)
+	}
+
+	expected := 0.0
+	for _, state := range states {
+		// The next turn is the opponent's, so its value for them counts against the player.
+		expected -= state.Probability * e.value(state.Next, depth-1)
+	}
+	return expected
+}
+
+// evaluate values a turn for the player whose turn it is.
+//
+// Parameters:
+//   - turn: The state of the match at the start of the turn.
+//
+// Returns:
+//   - float64: winValue if the player won, -winValue if they lost, 0 if both players fell, and
+//     otherwise their remaining share of their starting health minus their opponent's.
+func evaluate(turn match.Turn) float64 {
+	switch {
+	case turn.Attacker.Health <= 0 && turn.Defender.Health <= 0:
+		return 0
+	case turn.Defender.Health <= 0:
+		return winValue
+	case turn.Attacker.Health <= 0:
+		return -winValue
+	}
+	return float64(turn.Attacker.Health)/float64(turn.Attacker.StartHealth) - float64(turn.Defender.Health)/float64(turn.Defender.StartHealth)
+}
+
+// stateKey identifies the state of both players at the start of a turn, so that the ways a turn
+// can play out that lead to the same state can be searched once.
+//
+// Parameters:
+//   - turn: The state of the match at the start of the turn.
+//
+// Returns:
+//   - string: The key of the state.
+func stateKey(turn match.Turn) string {
+	key := make([]byte, 0, 64)
+	for _, f := range []match.FighterState{turn.Attacker, turn.Defender} {
+		key = strconv.AppendInt(key, int64(f.Health), 10)
+		key = append(key, ',')
+		key = strconv.AppendInt(key, int64(f.Mana), 10)
+		key = strconv.AppendBool(append(key, ','), f.Shielded)
+		key = strconv.AppendBool(append(key, ','), f.Defending)
+		key = strconv.AppendInt(append(key, ','), int64(f.HealReady), 10)
+		for _, spell := range match.Spells() {
+			key = strconv.AppendInt(append(key, ','), int64(f.Cooldowns[spell.Name]), 10)
+		}
+		for _, effect := range f.Effects {
+			key = append(append(key, ','), effect.String()...)
+		}
+		key = append(key, ';')
+	}
+	return string(key)
+}
@@ -0,0 +1,190 @@
+package strategy
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"proj/pkg/match"
+)
+
+// ErrUnknownStrategy is returned when no strategy has the requested name.
+var ErrUnknownStrategy = errors.New("unknown strategy")
+
+// Names lists the names of the built-in strategies.
+var Names = []string{"random", "greedy", "cautious", "expectimax"}
+
+// ByName returns the built-in strategy with the given name, with its default parameters.
+//
+// Parameters:
+//   - name: The name of the strategy: "random", "greedy", "cautious" or "expectimax".
+//   - seed: The seed of the random strategy's choices; ignored by the others.
+//
+// Returns:
+//   - match.Strategy: The strategy.
+//   - error: An error wrapping ErrUnknownStrategy, if no strategy has that name.
+//
+// Example:
+//
+//	s, err := ByName("expectimax", 0)
+//	m, err := match.NewMatch(playerA, playerB, match.WithStrategies(s, nil))
+func ByName(name string, seed int64) (match.Strategy, error) {
+	switch name {
+	case "random":
+		return NewRandom(seed), nil
+	case "greedy":
+		return NewGreedy(), nil
+	case "cautious":
+		return NewCautious(), nil
+	case "expectimax":
+		return NewExpectimax(), nil
+	}
+	return nil, fmt.Errorf("%w %q", ErrUnknownStrategy, name)
+}
+
+// Random picks one of the possible actions at random, each as likely as the others.
+type Random struct {
+	rng *rand.Rand // rng is the source of the random choices.
+}
+
+// NewRandom creates the random strategy, seeded so that the same seed makes the same choices.
+//
+// Parameters:
+//   - seed: The seed of the random choices.
+//
+// Returns:
+//   - *Random: A pointer to the strategy.
+func NewRandom(seed int64) *Random {
+	return &Random{rng: rand.New(rand.NewSource(seed))}
+}
+
+// Choose picks a random action among those the player can take.
+//
+// Parameters:
+//   - turn: The state of the match at the start of the turn.
+//
+// Returns:
+//   - match.Action: The action to take.
+func (r *Random) Choose(turn match.Turn) match.Action {
+	actions := turn.Actions()
+	return actions[r.rng.Intn(len(actions))]
+}
+
+// Greedy picks the action dealing the most damage on average, attacking when no action deals more.
+type Greedy struct{}
+
+// NewGreedy creates the greedy strategy.
+//
+// Returns:
+//   - *Greedy: A pointer to the strategy.
+func NewGreedy() *Greedy {
+	return &Greedy{}
+}
+
+// Choose picks the action with the highest expected damage to the opponent.
+//
+// Parameters:
+//   - turn: The state of the match at the start of the turn.
+//
+// Returns:
+//   - match.Action: The action to take.
+func (g *Greedy) Choose(turn match.Turn) match.Action {
+	return best(turn, expectedDamage)
+}
+
+// Cautious heals when their health falls below a threshold, with whichever of the heal action and
+// the spells restores the most health, and otherwise plays like Greedy. It heals at most MaxHeals
+// times, so that two cautious players who barely hurt each other cannot keep healing forever; the
+// heals are counted across every turn it chooses for, so each match needs its own Cautious.
+type Cautious struct {
+	Threshold int // Threshold is the percentage of their starting health below which the player heals.
+	MaxHeals  int // MaxHeals is the number of times the player heals, after which they play like Greedy.
+	heals     int // heals is the number of times the player has healed so far.
+}
+
+// NewCautious creates the cautious strategy, healing below 30% of the starting health up to 5 times.
+//
+// Returns:
+//   - *Cautious: A pointer to the strategy.
+func NewCautious() *Cautious {
+	return &Cautious{Threshold: 30, MaxHeals: 5}
+}
+
+// Choose heals when the player's health is below the threshold and they have heals left, and
+// otherwise picks the action with the highest expected damage to the opponent.
+//
+// Parameters:
+//   - turn: The state of the match at the start of the turn.
+//
+// Returns:
+//   - match.Action: The action to take.
+func (c *Cautious) Choose(turn match.Turn) match.Action {
+	if c.heals < c.MaxHeals && turn.Attacker.Health*100 < turn.Attacker.StartHealth*c.Threshold {
+		if action, healing := bestValue(turn, expectedHealing); healing > 0 {
+			c.heals++
+			return action
+		}
+	}
+	return best(turn, expectedDamage)
+}
+
+// best picks the possible action with the highest expected value of some measure of a turn,
+// the first one on ties.
+//
+// Parameters:
+//   - turn: The state of the match at the start of the turn.
+//   - measure: The measure of a way the turn can play out.
+//
+// Returns:
+//   - match.Action: The action with the highest expected measure.
+func best(turn match.Turn, measure func(match.Branch) float64) match.Action {
+	action, _ := bestValue(turn, measure)
+	return action
+}
+
+// bestValue picks the possible action with the highest expected value of some measure of a turn,
+// the first one on ties, together with that value.
+//
+// Parameters:
+//   - turn: The state of the match at the start of the turn.
+//   - measure: The measure of a way the turn can play out.
+//
+// Returns:
+//   - match.Action: The action with the highest expected measure.
+//   - float64: The expected measure of the action.
+func bestValue(turn match.Turn, measure func(match.Branch) float64) (match.Action, float64) {
+	var bestAction match.Action
+	bestValue := -1.0
+	for _, action := range turn.Actions() {
+		branches, _ := turn.Branches(action)
+		value := 0.0
+		for _, branch := range branches {
+			value += branch.Probability * measure(branch)
+		}
+		if value > bestValue {
+			bestAction, bestValue = action, value
+		}
+	}
+	return bestAction, bestValue
+}
+
+// expectedDamage measures a way a turn can play out by the health the opponent lost.
+//
+// Parameters:
+//   - branch: The way the turn plays out.
+//
+// Returns:
+//   - float64: The health the opponent lost.
+func expectedDamage(branch match.Branch) float64 {
+	return float64(branch.Event.DefenderHealthBefore - branch.Event.DefenderHealthAfter)
+}
+
+// expectedHealing measures a way a turn can play out by the health the player recovered.
+//
+// Parameters:
+//   - branch: The way the turn plays out.
+//
+// Returns:
+//   - float64: The health the player recovered.
+func expectedHealing(branch match.Branch) float64 {
+	return float64(branch.Event.Healing)
+}
@@ -0,0 +1,273 @@
+package strategy
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"proj/pkg/match"
+	"proj/pkg/player"
+	"reflect"
+	"testing"
+)
+
+// ANSI escape codes for text color
+const (
+	redColor   = "\033[31m"
+	greenColor = "\033[32m"
+	resetColor = "\033[0m"
+)
+
+// mageTurn returns the first turn of a mage with 20 mana against a knight whose strength stops
+// every attack, with the mage at the given health.
+func mageTurn(health int) match.Turn {
+	return match.Turn{
+		Round:    1,
+		Attacker: match.FighterState{Name: "Mage", Health: health, StartHealth: 100, Strength: 10, Attack: 10, Mana: 20, MaxMana: 20},
+		Defender: match.FighterState{Name: "Knight", Health: 100, StartHealth: 100, Strength: 60, Attack: 10},
+		Rules:    match.DefaultRules(),
+	}
+}
+
+// TestGreedy tests the greedy strategy.
+//
+// Test scenarios:
+//  1. Against an opponent their attacks cannot hurt, a mage casts fireball, which ignores strength.
+//  2. A player without mana against an opponent they can hurt attacks.
+func TestGreedy(t *testing.T) {
+	greedy := NewGreedy()
+
+	//TEST 1: fireball through strength
+	if action := greedy.Choose(mageTurn(100)); action != (match.Action{Kind: match.ActionCast, Spell: match.SpellFireball}) {
+		t.Errorf(redColor+"Expected a fireball, got %v"+resetColor, action)
+	} else {
+		fmt.Println(greenColor + "TestGreedy : Test1 : Passed" + resetColor)
+	}
+
+	//TEST 2: plain attack
+	turn := mageTurn(100)
+	turn.Attacker.Mana, turn.Defender.Strength = 0, 5
+	if action := greedy.Choose(turn); action != (match.Action{Kind: match.ActionAttack}) {
+		t.Errorf(redColor+"Expected an attack, got %v"+resetColor, action)
+	} else {
+		fmt.Println(greenColor + "TestGreedy : Test2 : Passed" + resetColor)
+	}
+}
+
+// TestCautious tests the cautious strategy.
+//
+// Test scenarios:
+//  1. Below the threshold, a mage casts heal, which restores more than tending their wounds.
+//  2. Below the threshold without mana, the player tends their wounds.
+//  3. Above the threshold, the player picks the most damaging action like Greedy.
+//  4. Once they have healed MaxHeals times, the player attacks below the threshold too.
+func TestCautious(t *testing.T) {
+	cautious := NewCautious()
+
+	//TEST 1: heal spell
+	if action := cautious.Choose(mageTurn(20)); action != (match.Action{Kind: match.ActionCast, Spell: match.SpellHeal}) {
+		t.Errorf(redColor+"Expected the heal spell, got %v"+resetColor, action)
+	} else {
+		fmt.Println(greenColor + "TestCautious : Test1 : Passed" + resetColor)
+	}
+
+	//TEST 2: heal action
+	turn := mageTurn(20)
+	turn.Attacker.Mana, turn.Defender.Attack = 0, 1
+	if action := cautious.Choose(turn); action != (match.Action{Kind: match.ActionHeal}) {
+		t.Errorf(redColor+"Expected the heal action, got %v"+resetColor, action)
+	} else {
+		fmt.Println(greenColor + "TestCautious : Test2 : Passed" + resetColor)
+	}
+
+	//TEST 3: above the threshold
+	if action := cautious.Choose(mageTurn(30)); action != (match.Action{Kind: match.ActionCast, Spell: match.SpellFireball}) {
+		t.Errorf(redColor+"Expected a fireball, got %v"+resetColor, action)
+	} else {
+		fmt.Println(greenColor + "TestCautious : Test3 : Passed" + resetColor)
+	}
+
+	//TEST 4: out of heals
+	cautious = &Cautious{Threshold: 30, MaxHeals: 2}
+	var actions []match.Action
+	for i := 0; i < 3; i++ {
+		actions = append(actions, cautious.Choose(turn))
+	}
+	heal, attack := match.Action{Kind: match.ActionHeal}, match.Action{Kind: match.ActionAttack}
+	if !reflect.DeepEqual(actions, []match.Action{heal, heal, attack}) {
+		t.Errorf(redColor+"Expected two heals and then an attack, got %v"+resetColor, actions)
+	} else {
+		fmt.Println(greenColor + "TestCautious : Test4 : Passed" + resetColor)
+	}
+}
+
+// TestRandom tests the random strategy.
+//
+// Test scenarios:
+//  1. Every choice is a possible action, and over many turns more than one kind of action is chosen.
+//  2. Two strategies with the same seed make the same choices.
+func TestRandom(t *testing.T) {
+	turn := mageTurn(100)
+	possible := make(map[match.Action]bool)
+	for _, action := range turn.Actions() {
+		possible[action] = true
+	}
+
+	//TEST 1: possible actions
+	random, same := NewRandom(7), NewRandom(7)
+	chosen := make(map[match.Action]bool)
+	passed, repeated := true, true
+	for i := 0; i < 50; i++ {
+		action := random.Choose(turn)
+		chosen[action] = true
+		if !possible[action] {
+			passed = false
+		}
+		if same.Choose(turn) != action {
+			repeated = false
+		}
+	}
+	if !passed || len(chosen) < 2 {
+		t.Errorf(redColor+"Expected varied possible actions, got %v"+resetColor, chosen)
+	} else {
+		fmt.Println(greenColor + "TestRandom : Test1 : Passed" + resetColor)
+	}
+
+	//TEST 2: same seed
+	if !repeated {
+		t.Errorf(redColor + "Expected the same seed to make the same choices" + resetColor)
+	} else {
+		fmt.Println(greenColor + "TestRandom : Test2 : Passed" + resetColor)
+	}
+}
+
+// TestExpectimax tests the expectimax strategy.
+//
+// Test scenarios:
+//  1. A player who can finish their opponent this turn picks an action that is sure to.
+//  2. A hurt player in a match where neither player can hurt the other heals, as nothing else helps.
+//  3. Playing as a mage against the same mage choosing at random, expectimax wins most matches.
+//  4. Matches between two expectimax mages with the same seed play out identically every time.
+func TestExpectimax(t *testing.T) {
+	expectimax := NewExpectimax()
+
+	//TEST 1: finishing blow
+	turn := mageTurn(100)
+	turn.Defender.Health, turn.Defender.Strength = 5, 0
+	action := expectimax.Choose(turn)
+	branches, _ := turn.Branches(action)
+	passed := len(branches) > 0
+	for _, branch := range branches {
+		if branch.Next.Attacker.Health > 0 {
+			passed = false
+		}
+	}
+	if !passed {
+		t.Errorf(redColor+"Expected a finishing blow, got %v"+resetColor, action)
+	} else {
+		fmt.Println(greenColor + "TestExpectimax : Test1 : Passed" + resetColor)
+	}
+
+	//TEST 2: healing instead
+	turn = mageTurn(15)
+	turn.Attacker.Mana, turn.Defender.Attack = 0, 1
+	if action := expectimax.Choose(turn); action != (match.Action{Kind: match.ActionHeal}) {
+		t.Errorf(redColor+"Expected the heal action, got %v"+resetColor, action)
+	} else {
+		fmt.Println(greenColor + "TestExpectimax : Test2 : Passed" + resetColor)
+	}
+
+	//TEST 3: against random choices
+	playerA := player.NewPlayer("Smart", 60, 10, 10, player.WithMana(20))
+	playerB := player.NewPlayer("Lucky", 60, 10, 10, player.WithMana(20))
+	wins := 0
+	for seed := int64(1); seed <= 10; seed++ {
+		m, _ := match.NewMatch(playerA, playerB, match.WithSeed(seed), match.WithStrategies(expectimax, NewRandom(seed)))
+		if _, outcome := match.ConductMatch(m); outcome.Winner == playerA {
+			wins++
+		}
+	}
+	if wins < 7 {
+		t.Errorf(redColor+"Expected expectimax to win at least 7 of 10 matches, won %d"+resetColor, wins)
+	} else {
+		fmt.Println(greenColor + "TestExpectimax : Test3 : Passed" + resetColor)
+	}
+
+	//TEST 4: reproducible choices
+	var first []match.RoundEvent
+	passed = true
+	for i := 0; i < 5; i++ {
+		m, _ := match.NewMatch(playerA, playerB, match.WithSeed(3), match.WithStrategies(expectimax, NewExpectimax()))
+		match.ConductMatch(m)
+		if i == 0 {
+			first = m.Events()
+		} else if !reflect.DeepEqual(m.Events(), first) {
+			passed = false
+		}
+	}
+	if !passed {
+		t.Errorf(redColor+"Expected the same seed to play out the same, first got %v"+resetColor, first)
+	} else {
+		fmt.Println(greenColor + "TestExpectimax : Test4 : Passed" + resetColor)
+	}
+}
+
+// TestMatchesEnd tests that matches between the built-in strategies end.
+//
+// Test scenarios:
+//  1. Under the default rules, which set no round limit, every pair of strategies finishes a
+//     match between two players who barely hurt each other, where healing and defending could
+//     otherwise stall the match forever.
+func TestMatchesEnd(t *testing.T) {
+	//TEST 1: every pair of strategies
+	passed := true
+	for _, nameA := range Names {
+		for _, nameB := range Names {
+			strategyA, _ := ByName(nameA, 1)
+			strategyB, _ := ByName(nameB, 2)
+			playerA, playerB := player.NewPlayer("A", 100, 10, 3), player.NewPlayer("B", 100, 10, 3)
+			m, _ := match.NewMatch(playerA, playerB, match.WithSeed(1), match.WithStrategies(strategyA, strategyB))
+			if _, outcome := match.ConductMatch(m); !m.Over() || outcome.Rounds > match.SafetyRounds {
+				t.Errorf(redColor+"Expected %s against %s to end within %d rounds, got %d"+resetColor, nameA, nameB, match.SafetyRounds, outcome.Rounds)
+				passed = false
+			}
+		}
+	}
+	if passed {
+		fmt.Println(greenColor + "TestMatchesEnd : Test1 : Passed" + resetColor)
+	}
+}
+
+// TestByName tests looking up the built-in strategies by name.
+//
+// Test scenarios:
+//  1. Every name in Names gives a strategy.
+//  2. An unknown name gives ErrUnknownStrategy.
+func TestByName(t *testing.T) {
+	//TEST 1: built-in names
+	passed := true
+	for _, name := range Names {
+		if s, err := ByName(name, 1); s == nil || err != nil {
+			passed = false
+		}
+	}
+	if !passed {
+		t.Errorf(redColor+"Expected every one of %v to give a strategy"+resetColor, Names)
+	} else {
+		fmt.Println(greenColor + "TestByName : Test1 : Passed" + resetColor)
+	}
+
+	//TEST 2: unknown name
+	if _, err := ByName("minimax", 1); !errors.Is(err, ErrUnknownStrategy) {
+		t.Errorf(redColor+"Expected ErrUnknownStrategy, got %v"+resetColor, err)
+	} else {
+		fmt.Println(greenColor + "TestByName : Test2 : Passed" + resetColor)
+	}
+}
+
+// TestMain runs the main testing suite.
+func TestMain(m *testing.M) {
+	fmt.Println("Testing strategy package...")
+	Result := m.Run()
+	fmt.Println("Testing complete.")
+	os.Exit(Result)
+}
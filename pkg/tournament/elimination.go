@@ -0,0 +1,362 @@
+package tournament
+
+import (
+	"math/rand"
+	"proj/pkg/player"
+	"sort"
+)
+
+// Pairing is one match of an elimination bracket. An empty side, -1, is a bye: the other entrant
+// advances without playing.
+type Pairing struct {
+	A      int   // A is the index of the first entrant, or -1 for a bye.
+	B      int   // B is the index of the second entrant, or -1 for a bye.
+	Winner int   // Winner is the index of the entrant who advances, or -1 if both sides are empty.
+	Loser  int   // Loser is the index of the entrant who lost, or -1 if no match was played.
+	Game   *Game // Game is the match played, or nil for a bye.
+}
+
+// Bracket is the outcome of an elimination tournament. The entrants are in seed order: entrant 0
+// is the top seed.
+type Bracket struct {
+	Result
+	Entrants []*player.Player // Entrants are the players of the tournament, in seed order.
+	Double   bool             // Double is true for a double elimination bracket.
+	Winners  [][]Pairing      // Winners holds the pairings of every round of the winners bracket.
+	Losers   [][]Pairing      // Losers holds the pairings of every round of the losers bracket, for double elimination.
+	Finals   []Pairing        // Finals holds the grand final and, if the winners bracket champion lost it, the deciding rematch.
+	Champion int              // Champion is the index of the winner of the tournament.
+}
+
+// SeedByRating orders entrants by rating, highest first, for seeding a bracket. Entrants with
+// equal ratings keep their relative order.
+//
+// Parameters:
+//   - entrants: The entrants.
+//   - ratings: The rating of each entrant, in the same order.
+//
+// Returns:
+//   - []*player.Player: The entrants in seed order.
+func SeedByRating(entrants []*player.Player, ratings []float64) []*player.Player {
+	order := make([]int, len(entrants))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return ratings[order[i]] > ratings[order[j]]
+	})
+
+	seeded := make([]*player.Player, len(entrants))
+	for i, index := range order {
+		seeded[i] = entrants[index]
+	}
+	return seeded
+}
+
+// SeedRandomly shuffles entrants into a random seed order, reproducible from the seed.
+//
+// Parameters:
+//   - entrants: The entrants.
+//   - seed: The seed of the shuffle.
+//
+// Returns:
+//   - []*player.Player: The entrants in seed order.
+func SeedRandomly(entrants []*player.Player, seed int64) []*player.Player {
+	seeded := append([]*player.Player(nil), entrants...)
+	rand.New(rand.NewSource(seed)).Shuffle(len(seeded), func(i, j int) {
+		seeded[i], seeded[j] = seeded[j], seeded[i]
+	})
+	return seeded
+}
+
+// bracketPositions returns the seed placed at each position of the first round of a bracket of
+// the given size, a power of two, so that the top seeds can only meet in the latest rounds.
+//
+// Parameters:
+//   - size: The number of positions in the first round.
+//
+// Returns:
+//   - []int: The 0-based seed at each position, e.g. [0 7 3 4 1 6 2 5] for a size of 8.
+func bracketPositions(size int) []int {
+	positions := []int{0}
+	for len(positions) < size {
+		next := make([]int, 0, 2*len(positions))
+		for _, seed := range positions {
+			next = append(next, seed, 2*len(positions)-1-seed)
+		}
+		positions = next
+	}
+	return positions
+}
+
+// SingleElimination conducts a single elimination tournament: entrants who lose a match are out,
+// and the last one standing is the champion.
+//
+// The bracket is the next power of two in size. The missing entrants are byes, which fall to the
+// top seeds. A match ending in a draw is won by the higher seed.
+//
+// Parameters:
+//   - entrants: The entrants, in seed order; see SeedByRating and SeedRandomly.
+//   - cfg: The configuration of the matches.
+//
+// Returns:
+//   - *Bracket: A pointer to the completed bracket.
+//   - error: ErrTooFewEntrants, an error returned by match.NewMatch if two entrants cannot meet,
+//     or an error returned by Config.OnMatch.
+//
+// Example:
+//
+//	bracket, err := SingleElimination(SeedRandomly(players, 7), Config{Rules: match.DefaultRules()})
+//	bracket.Render(os.Stdout)
+func SingleElimination(entrants []*player.Player, cfg Config) (*Bracket, error) {
+	return eliminate(entrants, false, cfg)
+}
+
+// DoubleElimination conducts a double elimination tournament: entrants who lose in the winners
+// bracket drop into the losers bracket, and entrants who lose there are out. The winners bracket
+// champion meets the losers bracket champion in the grand final; if the losers bracket champion
+// wins it, both have lost once and a deciding rematch is played.
+//
+// Byes and draws are handled as in SingleElimination.
+//
+// Parameters:
+//   - entrants: The entrants, in seed order; see SeedByRating and SeedRandomly.
+//   - cfg: The configuration of the matches.
+//
+// Returns:
+//   - *Bracket: A pointer to the completed bracket.
+//   - error: ErrTooFewEntrants, an error returned by match.NewMatch if two entrants cannot meet,
+//     or an error returned by Config.OnMatch.
+func DoubleElimination(entrants []*player.Player, cfg Config) (*Bracket, error) {
+	return eliminate(entrants, true, cfg)
+}
+
+// eliminator plays the rounds of an elimination bracket.
+type eliminator struct {
+	*runner
+	stage      int   // stage counts the rounds played across all brackets, numbering Game.Round.
+	eliminated []int // eliminated holds the stage at which each entrant was knocked out, 0 while still in.
+}
+
+// eliminate conducts a single or double elimination tournament.
+//
+// Parameters:
+//   - entrants: The entrants, in seed order.
+//   - double: true for double elimination, false for single elimination.
+//   - cfg: The configuration of the matches.
+//
+// Returns:
+//   - *Bracket: A pointer to the completed bracket.
+//   - error: An error; see SingleElimination.
+func eliminate(entrants []*player.Player, double bool, cfg Config) (*Bracket, error) {
+	r, err := newRunner(entrants, cfg)
+	if err != nil {
+		return nil, err
+	}
+	e := &eliminator{runner: r, eliminated: make([]int, len(entrants))}
+	b := &Bracket{Entrants: entrants, Double: double}
+
+	size := 1
+	for size < len(entrants) {
+		size *= 2
+	}
+	alive := bracketPositions(size)
+	for i, seed := range alive {
+		if seed >= len(entrants) {
+			alive[i] = -1
+		}
+	}
+
+	// Losers of the winners bracket waiting to drop into the losers bracket, and the entrants
+	// still alive there.
+	var dropped, surviving []int
+	for len(alive) > 1 {
+		round, err := e.playRound(alive, nil, !double)
+		if err != nil {
+			return nil, err
+		}
+		b.Winners = append(b.Winners, round)
+		alive = winners(round)
+		if !double {
+			continue
+		}
+
+		dropped = losers(round)
+		if len(b.Winners) == 1 {
+			// The first losers round pairs the first round's losers with each other.
+			if len(dropped) == 1 {
+				surviving = dropped
+				continue
+			}
+			round, err = e.playRound(dropped, nil, true)
+			if err != nil {
+				return nil, err
+			}
+			b.Losers = append(b.Losers, round)
+			surviving = winners(round)
+			continue
+		}
+
+		// Later losers rounds first meet the survivors with the new drop-ins, reversing the order
+		// every other round so entrants who just met do not meet again straight away, then pair
+		// the survivors with each other, until one entrant is left to meet the next drop-ins.
+		if len(b.Winners)%2 == 0 {
+			for i, j := 0, len(dropped)-1; i < j; i, j = i+1, j-1 {
+				dropped[i], dropped[j] = dropped[j], dropped[i]
+			}
+		}
+		round, err = e.playRound(surviving, dropped, true)
+		if err != nil {
+			return nil, err
+		}
+		b.Losers = append(b.Losers, round)
+		surviving = winners(round)
+		if len(surviving) > 1 {
+			round, err = e.playRound(surviving, nil, true)
+			if err != nil {
+				return nil, err
+			}
+			b.Losers = append(b.Losers, round)
+			surviving = winners(round)
+		}
+	}
+
+	b.Champion = alive[0]
+	if double {
+		final, err := e.playRound([]int{alive[0], surviving[0]}, nil, false)
+		if err != nil {
+			return nil, err
+		}
+		b.Finals = append(b.Finals, final...)
+		b.Champion = final[0].Winner
+		if final[0].Winner != alive[0] && final[0].Loser != -1 {
+			// The winners bracket champion has now lost once too, so the rematch decides it.
+			rematch, err := e.playRound([]int{final[0].Winner, final[0].Loser}, nil, true)
+			if err != nil {
+				return nil, err
+			}
+			b.Finals = append(b.Finals, rematch...)
+			b.Champion = rematch[0].Winner
+		} else if final[0].Loser != -1 {
+			e.eliminated[final[0].Loser] = e.stage
+		}
+	}
+
+	b.Games = e.games
+	b.Standings = e.rank(b.Champion)
+	return b, nil
+}
+
+// playRound plays one round of a bracket. Without opponents, the entrants are paired with their
+// neighbours: 0 with 1, 2 with 3, and so on. With opponents, entrant i meets opponent i.
+//
+// Parameters:
+//   - entrants: The indices of the entrants, -1 for a bye.
+//   - opponents: The indices of the opponents, or nil to pair the entrants with each other.
+//   - knockout: true if the losers of the round are out of the tournament.
+//
+// Returns:
+//   - []Pairing: The pairings of the round.
+//   - error: An error returned by Config.OnMatch.
+func (e *eliminator) playRound(entrants, opponents []int, knockout bool) ([]Pairing, error) {
+	e.stage++
+	var pairings []Pairing
+	for i := 0; i < len(entrants); i++ {
+		pairing := Pairing{A: entrants[i], B: -1, Winner: entrants[i], Loser: -1}
+		if opponents != nil {
+			pairing.B = opponents[i]
+		} else {
+			i++
+			pairing.B = entrants[i]
+		}
+
+		switch {
+		case pairing.A == -1:
+			pairing.Winner = pairing.B
+		case pairing.B != -1:
+			game, err := e.play(e.stage, pairing.A, pairing.B)
+			if err != nil {
+				return nil, err
+			}
+			pairing.Game = &game
+			pairing.Winner = game.Winner()
+			if pairing.Winner == -1 {
+				// A draw goes to the higher seed, who has the lower index.
+				pairing.Winner = min(pairing.A, pairing.B)
+			}
+			pairing.Loser = pairing.A + pairing.B - pairing.Winner
+			if knockout {
+				e.eliminated[pairing.Loser] = e.stage
+			}
+		}
+		pairings = append(pairings, pairing)
+	}
+	return pairings, nil
+}
+
+// rank orders the standings by how far each entrant went: the champion first, then entrants
+// knocked out later ahead of those knocked out earlier, then by points and damage differential.
+//
+// Parameters:
+//   - champion: The index of the champion.
+//
+// Returns:
+//   - []Standing: The ranked standings.
+func (e *eliminator) rank(champion int) []Standing {
+	order := make([]int, len(e.entrants))
+	for i := range order {
+		order[i] = i
+	}
+	lasted := func(i int) int {
+		if i == champion {
+			return e.stage + 1
+		}
+		return e.eliminated[i]
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := e.standings[order[i]], e.standings[order[j]]
+		if lasted(order[i]) != lasted(order[j]) {
+			return lasted(order[i]) > lasted(order[j])
+		}
+		if a.Points != b.Points {
+			return a.Points > b.Points
+		}
+		return a.DamageDifferential() > b.DamageDifferential()
+	})
+
+	standings := make([]Standing, len(order))
+	for i, index := range order {
+		standings[i] = e.standings[index]
+	}
+	return standings
+}
+
+// winners returns the entrant advancing from each pairing of a round.
+//
+// Parameters:
+//   - round: The pairings of the round.
+//
+// Returns:
+//   - []int: The index of each advancing entrant, -1 where both sides were empty.
+func winners(round []Pairing) []int {
+	advancing := make([]int, len(round))
+	for i, pairing := range round {
+		advancing[i] = pairing.Winner
+	}
+	return advancing
+}
+
+// losers returns the entrant who lost each pairing of a round.
+//
+// Parameters:
+//   - round: The pairings of the round.
+//
+// Returns:
+//   - []int: The index of each losing entrant, -1 where no match was played.
+func losers(round []Pairing) []int {
+	beaten := make([]int, len(round))
+	for i, pairing := range round {
+		beaten[i] = pairing.Loser
+	}
+	return beaten
+}
@@ -0,0 +1,161 @@
+package tournament
+
+import (
+	"fmt"
+	"proj/pkg/match"
+	"proj/pkg/player"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestSeeding tests placing and ordering the seeds of a bracket.
+//
+// Test scenarios:
+//  1. In a bracket of 8, seed 1 meets seed 8, seed 4 meets seed 5, and seeds 1 and 2 are in
+//     opposite halves.
+//  2. SeedByRating orders entrants by rating, keeping the entry order of equal ratings.
+//  3. SeedRandomly is a permutation of the entrants, reproducible from the seed.
+func TestSeeding(t *testing.T) {
+	//TEST 1: bracket positions
+	if positions := bracketPositions(8); !reflect.DeepEqual(positions, []int{0, 7, 3, 4, 1, 6, 2, 5}) {
+		t.Errorf(redColor+"Expected [0 7 3 4 1 6 2 5], got %v"+resetColor, positions)
+	} else {
+		fmt.Println(greenColor + "TestSeeding : Test1 : Passed" + resetColor)
+	}
+
+	//TEST 2: by rating
+	entrants := newEntrants(4)
+	seeded := SeedByRating(entrants, []float64{1500, 1700, 1500, 1600})
+	if !reflect.DeepEqual(seeded, []*player.Player{entrants[1], entrants[3], entrants[0], entrants[2]}) {
+		t.Errorf(redColor+"Expected Player2, Player4, Player1, Player3, got %v"+resetColor, seeded)
+	} else {
+		fmt.Println(greenColor + "TestSeeding : Test2 : Passed" + resetColor)
+	}
+
+	//TEST 3: random
+	first, second := SeedRandomly(entrants, 5), SeedRandomly(entrants, 5)
+	seen := map[*player.Player]bool{}
+	for _, p := range first {
+		seen[p] = true
+	}
+	if !reflect.DeepEqual(first, second) || len(seen) != 4 {
+		t.Errorf(redColor+"Expected a reproducible permutation, got %v and %v"+resetColor, first, second)
+	} else {
+		fmt.Println(greenColor + "TestSeeding : Test3 : Passed" + resetColor)
+	}
+}
+
+// TestSingleElimination tests single elimination brackets.
+//
+// Test scenarios:
+//  1. With 5 entrants, the bracket has 3 rounds, the top 3 seeds get byes, and 4 games are played.
+//  2. The champion won every game and ranks first; every other entrant lost exactly once.
+//  3. The bracket renders as a tree ending with the champion.
+func TestSingleElimination(t *testing.T) {
+	bracket, err := SingleElimination(newEntrants(5), Config{Rules: match.DefaultRules(), Seed: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	//TEST 1: byes
+	byes := 0
+	for _, pairing := range bracket.Winners[0] {
+		if pairing.B == -1 && pairing.A <= 2 && pairing.Winner == pairing.A {
+			byes++
+		}
+	}
+	if len(bracket.Winners) != 3 || byes != 3 || len(bracket.Games) != 4 {
+		t.Errorf(redColor+"Expected 3 rounds, 3 byes and 4 games, got %d, %d and %d"+resetColor, len(bracket.Winners), byes, len(bracket.Games))
+	} else {
+		fmt.Println(greenColor + "TestSingleElimination : Test1 : Passed" + resetColor)
+	}
+
+	//TEST 2: champion and eliminations
+	passed := bracket.Standings[0].Player == bracket.Entrants[bracket.Champion] && bracket.Standings[0].Losses == 0 &&
+		bracket.Winners[2][0].Winner == bracket.Champion
+	for _, s := range bracket.Standings[1:] {
+		passed = passed && s.Losses == 1
+	}
+	if !passed {
+		t.Errorf(redColor+"Expected an unbeaten champion and one loss for everyone else, got %+v"+resetColor, bracket.Standings)
+	} else {
+		fmt.Println(greenColor + "TestSingleElimination : Test2 : Passed" + resetColor)
+	}
+
+	//TEST 3: rendering
+	players := []*player.Player{player.NewPlayer("Hero", 100, 10, 5), player.NewPlayer("Villain", 50, 5, 2), player.NewPlayer("Rogue", 60, 5, 8)}
+	small, err := SingleElimination(players, Config{Rules: match.DefaultRules(), Seed: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out strings.Builder
+	expected := "(1) Hero ────┐\n" +
+		"             ├─ Hero ────────┐\n" +
+		"bye ─────────┘               │\n" +
+		"                             ├─ Rogue\n" +
+		"(2) Villain ─┐               │\n" +
+		"             ├─ Rogue ───────┘\n" +
+		"(3) Rogue ───┘\n" +
+		"\n" +
+		"Champion: (3) Rogue\n"
+	if err := small.Render(&out); err != nil || out.String() != expected {
+		t.Errorf(redColor+"Expected the bracket\n%s\ngot\n%s"+resetColor, expected, out.String())
+	} else {
+		fmt.Println(greenColor + "TestSingleElimination : Test3 : Passed" + resetColor)
+	}
+}
+
+// TestDoubleElimination tests double elimination brackets.
+//
+// Test scenarios:
+//  1. For 2 to 9 entrants, everyone but the champion is knocked out with exactly two losses,
+//     the champion lost at most once, and every entrant drops from the winners bracket once.
+//  2. The grand final is a rematch only if the losers bracket champion won the first final.
+//  3. Every bracket match is a conducted match.Match passed to OnMatch.
+func TestDoubleElimination(t *testing.T) {
+	passedLosses, passedFinals := true, true
+	seen, games := 0, 0
+	cfg := Config{Rules: match.DefaultRules(), Seed: 11, OnMatch: func(m *match.Match) error {
+		if m.Outcome().Rounds > 0 {
+			seen++
+		}
+		return nil
+	}}
+	for n := 2; n <= 9; n++ {
+		bracket, err := DoubleElimination(newEntrants(n), cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		games += len(bracket.Games)
+
+		//TEST 1: two losses each
+		for _, s := range bracket.Standings[1:] {
+			passedLosses = passedLosses && s.Losses == 2
+		}
+		passedLosses = passedLosses && bracket.Standings[0].Player == bracket.Entrants[bracket.Champion] && bracket.Standings[0].Losses <= 1
+
+		//TEST 2: bracket reset
+		winnersChampion := bracket.Winners[len(bracket.Winners)-1][0].Winner
+		reset := bracket.Finals[0].Winner != winnersChampion
+		passedFinals = passedFinals && (len(bracket.Finals) == 2) == reset
+	}
+
+	if !passedLosses {
+		t.Errorf(redColor + "Expected everyone but the champion to be knocked out with two losses" + resetColor)
+	} else {
+		fmt.Println(greenColor + "TestDoubleElimination : Test1 : Passed" + resetColor)
+	}
+	if !passedFinals {
+		t.Errorf(redColor + "Expected a rematch exactly when the losers bracket champion won the final" + resetColor)
+	} else {
+		fmt.Println(greenColor + "TestDoubleElimination : Test2 : Passed" + resetColor)
+	}
+
+	//TEST 3: every game is a conducted match
+	if seen != games {
+		t.Errorf(redColor+"Expected OnMatch to see all %d conducted matches, saw %d"+resetColor, games, seen)
+	} else {
+		fmt.Println(greenColor + "TestDoubleElimination : Test3 : Passed" + resetColor)
+	}
+}
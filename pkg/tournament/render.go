@@ -0,0 +1,180 @@
+package tournament
+
+import (
+	"fmt"
+	"io"
+	"proj/pkg/player"
+	"strings"
+	"unicode/utf8"
+)
+
+// Render draws the bracket for a terminal: the winners bracket as a tree, followed by the rounds
+// of the losers bracket and the grand final of a double elimination bracket, and the champion.
+//
+// Parameters:
+//   - w: The writer to draw the bracket to.
+//
+// Returns:
+//   - error: An error returned by the writer.
+//
+// Example output for a single elimination bracket of three entrants:
+//
+//	(1) Hero ────┐
+//	             ├─ Hero ────────┐
+//	bye ─────────┘               │
+//	                             ├─ Rogue
+//	(2) Villain ─┐               │
+//	             ├─ Rogue ───────┘
+//	(3) Rogue ───┘
+//
+//	Champion: (3) Rogue
+func (b *Bracket) Render(w io.Writer) error {
+	var sb strings.Builder
+	if b.Double {
+		sb.WriteString("Winners bracket\n")
+	}
+	b.renderTree(&sb)
+
+	if b.Double {
+		for i, round := range b.Losers {
+			fmt.Fprintf(&sb, "\nLosers bracket, round %d\n", i+1)
+			for _, pairing := range round {
+				if pairing.Winner != -1 {
+					sb.WriteString("  " + b.describe(pairing) + "\n")
+				}
+			}
+		}
+		sb.WriteString("\nGrand final\n")
+		for _, pairing := range b.Finals {
+			sb.WriteString("  " + b.describe(pairing) + "\n")
+		}
+	}
+	fmt.Fprintf(&sb, "\nChampion: %s\n", b.label(b.Champion))
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// renderTree draws the winners bracket as a tree, one column per round.
+//
+// Parameters:
+//   - sb: The builder to draw the tree to.
+func (b *Bracket) renderTree(sb *strings.Builder) {
+	// columns holds the labels of the entrants in every round, ending with the winner.
+	columns := [][]string{{}}
+	for _, pairing := range b.Winners[0] {
+		columns[0] = append(columns[0], b.label(pairing.A), b.label(pairing.B))
+	}
+	for _, round := range b.Winners {
+		var column []string
+		for _, pairing := range round {
+			column = append(column, b.name(pairing.Winner))
+		}
+		columns = append(columns, column)
+	}
+
+	width := 0
+	for _, column := range columns {
+		for _, label := range column {
+			width = max(width, utf8.RuneCountInString(label))
+		}
+	}
+	width += 2
+
+	// Every entrant of the first round gets every other line; the entrants of later rounds sit
+	// halfway between the two entrants of the pairing they won.
+	rows := make([][]int, len(columns))
+	for i := range columns[0] {
+		rows[0] = append(rows[0], 2*i)
+	}
+	for c := 1; c < len(columns); c++ {
+		for i := range columns[c] {
+			rows[c] = append(rows[c], (rows[c-1][2*i]+rows[c-1][2*i+1])/2)
+		}
+	}
+
+	height := 2*len(columns[0]) - 1
+	grid := make([][]rune, height)
+	for i := range grid {
+		grid[i] = []rune(strings.Repeat(" ", len(columns)*(width+3)))
+	}
+	put := func(row, col int, s string) {
+		copy(grid[row][col:], []rune(s))
+	}
+
+	for c, column := range columns {
+		x := c * (width + 3)
+		last := c == len(columns)-1
+		for i, label := range column {
+			if c > 0 {
+				put(rows[c][i], x-2, "─ ")
+			}
+			if last {
+				put(rows[c][i], x, label)
+				continue
+			}
+			put(rows[c][i], x, label+" "+strings.Repeat("─", width-utf8.RuneCountInString(label)-1))
+		}
+		if last {
+			continue
+		}
+
+		// Join each pair of entrants to the line of the entrant who advanced.
+		for i := 0; i < len(column); i += 2 {
+			top, bottom, mid := rows[c][i], rows[c][i+1], rows[c+1][i/2]
+			put(top, x+width, "┐")
+			put(bottom, x+width, "┘")
+			for row := top + 1; row < bottom; row++ {
+				put(row, x+width, "│")
+			}
+			put(mid, x+width, "├")
+		}
+	}
+
+	for _, line := range grid {
+		sb.WriteString(strings.TrimRight(string(line), " ") + "\n")
+	}
+}
+
+// describe summarises a pairing of the losers bracket or the grand final.
+//
+// Parameters:
+//   - pairing: The pairing.
+//
+// Returns:
+//   - string: The summary, e.g. "(1) Hero beat (4) Knight in 7 rounds".
+func (b *Bracket) describe(pairing Pairing) string {
+	if pairing.Game == nil {
+		return b.label(pairing.Winner) + " advances with a bye"
+	}
+	return fmt.Sprintf("%s beat %s in %d rounds", b.label(pairing.Winner), b.label(pairing.Loser), pairing.Game.Match.Outcome().Rounds)
+}
+
+// label returns an entrant's name with their seed, or "bye" for an empty slot.
+//
+// Parameters:
+//   - entrant: The index of the entrant, or -1.
+//
+// Returns:
+//   - string: The label, e.g. "(1) Hero".
+func (b *Bracket) label(entrant int) string {
+	if entrant == -1 {
+		return "bye"
+	}
+	return fmt.Sprintf("(%d) %s", entrant+1, b.name(entrant))
+}
+
+// name returns an entrant's name, or "bye" for an empty slot.
+//
+// Parameters:
+//   - entrant: The index of the entrant, or -1.
+//
+// Returns:
+//   - string: The name.
+func (b *Bracket) name(entrant int) string {
+	if entrant == -1 {
+		return "bye"
+	}
+	name, _, _, _ := player.GetPlayerBaseAttributes(b.Entrants[entrant])
+	return name
+}
@@ -0,0 +1,116 @@
+package tournament
+
+import (
+	"proj/pkg/player"
+	"sort"
+)
+
+// Schedule pairs every entrant with every other entrant using the circle method, so that in each
+// round every entrant plays at most once. With an odd number of entrants, one entrant sits out
+// each round. In a double round-robin, every pairing is played a second time with the players
+// swapped, in the rounds following the first cycle.
+//
+// Parameters:
+//   - n: The number of entrants.
+//   - double: true for a double round-robin, false for a single one.
+//
+// Returns:
+//   - [][][2]int: The pairings of every round, as indices of Player A and Player B.
+//
+// Example:
+//
+//	for round, pairings := range Schedule(4, false) {
+//		fmt.Println(round+1, pairings) // 1 [[0 3] [1 2]], then 2 [[2 0] [3 1]], then 3 [[0 1] [2 3]]
+//	}
+func Schedule(n int, double bool) [][][2]int {
+	// With an odd count, a placeholder entrant -1 gives its opponent of the round a rest.
+	circle := make([]int, 0, n+1)
+	for i := 0; i < n; i++ {
+		circle = append(circle, i)
+	}
+	if n%2 == 1 {
+		circle = append(circle, -1)
+	}
+	size := len(circle)
+
+	var rounds [][][2]int
+	for round := 0; round < size-1; round++ {
+		var pairings [][2]int
+		for i := 0; i < size/2; i++ {
+			a, b := circle[i], circle[size-1-i]
+			if a == -1 || b == -1 {
+				continue
+			}
+			// Alternate the fixed entrant's side, so no entrant is Player A in every round.
+			if i == 0 && round%2 == 1 {
+				a, b = b, a
+			}
+			pairings = append(pairings, [2]int{a, b})
+		}
+		rounds = append(rounds, pairings)
+
+		// Keep the first entrant fixed and rotate everyone else one place.
+		last := circle[size-1]
+		copy(circle[2:], circle[1:size-1])
+		circle[1] = last
+	}
+
+	if double {
+		for _, pairings := range rounds {
+			swapped := make([][2]int, len(pairings))
+			for i, pairing := range pairings {
+				swapped[i] = [2]int{pairing[1], pairing[0]}
+			}
+			rounds = append(rounds, swapped)
+		}
+	}
+	return rounds
+}
+
+// RoundRobin conducts a round-robin tournament in which every entrant meets every other entrant,
+// twice with the players swapped in a double round-robin.
+//
+// The standings rank entrants by points, then by damage differential, then by wins; entrants
+// still level keep their entry order.
+//
+// Parameters:
+//   - entrants: The entrants of the tournament; their names must be unique.
+//   - double: true for a double round-robin, false for a single one.
+//   - cfg: The configuration of the matches.
+//
+// Returns:
+//   - Result: The games played and the final standings.
+//   - error: ErrTooFewEntrants, an error returned by match.NewMatch if two entrants cannot meet,
+//     or an error returned by Config.OnMatch.
+//
+// Example:
+//
+//	result, err := RoundRobin(players, false, Config{Rules: match.DefaultRules(), Seed: 42})
+//	fmt.Println(result.Standings[0].Name(), "wins the league")
+func RoundRobin(entrants []*player.Player, double bool, cfg Config) (Result, error) {
+	r, err := newRunner(entrants, cfg)
+	if err != nil {
+		return Result{}, err
+	}
+
+	for round, pairings := range Schedule(len(entrants), double) {
+		for _, pairing := range pairings {
+			if _, err := r.play(round+1, pairing[0], pairing[1]); err != nil {
+				return Result{}, err
+			}
+		}
+	}
+
+	standings := append([]Standing(nil), r.standings...)
+	sort.SliceStable(standings, func(i, j int) bool {
+		a, b := standings[i], standings[j]
+		if a.Points != b.Points {
+			return a.Points > b.Points
+		}
+		if a.DamageDifferential() != b.DamageDifferential() {
+			return a.DamageDifferential() > b.DamageDifferential()
+		}
+		return a.Wins > b.Wins
+	})
+	return Result{Games: r.games, Standings: standings}, nil
+}
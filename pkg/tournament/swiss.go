@@ -0,0 +1,262 @@
+package tournament
+
+import (
+	"errors"
+	"math/bits"
+	"proj/pkg/player"
+	"sort"
+)
+
+// ErrInvalidRounds is returned when a Swiss tournament is configured with a negative number of rounds.
+var ErrInvalidRounds = errors.New("number of rounds must not be negative")
+
+// pairingBudget bounds the pairings tried in one round before rematches are allowed, since
+// proving that no pairing without rematches exists can take exponential time in large fields.
+const pairingBudget = 100000
+
+// Swiss conducts a Swiss-system tournament: every round pairs entrants with equal scores, or the
+// closest scores possible, without repeating a pairing, and nobody is eliminated.
+//
+// With an odd number of entrants, the lowest ranked entrant who has not had a bye sits out the
+// round and scores a point. Rematches are only allowed when no pairing without them is found
+// within the pairing budget; the round is then paired greedily, avoiding rematches where it can.
+//
+// The standings rank entrants by points, then by the Buchholz score (the sum of their opponents'
+// points), then by the Sonneborn-Berger score (the sum of the points of the opponents they beat,
+// plus half those of the opponents they drew with), then by damage differential; entrants still
+// level keep their entry order.
+//
+// Parameters:
+//   - entrants: The entrants, in seed order; the seed breaks ties when pairing the first rounds.
+//   - rounds: The number of rounds to play, or 0 for enough rounds to find a single unbeaten
+//     entrant, the base-2 logarithm of the number of entrants rounded up.
+//   - cfg: The configuration of the matches.
+//
+// Returns:
+//   - Result: The games played and the final standings.
+//   - error: ErrTooFewEntrants, ErrInvalidRounds, an error returned by match.NewMatch if two
+//     entrants cannot meet, or an error returned by Config.OnMatch.
+//
+// Example:
+//
+//	result, err := Swiss(players, 0, Config{Rules: match.DefaultRules(), Seed: 42})
+//	WriteStandingsCSV(os.Stdout, result.Standings)
+func Swiss(entrants []*player.Player, rounds int, cfg Config) (Result, error) {
+	if rounds < 0 {
+		return Result{}, ErrInvalidRounds
+	}
+	r, err := newRunner(entrants, cfg)
+	if err != nil {
+		return Result{}, err
+	}
+	if rounds == 0 {
+		rounds = bits.Len(uint(len(entrants) - 1))
+	}
+
+	n := len(entrants)
+	met := make([][]bool, n)
+	for i := range met {
+		met[i] = make([]bool, n)
+	}
+	hadBye := make([]bool, n)
+	timesA := make([]int, n)
+
+	for round := 1; round <= rounds; round++ {
+		order := r.ranking()
+		pairs, bye, ok := pairSwiss(order, met, hadBye, pairingBudget)
+		if !ok {
+			// Either no pairing without rematches exists or the search ran out of budget looking
+			// for one, so pair greedily instead. This keeps rematches few, but not always fewest.
+			pairs, bye = pairGreedy(order, met, hadBye)
+		}
+
+		if bye != -1 {
+			hadBye[bye] = true
+			r.standings[bye].Byes++
+			r.standings[bye].Points++
+		}
+		for _, pair := range pairs {
+			a, b := pair[0], pair[1]
+			// Give the side of Player A to whoever has had it less often.
+			if timesA[b] < timesA[a] {
+				a, b = b, a
+			}
+			timesA[a]++
+			met[a][b], met[b][a] = true, true
+			if _, err := r.play(round, a, b); err != nil {
+				return Result{}, err
+			}
+		}
+	}
+
+	r.tieBreaks()
+	return Result{Games: r.games, Standings: r.rankedStandings()}, nil
+}
+
+// pairSwiss pairs the entrants for one Swiss round without rematches. Entrants are paired in
+// ranking order, each with the highest ranked entrant still available they have not met,
+// backtracking whenever the rest of the field cannot be paired.
+//
+// Parameters:
+//   - order: The indices of the entrants in ranking order.
+//   - met: Whether each pair of entrants has already met.
+//   - hadBye: Whether each entrant has already had a bye.
+//   - budget: The number of pairings that may be tried before the search gives up.
+//
+// Returns:
+//   - [][2]int: The pairings, as entrant indices.
+//   - int: The entrant given a bye, or -1 with an even number of entrants.
+//   - bool: true if a pairing was found, false if none exists or the budget ran out.
+func pairSwiss(order []int, met [][]bool, hadBye []bool, budget int) ([][2]int, int, bool) {
+	if len(order)%2 == 0 {
+		pairs, ok := pairUp(order, met, &budget)
+		return pairs, -1, ok
+	}
+
+	// Try the lowest ranked entrants without a bye first, then, if everyone has had one, anyone.
+	for _, allowRepeat := range []bool{false, true} {
+		for i := len(order) - 1; i >= 0; i-- {
+			if hadBye[order[i]] && !allowRepeat {
+				continue
+			}
+			rest := append(append([]int(nil), order[:i]...), order[i+1:]...)
+			if pairs, ok := pairUp(rest, met, &budget); ok {
+				return pairs, order[i], true
+			}
+		}
+	}
+	return nil, -1, false
+}
+
+// pairGreedy pairs the entrants for one Swiss round, allowing rematches. The lowest ranked entrant
+// who has not had a bye, or the lowest ranked entrant if everyone has had one, sits out with an
+// odd number of entrants. The others are paired in ranking order, each with the highest ranked
+// entrant still available they have not met, or the highest ranked one if they have met them all.
+// The pairing never backtracks, so it may hold more rematches than the fewest possible.
+//
+// Parameters:
+//   - order: The indices of the entrants in ranking order.
+//   - met: Whether each pair of entrants has already met.
+//   - hadBye: Whether each entrant has already had a bye.
+//
+// Returns:
+//   - [][2]int: The pairings, as entrant indices.
+//   - int: The entrant given a bye, or -1 with an even number of entrants.
+func pairGreedy(order []int, met [][]bool, hadBye []bool) ([][2]int, int) {
+	rest := append([]int(nil), order...)
+	bye := -1
+	if len(rest)%2 == 1 {
+		i := len(rest) - 1
+		for j := len(rest) - 1; j >= 0; j-- {
+			if !hadBye[rest[j]] {
+				i = j
+				break
+			}
+		}
+		bye = rest[i]
+		rest = append(rest[:i], rest[i+1:]...)
+	}
+
+	var pairs [][2]int
+	for len(rest) > 0 {
+		first, opponent := rest[0], 1
+		for j := 1; j < len(rest); j++ {
+			if !met[first][rest[j]] {
+				opponent = j
+				break
+			}
+		}
+		pairs = append(pairs, [2]int{first, rest[opponent]})
+		rest = append(rest[1:opponent], rest[opponent+1:]...)
+	}
+	return pairs, bye
+}
+
+// pairUp pairs an even number of entrants, each with the highest ranked available opponent they
+// have not met, backtracking when the remaining entrants cannot be paired.
+//
+// Parameters:
+//   - order: The indices of the entrants in ranking order.
+//   - met: Whether each pair of entrants has already met.
+//   - budget: The number of pairings that may still be tried; the search gives up when it runs out.
+//
+// Returns:
+//   - [][2]int: The pairings, as entrant indices.
+//   - bool: true if every entrant was paired.
+func pairUp(order []int, met [][]bool, budget *int) ([][2]int, bool) {
+	if len(order) == 0 {
+		return nil, true
+	}
+	if *budget <= 0 {
+		return nil, false
+	}
+	*budget--
+
+	first := order[0]
+	for j := 1; j < len(order); j++ {
+		if met[first][order[j]] {
+			continue
+		}
+		rest := append(append([]int(nil), order[1:j]...), order[j+1:]...)
+		if pairs, ok := pairUp(rest, met, budget); ok {
+			return append([][2]int{{first, order[j]}}, pairs...), true
+		}
+	}
+	return nil, false
+}
+
+// ranking returns the indices of the entrants ranked by points, keeping entry order among equal points.
+//
+// Returns:
+//   - []int: The indices of the entrants in ranking order.
+func (r *runner) ranking() []int {
+	order := make([]int, len(r.standings))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return r.standings[order[i]].Points > r.standings[order[j]].Points
+	})
+	return order
+}
+
+// tieBreaks computes the Buchholz and Sonneborn-Berger scores of every entrant from the games
+// played and the final points.
+func (r *runner) tieBreaks() {
+	for _, game := range r.games {
+		a, b := &r.standings[game.A], &r.standings[game.B]
+		a.Buchholz += b.Points
+		b.Buchholz += a.Points
+		switch game.Winner() {
+		case game.A:
+			a.SonnebornBerger += b.Points
+		case game.B:
+			b.SonnebornBerger += a.Points
+		default:
+			a.SonnebornBerger += b.Points / 2
+			b.SonnebornBerger += a.Points / 2
+		}
+	}
+}
+
+// rankedStandings returns the standings ranked by points, Buchholz score, Sonneborn-Berger score
+// and damage differential, keeping entry order among entrants still level.
+//
+// Returns:
+//   - []Standing: The ranked standings.
+func (r *runner) rankedStandings() []Standing {
+	standings := append([]Standing(nil), r.standings...)
+	sort.SliceStable(standings, func(i, j int) bool {
+		a, b := standings[i], standings[j]
+		switch {
+		case a.Points != b.Points:
+			return a.Points > b.Points
+		case a.Buchholz != b.Buchholz:
+			return a.Buchholz > b.Buchholz
+		case a.SonnebornBerger != b.SonnebornBerger:
+			return a.SonnebornBerger > b.SonnebornBerger
+		}
+		return a.DamageDifferential() > b.DamageDifferential()
+	})
+	return standings
+}
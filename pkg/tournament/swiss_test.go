@@ -0,0 +1,177 @@
+package tournament
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"proj/pkg/match"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestSwiss tests Swiss-system tournaments.
+//
+// Test scenarios:
+//  1. With 8 entrants and the default 3 rounds, 12 games are played without a rematch, and
+//     exactly one entrant finishes unbeaten.
+//  2. With 5 entrants, a different entrant gets the bye in each of the 3 rounds and scores a point.
+//  3. The Buchholz and Sonneborn-Berger scores match the games played, and rank entrants level
+//     on points.
+//  4. With more rounds than opponents, rematches are allowed so the tournament still completes.
+//  5. A negative number of rounds is rejected.
+func TestSwiss(t *testing.T) {
+	cfg := Config{Rules: match.DefaultRules(), Seed: 21}
+
+	//TEST 1: 8 entrants
+	result, err := Swiss(newEntrants(8), 0, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pairings := map[[2]int]bool{}
+	for _, game := range result.Games {
+		pairings[[2]int{min(game.A, game.B), max(game.A, game.B)}] = true
+	}
+	if len(result.Games) != 12 || len(pairings) != 12 || result.Standings[0].Points != 3 || result.Standings[1].Points == 3 {
+		t.Errorf(redColor+"Expected 12 distinct games and one unbeaten entrant, got %d games and %+v"+resetColor, len(result.Games), result.Standings)
+	} else {
+		fmt.Println(greenColor + "TestSwiss : Test1 : Passed" + resetColor)
+	}
+
+	//TEST 2: byes
+	odd, err := Swiss(newEntrants(5), 0, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	byes, points := 0, 0.0
+	passed := len(odd.Games) == 6
+	for _, s := range odd.Standings {
+		byes += s.Byes
+		points += s.Points
+		passed = passed && s.Byes <= 1 && s.Played+s.Byes == 3
+	}
+	if !passed || byes != 3 || points != 9 {
+		t.Errorf(redColor+"Expected 3 different byes worth a point each, got %+v"+resetColor, odd.Standings)
+	} else {
+		fmt.Println(greenColor + "TestSwiss : Test2 : Passed" + resetColor)
+	}
+
+	//TEST 3: tie-breaks
+	pointsOf := map[string]float64{}
+	for _, s := range result.Standings {
+		pointsOf[s.Name()] = s.Points
+	}
+	buchholz, sonnebornBerger := map[string]float64{}, map[string]float64{}
+	for _, game := range result.Games {
+		nameA, nameB := name(game, game.A), name(game, game.B)
+		buchholz[nameA] += pointsOf[nameB]
+		buchholz[nameB] += pointsOf[nameA]
+		switch game.Winner() {
+		case game.A:
+			sonnebornBerger[nameA] += pointsOf[nameB]
+		case game.B:
+			sonnebornBerger[nameB] += pointsOf[nameA]
+		default:
+			sonnebornBerger[nameA] += pointsOf[nameB] / 2
+			sonnebornBerger[nameB] += pointsOf[nameA] / 2
+		}
+	}
+	passed = true
+	for i, s := range result.Standings {
+		passed = passed && s.Buchholz == buchholz[s.Name()] && s.SonnebornBerger == sonnebornBerger[s.Name()]
+		if i > 0 && result.Standings[i-1].Points == s.Points {
+			passed = passed && result.Standings[i-1].Buchholz >= s.Buchholz
+		}
+	}
+	if !passed {
+		t.Errorf(redColor+"Expected tie-breaks matching the games, got %+v"+resetColor, result.Standings)
+	} else {
+		fmt.Println(greenColor + "TestSwiss : Test3 : Passed" + resetColor)
+	}
+
+	//TEST 4: rematches
+	long, err := Swiss(newEntrants(4), 5, cfg)
+	if err != nil || len(long.Games) != 10 {
+		t.Errorf(redColor+"Expected 10 games over 5 rounds, got %d, %v"+resetColor, len(long.Games), err)
+	} else {
+		fmt.Println(greenColor + "TestSwiss : Test4 : Passed" + resetColor)
+	}
+
+	//TEST 5: negative rounds
+	if _, err := Swiss(newEntrants(4), -1, cfg); !errors.Is(err, ErrInvalidRounds) {
+		t.Errorf(redColor+"Expected ErrInvalidRounds, got %v"+resetColor, err)
+	} else {
+		fmt.Println(greenColor + "TestSwiss : Test5 : Passed" + resetColor)
+	}
+}
+
+// TestSwissPairing tests how a Swiss round is paired when rematches cannot be ruled out.
+//
+// Test scenarios:
+//  1. Entrants 0 and 1 have met. A pairing without rematches exists, but the search runs out of
+//     budget before finding it; the greedy fallback still pairs 0 with 2 and 1 with 3.
+//  2. Entrant 0 has met everyone but 3, and 1 has met 2. With 5 entrants, 4 has had a bye, so 3
+//     sits out and the greedy fallback has to pair 0 and 1 with opponents they have met.
+func TestSwissPairing(t *testing.T) {
+	newMet := func(n int, pairs ...[2]int) [][]bool {
+		met := make([][]bool, n)
+		for i := range met {
+			met[i] = make([]bool, n)
+		}
+		for _, pair := range pairs {
+			met[pair[0]][pair[1]], met[pair[1]][pair[0]] = true, true
+		}
+		return met
+	}
+
+	//TEST 1: budget runs out
+	order := []int{0, 1, 2, 3}
+	met := newMet(4, [2]int{0, 1})
+	hadBye := make([]bool, 4)
+	_, _, found := pairSwiss(order, met, hadBye, pairingBudget)
+	_, _, foundOnBudget := pairSwiss(order, met, hadBye, 1)
+	pairs, bye := pairGreedy(order, met, hadBye)
+	if !found || foundOnBudget || bye != -1 || !reflect.DeepEqual(pairs, [][2]int{{0, 2}, {1, 3}}) {
+		t.Errorf(redColor+"Expected the search to give up and pair 0-2 and 1-3, got %v, %v, %v and %d"+resetColor, found, foundOnBudget, pairs, bye)
+	} else {
+		fmt.Println(greenColor + "TestSwissPairing : Test1 : Passed" + resetColor)
+	}
+
+	//TEST 2: rematches
+	order = []int{0, 1, 2, 3, 4}
+	met = newMet(5, [2]int{0, 1}, [2]int{0, 2}, [2]int{0, 4}, [2]int{1, 2})
+	hadBye = []bool{false, false, false, false, true}
+	pairs, bye = pairGreedy(order, met, hadBye)
+	if bye != 3 || !reflect.DeepEqual(pairs, [][2]int{{0, 1}, {2, 4}}) {
+		t.Errorf(redColor+"Expected 3 to sit out and pairs 0-1 and 2-4, got %v and %d"+resetColor, pairs, bye)
+	} else {
+		fmt.Println(greenColor + "TestSwissPairing : Test2 : Passed" + resetColor)
+	}
+}
+
+// name returns the name of the entrant with the given index in a game.
+func name(game Game, entrant int) string {
+	p := game.Match.PlayerA
+	if entrant == game.B {
+		p = game.Match.PlayerB
+	}
+	return Standing{Player: p}.Name()
+}
+
+// TestWriteStandingsCSV tests exporting standings as CSV.
+func TestWriteStandingsCSV(t *testing.T) {
+	result, err := Swiss(newEntrants(5), 0, Config{Rules: match.DefaultRules(), Seed: 21})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out strings.Builder
+	if err := WriteStandingsCSV(&out, result.Standings); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(strings.NewReader(out.String())).ReadAll()
+	if err != nil || len(records) != 6 || records[0][1] != "name" || records[1][0] != "1" || records[1][1] != result.Standings[0].Name() {
+		t.Errorf(redColor+"Expected a header and 5 ranked rows, got %v, %v"+resetColor, records, err)
+	} else {
+		fmt.Println(greenColor + "TestWriteStandingsCSV : Test1 : Passed" + resetColor)
+	}
+}
@@ -0,0 +1,230 @@
+package tournament
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"proj/pkg/match"
+	"proj/pkg/player"
+	"strconv"
+)
+
+// ErrTooFewEntrants is returned when a tournament has fewer than two entrants.
+var ErrTooFewEntrants = errors.New("a tournament needs at least 2 entrants")
+
+// Config configures how the matches of a tournament are played.
+type Config struct {
+	Rules match.Rules // Rules are the rules every match is played under, limited to match.SafetyRounds rounds if they set no limit.
+	Seed  int64       // Seed seeds the dice of the first match; every later match uses the next seed.
+	// OnMatch, if set, is called with every match once it is conducted, e.g. to record it in the
+	// match history. An error stops the tournament.
+	OnMatch func(m *match.Match) error
+}
+
+// Game is a match played in a tournament.
+type Game struct {
+	Round int          // Round is the tournament round the match was played in, counting from 1.
+	A     int          // A is the index of Player A among the entrants.
+	B     int          // B is the index of Player B among the entrants.
+	Match *match.Match // Match is the conducted match.
+}
+
+// Result is the outcome of a tournament: every game played and the final standings.
+type Result struct {
+	Games     []Game     // Games are the games played, in the order they were played.
+	Standings []Standing // Standings rank the entrants, best first.
+}
+
+// Standing is the record of one entrant over a tournament.
+type Standing struct {
+	Player      *player.Player `json:"player"`         // Player is the entrant.
+	Played      int            `json:"played"`         // Played is the number of games played, byes excluded.
+	Wins        int            `json:"wins"`           // Wins is the number of games won.
+	Losses      int            `json:"losses"`         // Losses is the number of games lost.
+	Draws       int            `json:"draws"`          // Draws is the number of games drawn.
+	Points      float64        `json:"points"`         // Points scores 1 for a win or a bye and 0.5 for a draw.
+	DamageDealt int            `json:"damageDealt"`    // DamageDealt is the total damage the entrant dealt.
+	DamageTaken int            `json:"damageTaken"`    // DamageTaken is the total damage the entrant took.
+	Byes        int            `json:"byes,omitempty"` // Byes is the number of Swiss rounds the entrant sat out.
+	// Buchholz is the sum of the points of the entrant's opponents, in a Swiss tournament.
+	Buchholz float64 `json:"buchholz,omitempty"`
+	// SonnebornBerger is the sum of the points of the opponents the entrant beat, plus half the
+	// points of the opponents they drew with, in a Swiss tournament.
+	SonnebornBerger float64 `json:"sonnebornBerger,omitempty"`
+}
+
+// Name returns the name of the entrant.
+//
+// Returns:
+//   - string: The name of the entrant.
+func (s Standing) Name() string {
+	name, _, _, _ := player.GetPlayerBaseAttributes(s.Player)
+	return name
+}
+
+// DamageDifferential returns the damage the entrant dealt minus the damage they took.
+//
+// Returns:
+//   - int: The damage differential.
+func (s Standing) DamageDifferential() int {
+	return s.DamageDealt - s.DamageTaken
+}
+
+// validateEntrants checks that every entrant can meet every other entrant in a match under the
+// given rules, so a tournament never stops halfway through on an impossible pairing.
+//
+// Parameters:
+//   - entrants: The entrants of the tournament.
+//   - rules: The rules every match is played under.
+//
+// Returns:
+//   - error: ErrTooFewEntrants, or the error match.NewMatch returns for the first invalid pairing.
+func validateEntrants(entrants []*player.Player, rules match.Rules) error {
+	if len(entrants) < 2 {
+		return ErrTooFewEntrants
+	}
+	for i := range entrants {
+		for j := i + 1; j < len(entrants); j++ {
+			if _, err := match.NewMatch(entrants[i], entrants[j], match.WithRules(rules), match.WithSeed(0)); err != nil {
+				return fmt.Errorf("entrants %d and %d: %w", i+1, j+1, err)
+			}
+		}
+	}
+	return nil
+}
+
+// runner plays the games of a tournament and keeps the record of every entrant.
+type runner struct {
+	cfg       Config           // cfg configures how the matches are played.
+	entrants  []*player.Player // entrants are the players of the tournament.
+	games     []Game           // games are the games played so far.
+	standings []Standing       // standings hold the record of every entrant, in entrant order.
+}
+
+// newRunner creates a runner for a tournament between the given entrants.
+//
+// Parameters:
+//   - entrants: The entrants of the tournament.
+//   - cfg: The configuration of the matches.
+//
+// Returns:
+//   - *runner: A pointer to the runner.
+//   - error: An error, if the entrants cannot all meet each other; see validateEntrants.
+func newRunner(entrants []*player.Player, cfg Config) (*runner, error) {
+	// A single match that never ends would keep the whole tournament from finishing.
+	cfg.Rules = cfg.Rules.WithSafetyLimit()
+	if err := validateEntrants(entrants, cfg.Rules); err != nil {
+		return nil, err
+	}
+	r := &runner{cfg: cfg, entrants: entrants, standings: make([]Standing, len(entrants))}
+	for i, p := range entrants {
+		r.standings[i].Player = p
+	}
+	return r, nil
+}
+
+// play conducts a game between two entrants and adds it to their records.
+//
+// Parameters:
+//   - round: The tournament round of the game.
+//   - a: The index of Player A among the entrants.
+//   - b: The index of Player B among the entrants.
+//
+// Returns:
+//   - Game: The played game.
+//   - error: An error returned by Config.OnMatch.
+func (r *runner) play(round, a, b int) (Game, error) {
+	m, err := match.NewMatch(r.entrants[a], r.entrants[b], match.WithRules(r.cfg.Rules), match.WithSeed(r.cfg.Seed+int64(len(r.games))))
+	if err != nil {
+		return Game{}, err
+	}
+	_, outcome := match.ConductMatch(m)
+	if r.cfg.OnMatch != nil {
+		if err := r.cfg.OnMatch(m); err != nil {
+			return Game{}, err
+		}
+	}
+
+	game := Game{Round: round, A: a, B: b, Match: m}
+	r.games = append(r.games, game)
+
+	standingA, standingB := &r.standings[a], &r.standings[b]
+	standingA.Played++
+	standingB.Played++
+	switch outcome.Winner {
+	case nil:
+		standingA.Draws++
+		standingB.Draws++
+		standingA.Points += 0.5
+		standingB.Points += 0.5
+	case m.PlayerA:
+		standingA.Wins++
+		standingB.Losses++
+		standingA.Points++
+	default:
+		standingB.Wins++
+		standingA.Losses++
+		standingB.Points++
+	}
+
+	nameA, _, _, _ := player.GetPlayerBaseAttributes(m.PlayerA)
+	for _, event := range m.Events() {
+		if event.Attacker == nameA {
+			standingA.DamageDealt += event.Damage
+			standingB.DamageTaken += event.Damage
+		} else {
+			standingB.DamageDealt += event.Damage
+			standingA.DamageTaken += event.Damage
+		}
+	}
+	return game, nil
+}
+
+// Winner returns the index of the entrant who won a game, or -1 for a draw.
+//
+// Returns:
+//   - int: The index of the winner among the entrants, or -1.
+func (g Game) Winner() int {
+	switch g.Match.Outcome().Winner {
+	case nil:
+		return -1
+	case g.Match.PlayerA:
+		return g.A
+	}
+	return g.B
+}
+
+// WriteStandingsCSV exports standings as CSV, with a header row and one row per entrant in rank
+// order, for spreadsheets and league tables.
+//
+// Parameters:
+//   - w: The writer to write the CSV to.
+//   - standings: The ranked standings.
+//
+// Returns:
+//   - error: An error returned by the writer.
+func WriteStandingsCSV(w io.Writer, standings []Standing) error {
+	writer := csv.NewWriter(w)
+	header := []string{"rank", "name", "played", "wins", "losses", "draws", "byes", "points",
+		"buchholz", "sonneborn_berger", "damage_dealt", "damage_taken", "damage_differential"}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for i, s := range standings {
+		record := []string{
+			strconv.Itoa(i + 1), s.Name(),
+			strconv.Itoa(s.Played), strconv.Itoa(s.Wins), strconv.Itoa(s.Losses), strconv.Itoa(s.Draws), strconv.Itoa(s.Byes),
+			strconv.FormatFloat(s.Points, 'f', -1, 64),
+			strconv.FormatFloat(s.Buchholz, 'f', -1, 64),
+			strconv.FormatFloat(s.SonnebornBerger, 'f', -1, 64),
+			strconv.Itoa(s.DamageDealt), strconv.Itoa(s.DamageTaken), strconv.Itoa(s.DamageDifferential()),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
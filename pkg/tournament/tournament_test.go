@@ -0,0 +1,178 @@
+package tournament
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"proj/pkg/match"
+	"proj/pkg/player"
+	"testing"
+)
+
+// ANSI escape codes for text color
+const (
+	redColor   = "\033[31m"
+	greenColor = "\033[32m"
+	resetColor = "\033[0m"
+)
+
+// newEntrants creates n entrants of increasing health, so that later entrants are stronger.
+func newEntrants(n int) []*player.Player {
+	entrants := make([]*player.Player, n)
+	for i := range entrants {
+		entrants[i] = player.NewPlayer(fmt.Sprintf("Player%d", i+1), 40+20*i, 5, 5+i)
+	}
+	return entrants
+}
+
+// TestSchedule tests scheduling round-robin pairings.
+//
+// Test scenarios:
+//  1. For 2 to 9 entrants, every pair meets exactly once, nobody plays twice in a round, and
+//     there are n-1 rounds for an even count and n rounds for an odd count.
+//  2. A double round-robin plays every pairing a second time with the players swapped.
+func TestSchedule(t *testing.T) {
+	//TEST 1: single round-robin
+	passed := true
+	for n := 2; n <= 9; n++ {
+		rounds := Schedule(n, false)
+		expectedRounds := n - 1 + n%2
+		met := map[[2]int]int{}
+		for _, pairings := range rounds {
+			busy := map[int]bool{}
+			for _, p := range pairings {
+				if busy[p[0]] || busy[p[1]] {
+					passed = false
+				}
+				busy[p[0]], busy[p[1]] = true, true
+				met[[2]int{min(p[0], p[1]), max(p[0], p[1])}]++
+			}
+		}
+		if len(rounds) != expectedRounds || len(met) != n*(n-1)/2 {
+			passed = false
+		}
+		for _, count := range met {
+			if count != 1 {
+				passed = false
+			}
+		}
+	}
+	if !passed {
+		t.Errorf(redColor+"Expected every pair to meet once, got %v"+resetColor, Schedule(5, false))
+	} else {
+		fmt.Println(greenColor + "TestSchedule : Test1 : Passed" + resetColor)
+	}
+
+	//TEST 2: double round-robin
+	single, double := Schedule(4, false), Schedule(4, true)
+	if len(double) != 2*len(single) || double[3][0] != [2]int{single[0][0][1], single[0][0][0]} {
+		t.Errorf(redColor+"Expected the second cycle to swap the players, got %v"+resetColor, double)
+	} else {
+		fmt.Println(greenColor + "TestSchedule : Test2 : Passed" + resetColor)
+	}
+}
+
+// TestRoundRobin tests conducting round-robin tournaments.
+//
+// Test scenarios:
+//  1. In a single round-robin of 5 entrants, 10 games are played, every entrant plays 4, and
+//     the records add up: as many wins as losses, and damage dealt equals damage taken.
+//  2. The standings are ranked by points, then damage differential.
+//  3. The same seed reproduces the same tournament, and OnMatch sees every match.
+//  4. A double round-robin plays 20 games.
+//  5. Too few entrants, or two entrants who cannot meet, are rejected before any game is played.
+//  6. Under rules without a round limit, two mages who heal faster than they hurt each other are
+//     stopped after match.SafetyRounds rounds, so the tournament finishes.
+func TestRoundRobin(t *testing.T) {
+	cfg := Config{Rules: match.DefaultRules(), Seed: 7}
+
+	//TEST 1: records add up
+	result, err := RoundRobin(newEntrants(5), false, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wins, losses, dealt, taken := 0, 0, 0, 0
+	passed := len(result.Games) == 10
+	for _, s := range result.Standings {
+		wins += s.Wins
+		losses += s.Losses
+		dealt += s.DamageDealt
+		taken += s.DamageTaken
+		passed = passed && s.Played == 4 && s.Wins+s.Losses+s.Draws == 4
+	}
+	if !passed || wins != losses || dealt != taken {
+		t.Errorf(redColor+"Expected consistent records over 10 games, got %+v"+resetColor, result.Standings)
+	} else {
+		fmt.Println(greenColor + "TestRoundRobin : Test1 : Passed" + resetColor)
+	}
+
+	//TEST 2: ranking
+	passed = true
+	for i := 1; i < len(result.Standings); i++ {
+		prev, cur := result.Standings[i-1], result.Standings[i]
+		if prev.Points < cur.Points || (prev.Points == cur.Points && prev.DamageDifferential() < cur.DamageDifferential()) {
+			passed = false
+		}
+	}
+	if !passed {
+		t.Errorf(redColor+"Expected standings ranked by points and damage differential, got %+v"+resetColor, result.Standings)
+	} else {
+		fmt.Println(greenColor + "TestRoundRobin : Test2 : Passed" + resetColor)
+	}
+
+	//TEST 3: reproducible, OnMatch called
+	seen := 0
+	cfg.OnMatch = func(m *match.Match) error {
+		seen++
+		return nil
+	}
+	again, err := RoundRobin(newEntrants(5), false, cfg)
+	passed = err == nil && seen == 10
+	for i := range result.Standings {
+		passed = passed && again.Standings[i].Name() == result.Standings[i].Name() && again.Standings[i].DamageDealt == result.Standings[i].DamageDealt
+	}
+	if !passed {
+		t.Errorf(redColor+"Expected the same standings and 10 matches seen, got %d and %v"+resetColor, seen, err)
+	} else {
+		fmt.Println(greenColor + "TestRoundRobin : Test3 : Passed" + resetColor)
+	}
+
+	//TEST 4: double round-robin
+	result, err = RoundRobin(newEntrants(5), true, Config{Rules: match.DefaultRules()})
+	if err != nil || len(result.Games) != 20 || result.Standings[0].Played != 8 {
+		t.Errorf(redColor+"Expected 20 games, got %d, %v"+resetColor, len(result.Games), err)
+	} else {
+		fmt.Println(greenColor + "TestRoundRobin : Test4 : Passed" + resetColor)
+	}
+
+	//TEST 5: invalid entrants
+	seen = 0
+	_, errFew := RoundRobin(newEntrants(1), false, cfg)
+	wall := append(newEntrants(3), player.NewPlayer("Wall", 100, 100, 1))
+	_, errWall := RoundRobin(wall, false, cfg)
+	if !errors.Is(errFew, ErrTooFewEntrants) || !errors.Is(errWall, match.ErrAttackCannotPenetrate) || seen != 0 {
+		t.Errorf(redColor+"Expected the entrants to be rejected up front, got %v, %v"+resetColor, errFew, errWall)
+	} else {
+		fmt.Println(greenColor + "TestRoundRobin : Test5 : Passed" + resetColor)
+	}
+
+	//TEST 6: safety round limit
+	mages := []*player.Player{
+		player.NewPlayer("Merlin", 100, 10, 2, player.WithMana(30)),
+		player.NewPlayer("Morgana", 100, 10, 3, player.WithMana(20)),
+	}
+	result, err = RoundRobin(mages, false, Config{Rules: match.DefaultRules(), Seed: 1})
+	if err != nil || len(result.Games) != 1 || result.Games[0].Match.Outcome().Rounds != match.SafetyRounds {
+		t.Errorf(redColor+"Expected a single game stopped after %d rounds, got %+v, %v"+resetColor, match.SafetyRounds, result.Games, err)
+	} else {
+		fmt.Println(greenColor + "TestRoundRobin : Test6 : Passed" + resetColor)
+	}
+}
+
+// TestMain runs the main testing suite.
+func TestMain(m *testing.M) {
+	fmt.Println("Testing tournament package...")
+	Result := m.Run()
+	fmt.Println("Testing complete.")
+	os.Exit(Result)
+}